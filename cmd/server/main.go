@@ -72,17 +72,21 @@ func main() {
 	orderRepo := repository.NewOrderRepository(db)
 	tradeRepo := repository.NewTradeRepository(db)
 	closedPnLRepo := repository.NewClosedPnLRepository(db)
+	fundingRepo := repository.NewFundingRepository(db)
+	symbolSettingRepo := repository.NewSymbolSettingRepository(db)
+	agentWalletRepo := repository.NewAgentWalletRepository(db)
 
 	// Initialize services
 	authService := service.NewAuthService(userRepo, cfg.JWT)
 	accountService := service.NewAccountService(
 		accountRepo,
+		agentWalletRepo,
 		cfg.Encryption,
 		"yourdomain.com", // Base URL for endpoint generation
 	)
 
 	// Initialize price service
-	priceService := service.NewPriceService(rdb)
+	priceService := service.NewPriceService(rdb, cfg.Price)
 
 	// Initialize trading service
 	tradingService := service.NewTradingService(
@@ -91,9 +95,18 @@ func main() {
 		orderRepo,
 		tradeRepo,
 		closedPnLRepo,
+		symbolSettingRepo,
 		priceService,
 	)
 
+	// Initialize funding service (periodic funding settlement and ledger)
+	fundingService := service.NewFundingService(accountRepo, positionRepo, fundingRepo, priceService, tradingService.Events())
+
+	// Initialize matching engine for resting limit orders (fed by live price updates)
+	matchingEngine := service.NewMatchingEngine(tradingService, orderRepo, accountRepo)
+	tradingService.SetMatchingEngine(matchingEngine)
+	priceService.AddSubscriber(matchingEngine)
+
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
 	accountHandler := handler.NewAccountHandler(accountService)
@@ -149,19 +162,21 @@ func main() {
 	go exchangeInfoService.Start(context.Background())
 
 	// Binance compatible routes (/fapi/v1/*, /fapi/v2/*)
-	binanceHandler := exchangeBinance.NewHandler(tradingService, priceService, exchangeInfoService)
+	binanceHandler := exchangeBinance.NewHandler(tradingService, priceService, exchangeInfoService, fundingService)
 	binanceAuthMiddleware := middleware.BinanceAuthMiddleware(accountService, cfg.Encryption.AESKey)
 	binanceHandler.RegisterRoutes(router, binanceAuthMiddleware)
 
 	// OKX compatible routes (/api/v5/*)
-	okxHandler := exchangeOKX.NewHandler(tradingService, priceService, exchangeInfoService)
+	okxHandler := exchangeOKX.NewHandler(tradingService, priceService, exchangeInfoService, fundingService)
 	okxAuthMiddleware := middleware.OKXAuthMiddleware(accountService, cfg.Encryption.AESKey)
 	okxHandler.RegisterRoutes(router, okxAuthMiddleware)
+	okxHandler.RegisterWebSocketRoutes(router, middleware.OKXWebSocketAuth(accountService, cfg.Encryption.AESKey))
 
 	// Bybit compatible routes (/v5/*)
-	bybitHandler := exchangeBybit.NewHandler(tradingService, priceService, exchangeInfoService)
+	bybitHandler := exchangeBybit.NewHandler(tradingService, priceService, exchangeInfoService, fundingService)
 	bybitAuthMiddleware := middleware.BybitAuthMiddleware(accountService, cfg.Encryption.AESKey)
 	bybitHandler.RegisterRoutes(router, bybitAuthMiddleware)
+	bybitHandler.RegisterWebSocketRoutes(router, middleware.BybitWebSocketAuth(accountService, cfg.Encryption.AESKey))
 
 	// Bitget compatible routes (/api/v2/mix/*)
 	bitgetHandler := exchangeBitget.NewHandler(tradingService, priceService, exchangeInfoService)
@@ -169,7 +184,7 @@ func main() {
 	bitgetHandler.RegisterRoutes(router, bitgetAuthMiddleware)
 
 	// Hyperliquid compatible routes (/info, /exchange)
-	hyperliquidHandler := exchangeHyperliquid.NewHandler(tradingService, priceService, exchangeInfoService)
+	hyperliquidHandler := exchangeHyperliquid.NewHandler(tradingService, accountService, priceService, exchangeInfoService, fundingService)
 	hyperliquidAuthMiddleware := middleware.HyperliquidAuthMiddleware(accountService, cfg.Encryption.AESKey)
 	hyperliquidHandler.RegisterRoutes(router, hyperliquidAuthMiddleware)
 
@@ -180,6 +195,9 @@ func main() {
 		Handler: router,
 	}
 
+	// Start matching engine before prices start flowing
+	matchingEngine.Start()
+
 	// Start price service
 	ctx := context.Background()
 	if err := priceService.Start(ctx); err != nil {
@@ -187,9 +205,17 @@ func main() {
 	}
 
 	// Start SL/TP monitoring worker
-	sltpWorker := worker.NewSLTPWorker(tradingService, orderRepo, 1*time.Second)
+	sltpWorker := worker.NewSLTPWorker(tradingService, orderRepo, accountRepo, 1*time.Second)
 	go sltpWorker.Start()
 
+	// Start liquidation worker
+	liquidationWorker := worker.NewLiquidationWorker(tradingService, positionRepo, accountRepo, 1*time.Second)
+	go liquidationWorker.Start()
+
+	// Start funding settlement worker
+	fundingWorker := worker.NewFundingWorker(fundingService, 1*time.Minute)
+	go fundingWorker.Start()
+
 	// Start server in goroutine
 	go func() {
 		log.Printf("Starting server on %s", addr)
@@ -208,9 +234,18 @@ func main() {
 	// Stop SL/TP worker
 	sltpWorker.Stop()
 
+	// Stop liquidation worker
+	liquidationWorker.Stop()
+
+	// Stop funding worker
+	fundingWorker.Stop()
+
 	// Stop price service
 	priceService.Stop()
 
+	// Stop matching engine
+	matchingEngine.Stop()
+
 	// Graceful shutdown with 10 second timeout
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -268,6 +303,10 @@ func autoMigrate(db *gorm.DB) error {
 		&models.Order{},
 		&models.Trade{},
 		&models.ClosedPnLRecord{},
+		&models.FundingRate{},
+		&models.FundingFee{},
+		&models.SymbolSetting{},
+		&models.AgentWallet{},
 	)
 }
 
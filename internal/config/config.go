@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -13,6 +14,7 @@ type Config struct {
 	Redis      RedisConfig      `yaml:"redis"`
 	JWT        JWTConfig        `yaml:"jwt"`
 	Encryption EncryptionConfig `yaml:"encryption"`
+	Price      PriceConfig      `yaml:"price"`
 }
 
 type ServerConfig struct {
@@ -46,6 +48,12 @@ type EncryptionConfig struct {
 	AESKey string `yaml:"aes_key"`
 }
 
+// PriceConfig configures price sources
+// Fallback maps an exchange to the venue whose prices are used when its own feed has no price
+type PriceConfig struct {
+	Fallback map[string]string `yaml:"fallback"`
+}
+
 // Load loads configuration from file and environment variables
 func Load(path string) (*Config, error) {
 	cfg := &Config{}
@@ -126,6 +134,16 @@ func (c *Config) loadFromEnv() {
 	if v := os.Getenv("AES_KEY"); v != "" {
 		c.Encryption.AESKey = v
 	}
+
+	// Price fallback sources, e.g. "bitget=binance,hyperliquid=binance"
+	if v := os.Getenv("PRICE_FALLBACK"); v != "" {
+		c.Price.Fallback = make(map[string]string)
+		for _, pair := range strings.Split(v, ",") {
+			if exchange, source, ok := strings.Cut(strings.TrimSpace(pair), "="); ok {
+				c.Price.Fallback[exchange] = source
+			}
+		}
+	}
 }
 
 // DSN returns the PostgreSQL connection string
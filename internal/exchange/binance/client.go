@@ -145,15 +145,9 @@ func (c *Client) subscribe(symbols []string) error {
 		return fmt.Errorf("not connected")
 	}
 
-	// Build stream names
-	streams := make([]string, len(symbols))
-	for i, symbol := range symbols {
-		streams[i] = strings.ToLower(symbol) + "@markPrice@1s"
-	}
-
 	msg := map[string]interface{}{
 		"method": "SUBSCRIBE",
-		"params": streams,
+		"params": streamNames(symbols),
 		"id":     time.Now().UnixNano(),
 	}
 
@@ -181,14 +175,9 @@ func (c *Client) Unsubscribe(symbols []string) error {
 		return nil
 	}
 
-	streams := make([]string, len(symbols))
-	for i, symbol := range symbols {
-		streams[i] = strings.ToLower(symbol) + "@markPrice@1s"
-	}
-
 	msg := map[string]interface{}{
 		"method": "UNSUBSCRIBE",
-		"params": streams,
+		"params": streamNames(symbols),
 		"id":     time.Now().UnixNano(),
 	}
 
@@ -199,6 +188,15 @@ func (c *Client) Unsubscribe(symbols []string) error {
 	return err
 }
 
+// streamNames returns the mark price (with index price) and mini ticker (last price) streams of symbols
+func streamNames(symbols []string) []string {
+	streams := make([]string, 0, 2*len(symbols))
+	for _, symbol := range symbols {
+		streams = append(streams, strings.ToLower(symbol)+"@markPrice@1s", strings.ToLower(symbol)+"@miniTicker")
+	}
+	return streams
+}
+
 // SetSubscriber sets the price update subscriber
 func (c *Client) SetSubscriber(subscriber exchange.PriceSubscriber) {
 	c.subMux.Lock()
@@ -246,25 +244,27 @@ func (c *Client) handleMessage(message []byte) {
 		return
 	}
 
-	// Check if it's a mark price update
-	eventType, ok := data["e"].(string)
-	if !ok || eventType != "markPriceUpdate" {
-		return
-	}
-
 	symbol, _ := data["s"].(string)
-	priceStr, _ := data["p"].(string)
 	timeMs, _ := data["E"].(float64)
 
-	price, _ := strconv.ParseFloat(priceStr, 64)
-
 	update := exchange.PriceUpdate{
 		Exchange:  "binance",
 		Symbol:    symbol,
-		Price:     price,
 		Timestamp: int64(timeMs),
 	}
 
+	// Mark price updates carry the mark and index prices, mini tickers the last price
+	switch eventType, _ := data["e"].(string); eventType {
+	case "markPriceUpdate":
+		update.Price = parseFloatField(data, "p")
+		update.MarkPrice = update.Price
+		update.IndexPrice = parseFloatField(data, "i")
+	case "24hrMiniTicker":
+		update.LastPrice = parseFloatField(data, "c")
+	default:
+		return
+	}
+
 	c.subMux.RLock()
 	subscriber := c.subscriber
 	c.subMux.RUnlock()
@@ -274,6 +274,13 @@ func (c *Client) handleMessage(message []byte) {
 	}
 }
 
+// parseFloatField parses a string-encoded number field of a stream event
+func parseFloatField(data map[string]interface{}, key string) float64 {
+	str, _ := data[key].(string)
+	value, _ := strconv.ParseFloat(str, 64)
+	return value
+}
+
 // handleDisconnect handles WebSocket disconnection
 func (c *Client) handleDisconnect() {
 	c.connMux.Lock()
@@ -459,6 +466,25 @@ func (c *Client) GetCurrentPrice(symbol string) (float64, error) {
 	return strconv.ParseFloat(result.Price, 64)
 }
 
+// GetFundingRate returns the current funding rate from the premium index
+func (c *Client) GetFundingRate(symbol string) (float64, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/fapi/v1/premiumIndex?symbol=%s", c.restURL, strings.ToUpper(symbol)))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		LastFundingRate string `json:"lastFundingRate"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(result.LastFundingRate, 64)
+}
+
 // ValidateSymbol checks if a symbol is valid
 func (c *Client) ValidateSymbol(symbol string) bool {
 	c.symbolsMux.RLock()
@@ -246,10 +246,11 @@ func (c *Client) handleMessage(message []byte) {
 			InstId   string `json:"instId"`
 		} `json:"arg"`
 		Data []struct {
-			InstId    string `json:"instId"`
-			LastPr    string `json:"lastPr"`
-			MarkPrice string `json:"markPrice"`
-			Ts        string `json:"ts"`
+			InstId     string `json:"instId"`
+			LastPr     string `json:"lastPr"`
+			MarkPrice  string `json:"markPrice"`
+			IndexPrice string `json:"indexPrice"`
+			Ts         string `json:"ts"`
 		} `json:"data"`
 	}
 
@@ -267,6 +268,9 @@ func (c *Client) handleMessage(message []byte) {
 			priceStr = ticker.LastPr
 		}
 		price, _ := strconv.ParseFloat(priceStr, 64)
+		lastPrice, _ := strconv.ParseFloat(ticker.LastPr, 64)
+		markPrice, _ := strconv.ParseFloat(ticker.MarkPrice, 64)
+		indexPrice, _ := strconv.ParseFloat(ticker.IndexPrice, 64)
 		ts, _ := strconv.ParseInt(ticker.Ts, 10, 64)
 
 		symbol := c.convertToStandardSymbol(ticker.InstId)
@@ -276,6 +280,10 @@ func (c *Client) handleMessage(message []byte) {
 			Symbol:    symbol,
 			Price:     price,
 			Timestamp: ts,
+
+			LastPrice:  lastPrice,
+			MarkPrice:  markPrice,
+			IndexPrice: indexPrice,
 		}
 
 		c.subMux.RLock()
@@ -475,6 +483,32 @@ func (c *Client) GetCurrentPrice(symbol string) (float64, error) {
 	return strconv.ParseFloat(result.Data[0].MarkPrice, 64)
 }
 
+// GetFundingRate returns the current funding rate
+func (c *Client) GetFundingRate(symbol string) (float64, error) {
+	url := fmt.Sprintf("%s/api/v2/mix/market/current-fund-rate?symbol=%s&productType=USDT-FUTURES", c.restURL, c.convertSymbol(symbol))
+	resp, err := http.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data []struct {
+			FundingRate string `json:"fundingRate"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, err
+	}
+
+	if len(result.Data) == 0 {
+		return 0, fmt.Errorf("no funding rate data")
+	}
+
+	return strconv.ParseFloat(result.Data[0].FundingRate, 64)
+}
+
 // ValidateSymbol checks if a symbol is valid
 func (c *Client) ValidateSymbol(symbol string) bool {
 	c.symbolsMux.RLock()
@@ -233,10 +233,12 @@ func (c *Client) handleMessage(message []byte) {
 	var data struct {
 		Topic string `json:"topic"`
 		Data  struct {
-			Symbol    string `json:"symbol"`
-			MarkPrice string `json:"markPrice"`
-			Bid1Price string `json:"bid1Price"`
-			Ask1Price string `json:"ask1Price"`
+			Symbol     string `json:"symbol"`
+			MarkPrice  string `json:"markPrice"`
+			LastPrice  string `json:"lastPrice"`
+			IndexPrice string `json:"indexPrice"`
+			Bid1Price  string `json:"bid1Price"`
+			Ask1Price  string `json:"ask1Price"`
 		} `json:"data"`
 		Ts int64 `json:"ts"`
 	}
@@ -252,6 +254,8 @@ func (c *Client) handleMessage(message []byte) {
 	price, _ := strconv.ParseFloat(data.Data.MarkPrice, 64)
 	bidPrice, _ := strconv.ParseFloat(data.Data.Bid1Price, 64)
 	askPrice, _ := strconv.ParseFloat(data.Data.Ask1Price, 64)
+	lastPrice, _ := strconv.ParseFloat(data.Data.LastPrice, 64)
+	indexPrice, _ := strconv.ParseFloat(data.Data.IndexPrice, 64)
 
 	update := exchange.PriceUpdate{
 		Exchange:  "bybit",
@@ -260,6 +264,10 @@ func (c *Client) handleMessage(message []byte) {
 		BidPrice:  bidPrice,
 		AskPrice:  askPrice,
 		Timestamp: data.Ts,
+
+		LastPrice:  lastPrice,
+		MarkPrice:  price,
+		IndexPrice: indexPrice,
 	}
 
 	c.subMux.RLock()
@@ -455,6 +463,33 @@ func (c *Client) GetCurrentPrice(symbol string) (float64, error) {
 	return strconv.ParseFloat(result.Result.List[0].MarkPrice, 64)
 }
 
+// GetFundingRate returns the current funding rate
+func (c *Client) GetFundingRate(symbol string) (float64, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/v5/market/tickers?category=linear&symbol=%s", c.restURL, strings.ToUpper(symbol)))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Result struct {
+			List []struct {
+				FundingRate string `json:"fundingRate"`
+			} `json:"list"`
+		} `json:"result"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, err
+	}
+
+	if len(result.Result.List) == 0 {
+		return 0, fmt.Errorf("no funding rate data")
+	}
+
+	return strconv.ParseFloat(result.Result.List[0].FundingRate, 64)
+}
+
 // ValidateSymbol checks if a symbol is valid
 func (c *Client) ValidateSymbol(symbol string) bool {
 	c.symbolsMux.RLock()
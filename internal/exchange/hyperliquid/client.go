@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -339,24 +340,31 @@ func (c *Client) loadSymbolInfo() error {
 	defer c.symbolsMux.Unlock()
 
 	for _, s := range result.Universe {
-		info := &exchange.SymbolInfo{
-			Symbol:            s.Name,
-			BaseAsset:         s.Name,
-			QuoteAsset:        "USD",
-			QuantityPrecision: s.SzDecimals,
-			PricePrecision:    6,
-		}
-
-		c.symbols[s.Name] = info
+		c.symbols[s.Name] = NewSymbolInfo(s.Name, s.SzDecimals)
 	}
 
 	log.Printf("[Hyperliquid] Loaded %d symbols", len(c.symbols))
 	return nil
 }
 
+// NewSymbolInfo builds the symbol info of a meta universe entry
+// Sizes step in units of 10^-szDecimals; Hyperliquid publishes no maximum size
+func NewSymbolInfo(coin string, szDecimals int) *exchange.SymbolInfo {
+	step := math.Pow10(-szDecimals)
+	return &exchange.SymbolInfo{
+		Symbol:            coin,
+		BaseAsset:         coin,
+		QuoteAsset:        "USD",
+		QuantityPrecision: szDecimals,
+		PricePrecision:    6,
+		MinQty:            step,
+		StepSize:          step,
+	}
+}
+
 // convertSymbol converts standard symbol (BTCUSDT) to Hyperliquid format (BTC)
+// Coin names are case-sensitive (e.g. kPEPE), so the symbol is not upper-cased
 func (c *Client) convertSymbol(symbol string) string {
-	symbol = strings.ToUpper(symbol)
 	// Remove USDT suffix
 	if strings.HasSuffix(symbol, "USDT") {
 		return symbol[:len(symbol)-4]
@@ -445,6 +453,50 @@ func (c *Client) GetCurrentPrice(symbol string) (float64, error) {
 	return strconv.ParseFloat(priceStr, 64)
 }
 
+// GetFundingRate returns the current hourly funding rate
+// Asset contexts are returned in the same order as the meta universe
+func (c *Client) GetFundingRate(symbol string) (float64, error) {
+	hlSymbol := c.convertSymbol(symbol)
+
+	resp, err := http.Post(c.restURL+"/info", "application/json",
+		strings.NewReader(`{"type": "metaAndAssetCtxs"}`))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var result []json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, err
+	}
+	if len(result) < 2 {
+		return 0, fmt.Errorf("unexpected metaAndAssetCtxs response")
+	}
+
+	var meta struct {
+		Universe []struct {
+			Name string `json:"name"`
+		} `json:"universe"`
+	}
+	var ctxs []struct {
+		Funding string `json:"funding"`
+	}
+	if err := json.Unmarshal(result[0], &meta); err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(result[1], &ctxs); err != nil {
+		return 0, err
+	}
+
+	for i, asset := range meta.Universe {
+		if asset.Name == hlSymbol && i < len(ctxs) {
+			return strconv.ParseFloat(ctxs[i].Funding, 64)
+		}
+	}
+
+	return 0, fmt.Errorf("symbol not found: %s", symbol)
+}
+
 // ValidateSymbol checks if a symbol is valid
 func (c *Client) ValidateSymbol(symbol string) bool {
 	c.symbolsMux.RLock()
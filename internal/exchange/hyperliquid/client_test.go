@@ -0,0 +1,33 @@
+package hyperliquid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadSymbolInfo tests size limits from szDecimals and case-sensitive coin lookup
+func TestLoadSymbolInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"universe":[{"name":"BTC","szDecimals":5},{"name":"kPEPE","szDecimals":0}]}`))
+	}))
+	defer server.Close()
+
+	c := NewClient()
+	c.restURL = server.URL
+	require.NoError(t, c.loadSymbolInfo())
+
+	info, err := c.GetSymbolInfo("BTCUSDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.00001, info.MinQty, 1e-12)
+	assert.Zero(t, info.MaxQty)
+	assert.True(t, info.ValidQuantity(1000))
+
+	info, err = c.GetSymbolInfo("kPEPEUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, "kPEPE", info.Symbol)
+	assert.Equal(t, 1.0, info.MinQty)
+}
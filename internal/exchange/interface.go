@@ -2,6 +2,7 @@ package exchange
 
 import (
 	"context"
+	"math"
 )
 
 // PriceUpdate represents a real-time price update from an exchange
@@ -12,9 +13,77 @@ type PriceUpdate struct {
 	BidPrice  float64 `json:"bid_price"`
 	AskPrice  float64 `json:"ask_price"`
 	Timestamp int64   `json:"timestamp"`
+
+	// Separate last, mark and index price streams; zero when the feed does not carry them
+	LastPrice  float64 `json:"last_price,omitempty"`
+	MarkPrice  float64 `json:"mark_price,omitempty"`
+	IndexPrice float64 `json:"index_price,omitempty"`
+}
+
+// Last returns the last traded price, falling back to the price when the feed has no trade stream
+func (u PriceUpdate) Last() float64 {
+	if u.LastPrice > 0 {
+		return u.LastPrice
+	}
+	return u.Price
+}
+
+// Mark returns the mark price, falling back to the price when the feed has no mark price stream
+func (u PriceUpdate) Mark() float64 {
+	if u.MarkPrice > 0 {
+		return u.MarkPrice
+	}
+	return u.Price
+}
+
+// Index returns the index price, falling back to the mark price when the feed has no index stream
+func (u PriceUpdate) Index() float64 {
+	if u.IndexPrice > 0 {
+		return u.IndexPrice
+	}
+	return u.Mark()
+}
+
+// Merge fills the prices a partial update leaves zero from the previous update of the same symbol
+// Feeds that deliver each price stream in its own message rely on this to keep a full picture
+func (u PriceUpdate) Merge(prev PriceUpdate) PriceUpdate {
+	u.BidPrice = firstPositive(u.BidPrice, prev.BidPrice)
+	u.AskPrice = firstPositive(u.AskPrice, prev.AskPrice)
+	u.LastPrice = firstPositive(u.LastPrice, prev.LastPrice)
+	u.MarkPrice = firstPositive(u.MarkPrice, prev.MarkPrice)
+	u.IndexPrice = firstPositive(u.IndexPrice, prev.IndexPrice)
+	u.Price = firstPositive(u.Price, prev.Price, u.MarkPrice, u.LastPrice)
+	return u
+}
+
+// firstPositive returns the first positive value, or zero when there is none
+func firstPositive(values ...float64) float64 {
+	for _, v := range values {
+		if v > 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+// BestBid returns the bid price, falling back to the last price when the feed has no book data
+func (u PriceUpdate) BestBid() float64 {
+	if u.BidPrice > 0 {
+		return u.BidPrice
+	}
+	return u.Price
+}
+
+// BestAsk returns the ask price, falling back to the last price when the feed has no book data
+func (u PriceUpdate) BestAsk() float64 {
+	if u.AskPrice > 0 {
+		return u.AskPrice
+	}
+	return u.Price
 }
 
 // SymbolInfo represents trading pair information
+// Quantity limits are in base-asset units; ContractSize converts them to the venue's order size unit
 type SymbolInfo struct {
 	Symbol            string  `json:"symbol"`
 	BaseAsset         string  `json:"base_asset"`
@@ -26,6 +95,37 @@ type SymbolInfo struct {
 	MinNotional       float64 `json:"min_notional"`
 	TickSize          float64 `json:"tick_size"`
 	StepSize          float64 `json:"step_size"`
+	ContractSize      float64 `json:"contract_size"` // base-asset amount of one contract, e.g. OKX ctVal
+}
+
+// ValidQuantity reports whether a base-asset quantity is within the symbol's limits
+// A zero MaxQty means the venue publishes no maximum size
+func (s *SymbolInfo) ValidQuantity(qty float64) bool {
+	return qty >= s.MinQty && (s.MaxQty <= 0 || qty <= s.MaxQty)
+}
+
+// Multiplier returns the base-asset amount of one unit of order size
+// Venues that size orders in the base asset have no contract size and a multiplier of 1
+func (s *SymbolInfo) Multiplier() float64 {
+	if s.ContractSize > 0 {
+		return s.ContractSize
+	}
+	return 1
+}
+
+// ToBase converts an order size in contracts to base-asset units
+func (s *SymbolInfo) ToBase(contracts float64) float64 {
+	return roundSize(contracts * s.Multiplier())
+}
+
+// ToContracts converts a base-asset quantity to an order size in contracts
+func (s *SymbolInfo) ToContracts(quantity float64) float64 {
+	return roundSize(quantity / s.Multiplier())
+}
+
+// roundSize drops the floating point noise a contract conversion leaves below 1e-10
+func roundSize(size float64) float64 {
+	return math.Round(size*1e10) / 1e10
 }
 
 // PriceSubscriber is an interface for components that receive price updates
@@ -76,6 +176,9 @@ type ExchangeAdapter interface {
 	// GetMaintenanceMarginRate returns the maintenance margin rate for a position value
 	GetMaintenanceMarginRate(positionValue float64) float64
 
+	// GetFundingRate returns the current funding rate for a symbol
+	GetFundingRate(symbol string) (float64, error)
+
 	// GetFeeRate returns taker and maker fee rates
 	GetFeeRate() (takerFee, makerFee float64)
 }
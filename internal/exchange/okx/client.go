@@ -139,13 +139,7 @@ func (c *Client) subscribe(symbols []string) error {
 		return fmt.Errorf("not connected")
 	}
 
-	args := make([]map[string]string, len(symbols))
-	for i, symbol := range symbols {
-		args[i] = map[string]string{
-			"channel": "mark-price",
-			"instId":  c.convertSymbol(symbol),
-		}
-	}
+	args := c.channelArgs(symbols)
 
 	msg := map[string]interface{}{
 		"op":   "subscribe",
@@ -176,13 +170,7 @@ func (c *Client) Unsubscribe(symbols []string) error {
 		return nil
 	}
 
-	args := make([]map[string]string, len(symbols))
-	for i, symbol := range symbols {
-		args[i] = map[string]string{
-			"channel": "mark-price",
-			"instId":  c.convertSymbol(symbol),
-		}
-	}
+	args := c.channelArgs(symbols)
 
 	msg := map[string]interface{}{
 		"op":   "unsubscribe",
@@ -196,6 +184,21 @@ func (c *Client) Unsubscribe(symbols []string) error {
 	return err
 }
 
+// channelArgs returns the mark price, ticker (last price) and index ticker channels of symbols
+// Index tickers are keyed by the spot pair, e.g. BTC-USDT for BTC-USDT-SWAP
+func (c *Client) channelArgs(symbols []string) []map[string]string {
+	args := make([]map[string]string, 0, 3*len(symbols))
+	for _, symbol := range symbols {
+		instId := c.convertSymbol(symbol)
+		args = append(args,
+			map[string]string{"channel": "mark-price", "instId": instId},
+			map[string]string{"channel": "tickers", "instId": instId},
+			map[string]string{"channel": "index-tickers", "instId": strings.TrimSuffix(instId, "-SWAP")},
+		)
+	}
+	return args
+}
+
 // SetSubscriber sets the price update subscriber
 func (c *Client) SetSubscriber(subscriber exchange.PriceSubscriber) {
 	c.subMux.Lock()
@@ -243,6 +246,8 @@ func (c *Client) handleMessage(message []byte) {
 		} `json:"arg"`
 		Data []struct {
 			MarkPx string `json:"markPx"`
+			Last   string `json:"last"`
+			IdxPx  string `json:"idxPx"`
 			Ts     string `json:"ts"`
 		} `json:"data"`
 	}
@@ -251,12 +256,11 @@ func (c *Client) handleMessage(message []byte) {
 		return
 	}
 
-	if data.Arg.Channel != "mark-price" || len(data.Data) == 0 {
+	if len(data.Data) == 0 {
 		return
 	}
-
-	price, _ := strconv.ParseFloat(data.Data[0].MarkPx, 64)
-	ts, _ := strconv.ParseInt(data.Data[0].Ts, 10, 64)
+	tick := data.Data[0]
+	ts, _ := strconv.ParseInt(tick.Ts, 10, 64)
 
 	// Convert OKX symbol back to standard format
 	symbol := c.convertToStandardSymbol(data.Arg.InstId)
@@ -264,10 +268,21 @@ func (c *Client) handleMessage(message []byte) {
 	update := exchange.PriceUpdate{
 		Exchange:  "okx",
 		Symbol:    symbol,
-		Price:     price,
 		Timestamp: ts,
 	}
 
+	switch data.Arg.Channel {
+	case "mark-price":
+		update.Price, _ = strconv.ParseFloat(tick.MarkPx, 64)
+		update.MarkPrice = update.Price
+	case "tickers":
+		update.LastPrice, _ = strconv.ParseFloat(tick.Last, 64)
+	case "index-tickers":
+		update.IndexPrice, _ = strconv.ParseFloat(tick.IdxPx, 64)
+	default:
+		return
+	}
+
 	c.subMux.RLock()
 	subscriber := c.subscriber
 	c.subMux.RUnlock()
@@ -349,6 +364,7 @@ func (c *Client) loadSymbolInfo() error {
 			TickSz   string `json:"tickSz"`
 			LotSz    string `json:"lotSz"`
 			MinSz    string `json:"minSz"`
+			MaxLmtSz string `json:"maxLmtSz"`
 			CtVal    string `json:"ctVal"`
 		} `json:"data"`
 	}
@@ -362,17 +378,22 @@ func (c *Client) loadSymbolInfo() error {
 
 	for _, s := range result.Data {
 		tickSize, _ := strconv.ParseFloat(s.TickSz, 64)
-		stepSize, _ := strconv.ParseFloat(s.LotSz, 64)
-		minQty, _ := strconv.ParseFloat(s.MinSz, 64)
+		lotSz, _ := strconv.ParseFloat(s.LotSz, 64)
+		minSz, _ := strconv.ParseFloat(s.MinSz, 64)
+		maxSz, _ := strconv.ParseFloat(s.MaxLmtSz, 64)
+		ctVal, _ := strconv.ParseFloat(s.CtVal, 64)
 
+		// OKX sizes SWAP orders in contracts of ctVal base units each
 		info := &exchange.SymbolInfo{
-			Symbol:     c.convertToStandardSymbol(s.InstId),
-			BaseAsset:  s.BaseCcy,
-			QuoteAsset: s.QuoteCcy,
-			TickSize:   tickSize,
-			StepSize:   stepSize,
-			MinQty:     minQty,
+			Symbol:       c.convertToStandardSymbol(s.InstId),
+			BaseAsset:    s.BaseCcy,
+			QuoteAsset:   s.QuoteCcy,
+			TickSize:     tickSize,
+			ContractSize: ctVal,
 		}
+		info.StepSize = info.ToBase(lotSz)
+		info.MinQty = info.ToBase(minSz)
+		info.MaxQty = info.ToBase(maxSz)
 
 		c.symbols[info.Symbol] = info
 	}
@@ -471,6 +492,32 @@ func (c *Client) GetCurrentPrice(symbol string) (float64, error) {
 	return strconv.ParseFloat(result.Data[0].MarkPx, 64)
 }
 
+// GetFundingRate returns the current funding rate
+func (c *Client) GetFundingRate(symbol string) (float64, error) {
+	instId := c.convertSymbol(symbol)
+	resp, err := http.Get(fmt.Sprintf("%s/api/v5/public/funding-rate?instId=%s", c.restURL, instId))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data []struct {
+			FundingRate string `json:"fundingRate"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, err
+	}
+
+	if len(result.Data) == 0 {
+		return 0, fmt.Errorf("no funding rate data")
+	}
+
+	return strconv.ParseFloat(result.Data[0].FundingRate, 64)
+}
+
 // ValidateSymbol checks if a symbol is valid
 func (c *Client) ValidateSymbol(symbol string) bool {
 	c.symbolsMux.RLock()
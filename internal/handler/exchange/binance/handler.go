@@ -1,29 +1,46 @@
 package binance
 
 import (
+	"encoding/json"
+	"errors"
 	"log"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ccxt-simulator/internal/middleware"
 	"github.com/ccxt-simulator/internal/models"
+	"github.com/ccxt-simulator/internal/repository"
 	"github.com/ccxt-simulator/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	maxBatchOrders  = 5  // most orders accepted by POST /fapi/v1/batchOrders
+	maxBatchCancels = 10 // most orders accepted by DELETE /fapi/v1/batchOrders
+)
+
+// errMissingSymbol is returned by placeOrder when no symbol was sent
+var errMissingSymbol = errors.New("mandatory parameter 'symbol' was not sent")
+
 // Handler handles Binance-compatible API requests
 type Handler struct {
 	tradingService      *service.TradingService
 	priceService        *service.PriceService
 	exchangeInfoService *service.ExchangeInfoService
+	fundingService      *service.FundingService
+	listenKeys          *listenKeyStore
 }
 
 // NewHandler creates a new Binance handler
-func NewHandler(tradingService *service.TradingService, priceService *service.PriceService, exchangeInfoService *service.ExchangeInfoService) *Handler {
+func NewHandler(tradingService *service.TradingService, priceService *service.PriceService, exchangeInfoService *service.ExchangeInfoService, fundingService *service.FundingService) *Handler {
 	return &Handler{
 		tradingService:      tradingService,
 		priceService:        priceService,
 		exchangeInfoService: exchangeInfoService,
+		fundingService:      fundingService,
+		listenKeys:          newListenKeyStore(),
 	}
 }
 
@@ -66,6 +83,8 @@ func (h *Handler) GetAccount(c *gin.Context) {
 			"liquidationPrice": strconv.FormatFloat(pos.LiquidationPrice, 'f', 8, 64),
 			"leverage":         strconv.Itoa(pos.Leverage),
 			"marginType":       string(pos.MarginMode),
+			"isolated":         pos.IsIsolated(),
+			"isolatedWallet":   strconv.FormatFloat(isolatedWallet(&pos), 'f', 8, 64),
 			"positionSide":     string(pos.Side),
 			"updateTime":       pos.UpdatedAt.UnixMilli(),
 		})
@@ -155,10 +174,13 @@ func (h *Handler) GetPositionRisk(c *gin.Context) {
 	}
 
 	result := make([]gin.H, 0)
+	reported := make(map[string]bool)
 	for _, pos := range positions {
 		if symbol != "" && pos.Symbol != symbol {
 			continue
 		}
+		reported[pos.Symbol] = true
+
 		// Binance API spec: positionAmt is negative for SHORT positions
 		positionAmt := pos.Quantity
 		if pos.Side == models.PositionSideShort {
@@ -173,13 +195,45 @@ func (h *Handler) GetPositionRisk(c *gin.Context) {
 			"liquidationPrice": strconv.FormatFloat(pos.LiquidationPrice, 'f', 8, 64),
 			"leverage":         strconv.Itoa(pos.Leverage),
 			"marginType":       string(pos.MarginMode),
-			"isolatedMargin":   strconv.FormatFloat(pos.Margin, 'f', 8, 64),
+			"isolatedMargin":   strconv.FormatFloat(isolatedMargin(&pos), 'f', 8, 64),
+			"isolatedWallet":   strconv.FormatFloat(isolatedWallet(&pos), 'f', 8, 64),
 			"isAutoAddMargin":  "false",
 			"positionSide":     string(pos.Side),
 			"updateTime":       pos.UpdatedAt.UnixMilli(),
 		})
 	}
 
+	// Symbols without a position are reported flat with their configured leverage and margin type
+	var settings []models.SymbolSetting
+	if symbol != "" {
+		if setting, err := h.tradingService.GetSymbolSetting(account.ID, symbol); err == nil {
+			settings = append(settings, *setting)
+		}
+	} else {
+		settings, _ = h.tradingService.GetSymbolSettings(account.ID)
+	}
+	for _, setting := range settings {
+		if reported[setting.Symbol] {
+			continue
+		}
+		markPrice, _ := h.priceService.GetPrice("binance", setting.Symbol)
+		result = append(result, gin.H{
+			"symbol":           setting.Symbol,
+			"positionAmt":      "0",
+			"entryPrice":       "0",
+			"markPrice":        strconv.FormatFloat(markPrice, 'f', 8, 64),
+			"unRealizedProfit": "0",
+			"liquidationPrice": "0",
+			"leverage":         strconv.Itoa(setting.Leverage),
+			"marginType":       string(setting.MarginMode),
+			"isolatedMargin":   "0",
+			"isolatedWallet":   "0",
+			"isAutoAddMargin":  "false",
+			"positionSide":     string(models.PositionSideBoth),
+			"updateTime":       setting.UpdatedAt.UnixMilli(),
+		})
+	}
+
 	c.JSON(200, result)
 }
 
@@ -199,11 +253,106 @@ func (h *Handler) SetMarginType(c *gin.Context) {
 		return
 	}
 
+	var marginMode models.MarginMode
+	switch strings.ToUpper(marginType) {
+	case "ISOLATED":
+		marginMode = models.MarginModeIsolated
+	case "CROSSED", "CROSS":
+		marginMode = models.MarginModeCross
+	default:
+		c.JSON(400, gin.H{"code": -1130, "msg": "Data sent for parameter 'marginType' is not valid."})
+		return
+	}
+
+	if err := h.tradingService.SetMarginMode(account.ID, symbol, marginMode); err != nil {
+		switch err {
+		case service.ErrSettingNotChanged:
+			c.JSON(400, gin.H{"code": -4046, "msg": "No need to change margin type."})
+		case service.ErrPositionExists:
+			c.JSON(400, gin.H{"code": -4048, "msg": "Margin type cannot be changed if there exists position."})
+		default:
+			h.handleError(c, err)
+		}
+		return
+	}
+
 	c.JSON(200, gin.H{
 		"code": 200,
 		"msg":  "success",
 	})
-	_ = marginType
+}
+
+// AdjustPositionMargin handles POST /fapi/v1/positionMargin
+func (h *Handler) AdjustPositionMargin(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"code": -2015, "msg": "Invalid API-key."})
+		return
+	}
+
+	symbol := c.PostForm("symbol")
+	positionSide := c.PostForm("positionSide")
+	amount, _ := strconv.ParseFloat(c.PostForm("amount"), 64)
+	adjustType := c.PostForm("type") // 1: add, 2: reduce
+
+	if symbol == "" {
+		c.JSON(400, gin.H{"code": -1102, "msg": "Mandatory parameter 'symbol' was not sent."})
+		return
+	}
+	if amount <= 0 {
+		c.JSON(400, gin.H{"code": -4055, "msg": "Amount must be positive."})
+		return
+	}
+
+	delta := amount
+	switch adjustType {
+	case "1":
+	case "2":
+		delta = -amount
+	default:
+		c.JSON(400, gin.H{"code": -1130, "msg": "Data sent for parameter 'type' is not valid."})
+		return
+	}
+
+	if _, err := h.tradingService.AdjustIsolatedMargin(account.ID, symbol, models.PositionSide(positionSide), delta); err != nil {
+		switch err {
+		case service.ErrPositionNotFound:
+			c.JSON(400, gin.H{"code": -4054, "msg": "Cannot add position margin: position is 0."})
+		case service.ErrNotIsolated:
+			c.JSON(400, gin.H{"code": -4054, "msg": "Cannot adjust position margin: position is not isolated."})
+		case service.ErrInsufficientBalance:
+			c.JSON(400, gin.H{"code": -4050, "msg": "Cross balance insufficient."})
+		case service.ErrInsufficientMargin:
+			c.JSON(400, gin.H{"code": -4051, "msg": "Isolated balance insufficient."})
+		default:
+			h.handleError(c, err)
+		}
+		return
+	}
+
+	typeValue, _ := strconv.Atoi(adjustType)
+	c.JSON(200, gin.H{
+		"amount": amount,
+		"code":   200,
+		"msg":    "Successfully modify position margin.",
+		"type":   typeValue,
+	})
+}
+
+// isolatedWallet returns the margin locked in an isolated position (0 for cross)
+func isolatedWallet(pos *models.Position) float64 {
+	if !pos.IsIsolated() {
+		return 0
+	}
+	return pos.Margin
+}
+
+// isolatedMargin returns an isolated position's margin including unrealized PnL (0 for cross)
+func isolatedMargin(pos *models.Position) float64 {
+	if !pos.IsIsolated() {
+		return 0
+	}
+	return pos.Margin + pos.UnrealizedPnL
 }
 
 // GetOpenOrders handles GET /fapi/v1/openOrders
@@ -233,23 +382,87 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 		return
 	}
 
-	symbol := c.PostForm("symbol")
-	side := c.PostForm("side")
-	positionSide := c.PostForm("positionSide")
-	orderType := c.PostForm("type")
-	quantity, _ := strconv.ParseFloat(c.PostForm("quantity"), 64)
-	price, _ := strconv.ParseFloat(c.PostForm("price"), 64)
-	stopPrice, _ := strconv.ParseFloat(c.PostForm("stopPrice"), 64)
-	reduceOnly := c.PostForm("reduceOnly") == "true"
-	closePosition := c.PostForm("closePosition") == "true"
+	order, err := h.placeOrder(account.ID, c.PostForm)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(200, h.formatOrder(order))
+}
+
+// CreateBatchOrders handles POST /fapi/v1/batchOrders
+// Orders are placed independently; a failed order is reported in its slot as an error object
+func (h *Handler) CreateBatchOrders(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"code": -2015, "msg": "Invalid API-key."})
+		return
+	}
+
+	var batch []map[string]any
+	if err := json.Unmarshal([]byte(c.PostForm("batchOrders")), &batch); err != nil || len(batch) == 0 || len(batch) > maxBatchOrders {
+		c.JSON(400, gin.H{"code": -1130, "msg": "Data sent for parameter 'batchOrders' is not valid."})
+		return
+	}
+
+	result := make([]gin.H, 0, len(batch))
+	for _, params := range batch {
+		order, err := h.placeOrder(account.ID, batchParam(params))
+		if err != nil {
+			_, body := errorBody(err)
+			result = append(result, body)
+			continue
+		}
+		result = append(result, h.formatOrder(order))
+	}
+
+	c.JSON(200, result)
+}
+
+// batchParam reads the parameters of one batchOrders element
+// Values are normally strings, but JSON numbers and booleans are accepted too
+func batchParam(params map[string]any) func(string) string {
+	return func(key string) string {
+		switch value := params[key].(type) {
+		case string:
+			return value
+		case float64:
+			return strconv.FormatFloat(value, 'f', -1, 64)
+		case bool:
+			return strconv.FormatBool(value)
+		}
+		return ""
+	}
+}
+
+// placeOrder places one order from Binance order parameters, looked up through param
+func (h *Handler) placeOrder(accountID uint, param func(string) string) (*models.Order, error) {
+	symbol := param("symbol")
+	side := param("side")
+	positionSide := param("positionSide")
+	orderType := param("type")
+	quantity, _ := strconv.ParseFloat(param("quantity"), 64)
+	price, _ := strconv.ParseFloat(param("price"), 64)
+	stopPrice, _ := strconv.ParseFloat(param("stopPrice"), 64)
+	reduceOnly := param("reduceOnly") == "true"
+	closePosition := param("closePosition") == "true"
+	timeInForce := param("timeInForce")
+	clientOrderID := param("newClientOrderId")
+	callbackRate, _ := strconv.ParseFloat(param("callbackRate"), 64)
+	activationPrice, _ := strconv.ParseFloat(param("activationPrice"), 64)
+	workingType := param("workingType")
+	var goodTillDate time.Time
+	if ms, err := strconv.ParseInt(param("goodTillDate"), 10, 64); err == nil {
+		goodTillDate = time.UnixMilli(ms)
+	}
 
 	// DEBUG: Log the raw order parameters
 	log.Printf("[DEBUG] CreateOrder: symbol=%s, side=%s, positionSide=%s, type=%q, stopPrice=%f, reduceOnly=%v, closePosition=%v",
 		symbol, side, positionSide, orderType, stopPrice, reduceOnly, closePosition)
 
 	if symbol == "" {
-		c.JSON(400, gin.H{"code": -1102, "msg": "Mandatory parameter 'symbol' was not sent."})
-		return
+		return nil, errMissingSymbol
 	}
 
 	var posSide models.PositionSide
@@ -273,14 +486,22 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 		oType = models.OrderTypeStopMarket
 	case "TAKE_PROFIT", "TAKE_PROFIT_MARKET":
 		oType = models.OrderTypeTakeProfit
+	case "TRAILING_STOP_MARKET":
+		oType = models.OrderTypeTrailingStop
 	default:
 		oType = models.OrderTypeMarket
 	}
+	// STOP and TAKE_PROFIT rest a limit order at price once triggered; the _MARKET types close at market
+	limitPrice := price
+	if orderType != "STOP" && orderType != "TAKE_PROFIT" {
+		limitPrice = 0
+	}
 
 	// Check if this is a conditional order (SL/TP)
-	// STOP_MARKET and TAKE_PROFIT are conditional orders that should NOT execute immediately
+	// STOP_MARKET, TAKE_PROFIT and TRAILING_STOP_MARKET are conditional orders that should NOT execute immediately
 	// They should only create an order with status NEW and wait for price trigger
-	isConditionalOrder := oType == models.OrderTypeStopMarket || oType == models.OrderTypeTakeProfit
+	isConditionalOrder := oType == models.OrderTypeStopMarket || oType == models.OrderTypeTakeProfit ||
+		oType == models.OrderTypeTrailingStop
 
 	// Determine if this is a close position order
 	// In hedge mode: LONG+SELL or SHORT+BUY = close position
@@ -304,48 +525,64 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 	var err error
 
 	if isConditionalOrder {
+		triggerPriceType, ok := parseWorkingType(workingType)
+		if !ok {
+			return nil, service.ErrInvalidTrigger
+		}
+
 		// For conditional orders (SL/TP), just create the order without executing
 		// The order will be triggered when price reaches the stop price
 		order, err = h.tradingService.CreateConditionalOrder(&service.ConditionalOrderRequest{
-			AccountID:     account.ID,
+			AccountID:     accountID,
 			Symbol:        symbol,
 			Side:          posSide,
 			Quantity:      quantity,
 			OrderType:     oType,
 			StopPrice:     stopPrice,
-			Price:         price,
+			Price:         limitPrice,
 			ReduceOnly:    reduceOnly,
 			ClosePosition: closePosition,
+			ClientOrderID: clientOrderID,
+			// Binance callbackRate is a percentage
+			CallbackRate:     callbackRate / 100,
+			ActivationPrice:  activationPrice,
+			TriggerPriceType: triggerPriceType,
 		}, models.ExchangeBinance)
 	} else if isOpen {
 		req := &service.OpenPositionRequest{
-			AccountID: account.ID,
-			Symbol:    symbol,
-			Side:      posSide,
-			Quantity:  quantity,
-			OrderType: oType,
-			Price:     price,
+			AccountID:     accountID,
+			Symbol:        symbol,
+			Side:          posSide,
+			Quantity:      quantity,
+			OrderType:     oType,
+			Price:         price,
+			TimeInForce:   timeInForce,
+			GoodTillDate:  goodTillDate,
+			ClientOrderID: clientOrderID,
 		}
 		order, _, err = h.tradingService.OpenPosition(req, models.ExchangeBinance)
 	} else {
 		req := &service.ClosePositionRequest{
-			AccountID: account.ID,
-			Symbol:    symbol,
-			Side:      posSide,
-			Quantity:  &quantity,
-			OrderType: oType,
-			Price:     price,
-			StopPrice: stopPrice,
+			AccountID:     accountID,
+			Symbol:        symbol,
+			Side:          posSide,
+			Quantity:      &quantity,
+			OrderType:     oType,
+			Price:         price,
+			StopPrice:     stopPrice,
+			TimeInForce:   timeInForce,
+			GoodTillDate:  goodTillDate,
+			ClientOrderID: clientOrderID,
 		}
 		order, _, err = h.tradingService.ClosePosition(req, models.ExchangeBinance)
 	}
 
-	if err != nil {
-		h.handleError(c, err)
-		return
+	// An IOC order that cannot fill is returned as EXPIRED; FOK and GTX expiries are reported as errors
+	if err == service.ErrIOCNotFilled {
+		err = nil
 	}
 
-	c.JSON(200, h.formatOrder(order))
+	return order, err
 }
 
 // CreateAlgoOrder handles POST /fapi/v1/algoOrder (new Binance algo order API)
@@ -373,6 +610,13 @@ func (h *Handler) CreateAlgoOrder(c *gin.Context) {
 	price, _ := strconv.ParseFloat(c.PostForm("price"), 64)
 	closePosition := c.PostForm("closePosition") == "true"
 	reduceOnly := c.PostForm("reduceOnly") == "true"
+	callbackRate, _ := strconv.ParseFloat(c.PostForm("callbackRate"), 64)
+	activatePrice, _ := strconv.ParseFloat(c.PostForm("activatePrice"), 64)
+	triggerPriceType, ok := parseWorkingType(c.PostForm("workingType"))
+	if !ok {
+		h.handleError(c, service.ErrInvalidTrigger)
+		return
+	}
 
 	// DEBUG: Log the raw algo order parameters
 	log.Printf("[DEBUG] CreateAlgoOrder: symbol=%s, positionSide=%s, orderType=%q, triggerPrice=%f, closePosition=%v, reduceOnly=%v",
@@ -401,6 +645,10 @@ func (h *Handler) CreateAlgoOrder(c *gin.Context) {
 	default:
 		oType = models.OrderTypeStopMarket
 	}
+	// STOP and TAKE_PROFIT rest a limit order at price once triggered; the _MARKET types close at market
+	if orderType != "STOP" && orderType != "TAKE_PROFIT" {
+		price = 0
+	}
 
 	// Algo orders are conditional orders (SL/TP) - they should NOT execute immediately
 	// They just create an order entry and wait for price trigger
@@ -414,6 +662,11 @@ func (h *Handler) CreateAlgoOrder(c *gin.Context) {
 		Price:         price,
 		ClosePosition: closePosition,
 		ReduceOnly:    reduceOnly,
+		ClientOrderID: c.PostForm("clientAlgoId"),
+		// Binance callbackRate is a percentage
+		CallbackRate:     callbackRate / 100,
+		ActivationPrice:  activatePrice,
+		TriggerPriceType: triggerPriceType,
 	}, models.ExchangeBinance)
 
 	if err != nil {
@@ -444,21 +697,25 @@ func (h *Handler) GetOpenAlgoOrders(c *gin.Context) {
 
 	result := make([]gin.H, 0)
 	for _, order := range orders {
-		result = append(result, gin.H{
+		algoOrder := gin.H{
 			"algoId":        order.ID,
 			"clientAlgoId":  order.ClientOrderID,
 			"symbol":        order.Symbol,
 			"side":          string(order.Side),
 			"positionSide":  string(order.PositionSide),
-			"orderType":     string(order.Type),
+			"orderType":     orderTypeName(&order),
 			"triggerPrice":  strconv.FormatFloat(order.StopPrice, 'f', 8, 64),
+			"price":         strconv.FormatFloat(order.Price, 'f', 8, 64),
+			"workingType":   workingTypeName(&order),
 			"quantity":      strconv.FormatFloat(order.Quantity, 'f', 8, 64),
 			"reduceOnly":    order.ReduceOnly,
 			"closePosition": order.ClosePosition,
 			"algoStatus":    "NEW",
 			"bookTime":      order.CreatedAt.UnixMilli(),
 			"updateTime":    order.UpdatedAt.UnixMilli(),
-		})
+		}
+		addTrailingStop(algoOrder, &order)
+		result = append(result, algoOrder)
 	}
 
 	c.JSON(200, gin.H{
@@ -525,31 +782,147 @@ func (h *Handler) CancelOrder(c *gin.Context) {
 	}
 
 	symbol := c.Query("symbol")
-	orderIDStr := c.Query("orderId")
-
 	if symbol == "" {
 		c.JSON(400, gin.H{"code": -1102, "msg": "Mandatory parameter 'symbol' was not sent."})
 		return
 	}
 
-	orderID, _ := strconv.ParseUint(orderIDStr, 10, 64)
-	order, err := h.tradingService.GetOrderStatus(account.ID, uint(orderID))
+	orderID, _ := strconv.ParseUint(c.Query("orderId"), 10, 64)
+	order, err := h.tradingService.FindOrder(account.ID, uint(orderID), c.Query("origClientOrderId"))
+	if err == nil && order.Symbol == symbol {
+		order, err = h.tradingService.CancelOrder(account.ID, order.ID)
+	} else {
+		err = service.ErrOrderNotFound
+	}
 	if err != nil {
 		c.JSON(400, gin.H{"code": -2011, "msg": "Unknown order sent."})
 		return
 	}
 
-	c.JSON(200, gin.H{
-		"orderId":       order.ID,
-		"symbol":        order.Symbol,
-		"status":        "CANCELED",
-		"clientOrderId": order.ClientOrderID,
-		"origQty":       strconv.FormatFloat(order.Quantity, 'f', 8, 64),
-		"executedQty":   strconv.FormatFloat(order.FilledQty, 'f', 8, 64),
-		"type":          string(order.Type),
-		"side":          string(order.Side),
-		"updateTime":    time.Now().UnixMilli(),
+	c.JSON(200, h.formatOrder(order))
+}
+
+// CancelBatchOrders handles DELETE /fapi/v1/batchOrders
+// Orders are canceled independently; a failed cancel is reported in its slot as an error object
+func (h *Handler) CancelBatchOrders(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"code": -2015, "msg": "Invalid API-key."})
+		return
+	}
+
+	symbol := c.Query("symbol")
+	if symbol == "" {
+		c.JSON(400, gin.H{"code": -1102, "msg": "Mandatory parameter 'symbol' was not sent."})
+		return
+	}
+
+	var orders []*models.Order
+	var orderIDs []uint
+	var clientOrderIDs []string
+	if raw := c.Query("orderIdList"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &orderIDs); err != nil {
+			c.JSON(400, gin.H{"code": -1130, "msg": "Data sent for parameter 'orderIdList' is not valid."})
+			return
+		}
+		for _, orderID := range orderIDs {
+			order, _ := h.tradingService.GetOrderStatus(account.ID, orderID)
+			orders = append(orders, order)
+		}
+	} else if raw := c.Query("origClientOrderIdList"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &clientOrderIDs); err != nil {
+			c.JSON(400, gin.H{"code": -1130, "msg": "Data sent for parameter 'origClientOrderIdList' is not valid."})
+			return
+		}
+		for _, clientOrderID := range clientOrderIDs {
+			order, _ := h.tradingService.GetOrderByClientID(account.ID, clientOrderID)
+			orders = append(orders, order)
+		}
+	}
+
+	if len(orders) == 0 {
+		c.JSON(400, gin.H{"code": -1102, "msg": "Param orderIdList or origClientOrderIdList must be sent, but both were empty/null!"})
+		return
+	}
+	if len(orders) > maxBatchCancels {
+		c.JSON(400, gin.H{"code": -1130, "msg": "Data sent for parameter 'orderIdList' is not valid."})
+		return
+	}
+
+	result := make([]gin.H, 0, len(orders))
+	for _, order := range orders {
+		if order == nil || order.Symbol != symbol {
+			result = append(result, gin.H{"code": -2011, "msg": "Unknown order sent."})
+			continue
+		}
+		canceled, err := h.tradingService.CancelOrder(account.ID, order.ID)
+		if err != nil {
+			result = append(result, gin.H{"code": -2011, "msg": "Unknown order sent."})
+			continue
+		}
+		result = append(result, h.formatOrder(canceled))
+	}
+
+	c.JSON(200, result)
+}
+
+// ModifyOrder handles PUT /fapi/v1/order
+// Only NEW limit orders can be modified; price and quantity are both required, like Binance
+func (h *Handler) ModifyOrder(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"code": -2015, "msg": "Invalid API-key."})
+		return
+	}
+
+	symbol := c.PostForm("symbol")
+	if symbol == "" {
+		c.JSON(400, gin.H{"code": -1102, "msg": "Mandatory parameter 'symbol' was not sent."})
+		return
+	}
+	quantity, qtyErr := strconv.ParseFloat(c.PostForm("quantity"), 64)
+	price, priceErr := strconv.ParseFloat(c.PostForm("price"), 64)
+	if qtyErr != nil || priceErr != nil {
+		c.JSON(400, gin.H{"code": -1102, "msg": "Mandatory parameter 'quantity' or 'price' was not sent."})
+		return
+	}
+
+	var order *models.Order
+	var err error
+	if clientOrderID := c.PostForm("origClientOrderId"); clientOrderID != "" {
+		order, err = h.tradingService.GetOrderByClientID(account.ID, clientOrderID)
+	} else {
+		orderID, _ := strconv.ParseUint(c.PostForm("orderId"), 10, 64)
+		order, err = h.tradingService.GetOrderStatus(account.ID, uint(orderID))
+	}
+	if err != nil || order.Symbol != symbol {
+		c.JSON(400, gin.H{"code": -2013, "msg": "Order does not exist."})
+		return
+	}
+	if order.Type != models.OrderTypeLimit {
+		c.JSON(400, gin.H{"code": -4008, "msg": "Only limit orders can be modified."})
+		return
+	}
+
+	order, err = h.tradingService.AmendOrder(account.ID, order.ID, &service.AmendOrderRequest{
+		Price:    &price,
+		Quantity: &quantity,
 	})
+	if err != nil {
+		switch err {
+		case service.ErrOrderNotOpen:
+			c.JSON(400, gin.H{"code": -2013, "msg": "Order does not exist."})
+		case service.ErrOrderNotModified:
+			c.JSON(400, gin.H{"code": -5027, "msg": "No need to modify the order."})
+		case service.ErrInvalidPrice:
+			c.JSON(400, gin.H{"code": -4014, "msg": "Price not increased by tick size."})
+		default:
+			h.handleError(c, err)
+		}
+		return
+	}
+
+	c.JSON(200, h.formatOrder(order))
 }
 
 // CancelAllOpenOrders handles DELETE /fapi/v1/allOpenOrders
@@ -680,10 +1053,8 @@ func (h *Handler) GetQueryOrder(c *gin.Context) {
 		return
 	}
 
-	orderIDStr := c.Query("orderId")
-	orderID, _ := strconv.ParseUint(orderIDStr, 10, 64)
-
-	order, err := h.tradingService.GetOrderStatus(account.ID, uint(orderID))
+	orderID, _ := strconv.ParseUint(c.Query("orderId"), 10, 64)
+	order, err := h.tradingService.FindOrder(account.ID, uint(orderID), c.Query("origClientOrderId"))
 	if err != nil {
 		c.JSON(400, gin.H{"code": -2013, "msg": "Order does not exist."})
 		return
@@ -692,6 +1063,273 @@ func (h *Handler) GetQueryOrder(c *gin.Context) {
 	c.JSON(200, h.formatOrder(order))
 }
 
+// GetForceOrders handles GET /fapi/v1/forceOrders
+func (h *Handler) GetForceOrders(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"code": -2015, "msg": "Invalid API-key."})
+		return
+	}
+
+	symbol := c.Query("symbol")
+	autoCloseType := c.Query("autoCloseType")
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	result := make([]gin.H, 0)
+
+	// ADL is not simulated, only liquidation orders exist
+	if autoCloseType != "" && autoCloseType != "LIQUIDATION" {
+		c.JSON(200, result)
+		return
+	}
+
+	orders, err := h.tradingService.GetForceOrders(account.ID, symbol, limit)
+	if err != nil {
+		c.JSON(500, gin.H{"code": -1, "msg": err.Error()})
+		return
+	}
+
+	for _, order := range orders {
+		result = append(result, h.formatOrder(&order))
+	}
+
+	c.JSON(200, result)
+}
+
+// GetUserTrades handles GET /fapi/v1/userTrades
+// Without fromId or startTime the most recent trades are returned; results are oldest first
+func (h *Handler) GetUserTrades(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"code": -2015, "msg": "Invalid API-key."})
+		return
+	}
+
+	q, ok := h.historyQuery(c, "fromId")
+	if !ok {
+		return
+	}
+	if orderID, err := strconv.ParseUint(c.Query("orderId"), 10, 64); err == nil {
+		q.OrderID = uint(orderID)
+	}
+
+	trades, err := h.tradingService.GetTradeHistory(account.ID, q)
+	if err != nil {
+		c.JSON(500, gin.H{"code": -1, "msg": err.Error()})
+		return
+	}
+
+	result := make([]gin.H, len(trades))
+	for i, trade := range trades {
+		// Newest-first pages are reversed so every response is oldest first
+		j := i
+		if !q.Ascending {
+			j = len(trades) - 1 - i
+		}
+		result[j] = gin.H{
+			"symbol":          trade.Symbol,
+			"id":              trade.ID,
+			"orderId":         trade.OrderID,
+			"side":            string(trade.Side),
+			"price":           strconv.FormatFloat(trade.Price, 'f', 8, 64),
+			"qty":             strconv.FormatFloat(trade.Quantity, 'f', 8, 64),
+			"realizedPnl":     strconv.FormatFloat(trade.RealizedPnL, 'f', 8, 64),
+			"quoteQty":        strconv.FormatFloat(trade.Price*trade.Quantity, 'f', 8, 64),
+			"commission":      strconv.FormatFloat(trade.Fee, 'f', 8, 64),
+			"commissionAsset": trade.FeeCurrency,
+			"time":            trade.ExecutedAt.UnixMilli(),
+			"positionSide":    string(trade.Order.PositionSide),
+			"buyer":           trade.Side == models.OrderSideBuy,
+			"maker":           trade.IsMaker,
+		}
+	}
+
+	c.JSON(200, result)
+}
+
+// GetAllOrders handles GET /fapi/v1/allOrders
+// Without orderId or startTime the most recent orders are returned; results are oldest first
+func (h *Handler) GetAllOrders(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"code": -2015, "msg": "Invalid API-key."})
+		return
+	}
+
+	q, ok := h.historyQuery(c, "orderId")
+	if !ok {
+		return
+	}
+
+	orders, err := h.tradingService.GetOrderHistoryPage(account.ID, q)
+	if err != nil {
+		c.JSON(500, gin.H{"code": -1, "msg": err.Error()})
+		return
+	}
+
+	result := make([]gin.H, len(orders))
+	for i := range orders {
+		j := i
+		if !q.Ascending {
+			j = len(orders) - 1 - i
+		}
+		result[j] = h.formatOrder(&orders[i])
+	}
+
+	c.JSON(200, result)
+}
+
+// historyQuery parses the symbol, time window, limit and the inclusive ID cursor (fromId/orderId)
+// shared by userTrades and allOrders; it writes the error response when symbol is missing
+func (h *Handler) historyQuery(c *gin.Context, cursorParam string) (repository.HistoryQuery, bool) {
+	q := repository.HistoryQuery{Symbol: c.Query("symbol")}
+	if q.Symbol == "" {
+		c.JSON(400, gin.H{"code": -1102, "msg": "Mandatory parameter 'symbol' was not sent, was empty/null, or malformed."})
+		return q, false
+	}
+
+	q.Limit, _ = strconv.Atoi(c.Query("limit"))
+	if q.Limit <= 0 {
+		q.Limit = 500
+	}
+	if q.Limit > 1000 {
+		q.Limit = 1000
+	}
+
+	if ms, err := strconv.ParseInt(c.Query("startTime"), 10, 64); err == nil {
+		q.Start = time.UnixMilli(ms)
+		q.Ascending = true
+	}
+	if ms, err := strconv.ParseInt(c.Query("endTime"), 10, 64); err == nil {
+		q.End = time.UnixMilli(ms)
+	}
+	if id, err := strconv.ParseUint(c.Query(cursorParam), 10, 64); err == nil && id > 0 {
+		q.AfterID = uint(id) - 1
+		q.Ascending = true
+	}
+
+	return q, true
+}
+
+// GetIncome handles GET /fapi/v1/income
+// REALIZED_PNL and COMMISSION come from trades, FUNDING_FEE from the funding ledger
+// The most recent records in the window are paged with page/limit and returned oldest first
+func (h *Handler) GetIncome(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"code": -2015, "msg": "Invalid API-key."})
+		return
+	}
+
+	symbol := c.Query("symbol")
+	incomeType := c.Query("incomeType")
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page <= 0 {
+		page = 1
+	}
+
+	var startTime, endTime time.Time
+	if ms, err := strconv.ParseInt(c.Query("startTime"), 10, 64); err == nil {
+		startTime = time.UnixMilli(ms)
+	}
+	if ms, err := strconv.ParseInt(c.Query("endTime"), 10, 64); err == nil {
+		endTime = time.UnixMilli(ms)
+	}
+
+	type income struct {
+		at   time.Time
+		data gin.H
+	}
+	records := make([]income, 0)
+
+	if incomeType == "" || incomeType == "REALIZED_PNL" || incomeType == "COMMISSION" {
+		trades, err := h.tradingService.GetTradeHistory(account.ID, repository.HistoryQuery{
+			Symbol: symbol,
+			Start:  startTime,
+			End:    endTime,
+			Limit:  page * limit,
+		})
+		if err != nil {
+			c.JSON(500, gin.H{"code": -1, "msg": err.Error()})
+			return
+		}
+
+		for _, trade := range trades {
+			tradeID := strconv.Itoa(int(trade.ID))
+			if (incomeType == "" || incomeType == "REALIZED_PNL") && trade.RealizedPnL != 0 {
+				records = append(records, income{trade.ExecutedAt, gin.H{
+					"symbol":     trade.Symbol,
+					"incomeType": "REALIZED_PNL",
+					"income":     strconv.FormatFloat(trade.RealizedPnL, 'f', 8, 64),
+					"asset":      "USDT",
+					"info":       "",
+					"time":       trade.ExecutedAt.UnixMilli(),
+					"tranId":     trade.ID,
+					"tradeId":    tradeID,
+				}})
+			}
+			if (incomeType == "" || incomeType == "COMMISSION") && trade.Fee != 0 {
+				records = append(records, income{trade.ExecutedAt, gin.H{
+					"symbol":     trade.Symbol,
+					"incomeType": "COMMISSION",
+					"income":     strconv.FormatFloat(-trade.Fee, 'f', 8, 64),
+					"asset":      trade.FeeCurrency,
+					"info":       "",
+					"time":       trade.ExecutedAt.UnixMilli(),
+					"tranId":     trade.ID,
+					"tradeId":    tradeID,
+				}})
+			}
+		}
+	}
+
+	if incomeType == "" || incomeType == "FUNDING_FEE" {
+		fees, err := h.fundingService.GetFundingFees(account.ID, symbol, startTime, endTime, page*limit)
+		if err != nil {
+			c.JSON(500, gin.H{"code": -1, "msg": err.Error()})
+			return
+		}
+
+		for _, fee := range fees {
+			records = append(records, income{fee.FundingTime, gin.H{
+				"symbol":     fee.Symbol,
+				"incomeType": "FUNDING_FEE",
+				"income":     strconv.FormatFloat(fee.Amount, 'f', 8, 64),
+				"asset":      "USDT",
+				"info":       "FUNDING_FEE",
+				"time":       fee.FundingTime.UnixMilli(),
+				"tranId":     fee.ID,
+				"tradeId":    "",
+			}})
+		}
+	}
+
+	// Page through the merged ledger newest first, then present the page oldest first
+	sort.SliceStable(records, func(i, j int) bool { return records[i].at.After(records[j].at) })
+	from := min((page-1)*limit, len(records))
+	to := min(from+limit, len(records))
+
+	result := make([]gin.H, 0, to-from)
+	for i := to - 1; i >= from; i-- {
+		result = append(result, records[i].data)
+	}
+
+	c.JSON(200, result)
+}
+
 // GetExchangeInfo handles GET /fapi/v1/exchangeInfo
 func (h *Handler) GetExchangeInfo(c *gin.Context) {
 	if h.exchangeInfoService != nil {
@@ -715,40 +1353,141 @@ func (h *Handler) GetExchangeInfo(c *gin.Context) {
 
 // formatOrder formats an order for Binance response
 func (h *Handler) formatOrder(order *models.Order) gin.H {
-	return gin.H{
+	orderType := orderTypeName(order)
+	timeInForce := order.TimeInForce
+	if timeInForce == "" {
+		timeInForce = "GTC"
+	}
+	var goodTillDate int64
+	if order.GoodTillDate != nil {
+		goodTillDate = order.GoodTillDate.UnixMilli()
+	}
+
+	result := gin.H{
 		"orderId":       order.ID,
 		"symbol":        order.Symbol,
-		"status":        string(order.Status),
+		"status":        orderStatusName(order.Status),
 		"clientOrderId": order.ClientOrderID,
 		"price":         strconv.FormatFloat(order.Price, 'f', 8, 64),
 		"avgPrice":      strconv.FormatFloat(order.AvgPrice, 'f', 8, 64),
 		"origQty":       strconv.FormatFloat(order.Quantity, 'f', 8, 64),
 		"executedQty":   strconv.FormatFloat(order.FilledQty, 'f', 8, 64),
 		"cumQuote":      strconv.FormatFloat(order.AvgPrice*order.FilledQty, 'f', 8, 64),
-		"type":          string(order.Type),
+		"type":          orderType,
+		"origType":      orderType,
+		"timeInForce":   timeInForce,
+		"goodTillDate":  goodTillDate,
 		"side":          string(order.Side),
 		"positionSide":  string(order.PositionSide),
 		"stopPrice":     strconv.FormatFloat(order.StopPrice, 'f', 8, 64),
+		"workingType":   workingTypeName(order),
 		"reduceOnly":    order.ReduceOnly,
 		"closePosition": order.ClosePosition,
 		"time":          order.CreatedAt.UnixMilli(),
 		"updateTime":    order.UpdatedAt.UnixMilli(),
 	}
+	addTrailingStop(result, order)
+	addTriggeredOrder(result, order)
+	return result
+}
+
+// orderTypeName returns the Binance order type of an order
+// Conditional orders with a limit price are STOP or TAKE_PROFIT, those closing at market the _MARKET types
+func orderTypeName(order *models.Order) string {
+	switch {
+	case order.IsLiquidation():
+		// Binance reports liquidation orders as IOC LIMIT orders with an "autoclose-" client ID
+		return string(models.OrderTypeLimit)
+	case order.Type == models.OrderTypeStopMarket && order.Price > 0:
+		return "STOP"
+	case order.Type == models.OrderTypeTakeProfit && order.Price == 0:
+		return "TAKE_PROFIT_MARKET"
+	}
+	return string(order.Type)
+}
+
+// parseWorkingType maps a Binance workingType to the trigger price type of a conditional order
+// CONTRACT_PRICE (the default) triggers on the last price and MARK_PRICE on the mark price
+func parseWorkingType(workingType string) (models.TriggerPriceType, bool) {
+	switch workingType {
+	case "", "CONTRACT_PRICE":
+		return models.TriggerPriceLast, true
+	case "MARK_PRICE":
+		return models.TriggerPriceMark, true
+	default:
+		return "", false
+	}
+}
+
+// orderStatusName returns the Binance status of an order
+// A triggered STOP or TAKE_PROFIT order expires into the limit order it placed
+func orderStatusName(status models.OrderStatus) string {
+	if status == models.OrderStatusTriggered {
+		return "EXPIRED"
+	}
+	return string(status)
+}
+
+// workingTypeName returns the Binance workingType of an order
+func workingTypeName(order *models.Order) string {
+	if order.TriggerPriceType == models.TriggerPriceMark {
+		return "MARK_PRICE"
+	}
+	return "CONTRACT_PRICE"
+}
+
+// addTriggeredOrder links a triggered STOP or TAKE_PROFIT order to the limit order it placed
+func addTriggeredOrder(result gin.H, order *models.Order) {
+	if order.TriggeredOrderID != nil {
+		result["actualOrderId"] = *order.TriggeredOrderID
+	}
+}
+
+// addTrailingStop adds the activation price and callback rate of a trailing stop to an order response
+// watermarkPrice is a simulator extension: the best price since activation that the stop trails
+func addTrailingStop(result gin.H, order *models.Order) {
+	if order.Type != models.OrderTypeTrailingStop {
+		return
+	}
+	result["activatePrice"] = strconv.FormatFloat(order.ActivationPrice, 'f', 8, 64)
+	result["priceRate"] = strconv.FormatFloat(order.CallbackRate*100, 'f', 2, 64)
+	result["watermarkPrice"] = strconv.FormatFloat(order.Watermark, 'f', 8, 64)
 }
 
 // handleError maps service errors to Binance error codes
 func (h *Handler) handleError(c *gin.Context, err error) {
+	c.JSON(errorBody(err))
+}
+
+// errorBody returns the HTTP status and Binance error payload for a service error
+func errorBody(err error) (int, gin.H) {
 	switch err {
+	case errMissingSymbol:
+		return 400, gin.H{"code": -1102, "msg": "Mandatory parameter 'symbol' was not sent."}
 	case service.ErrInsufficientBalance:
-		c.JSON(400, gin.H{"code": -2019, "msg": "Margin is insufficient."})
+		return 400, gin.H{"code": -2019, "msg": "Margin is insufficient."}
 	case service.ErrInvalidSymbol:
-		c.JSON(400, gin.H{"code": -1121, "msg": "Invalid symbol."})
+		return 400, gin.H{"code": -1121, "msg": "Invalid symbol."}
 	case service.ErrInvalidQuantity:
-		c.JSON(400, gin.H{"code": -1013, "msg": "Invalid quantity."})
+		return 400, gin.H{"code": -1013, "msg": "Invalid quantity."}
 	case service.ErrNoOpenPosition:
-		c.JSON(400, gin.H{"code": -2022, "msg": "Position side not match."})
+		return 400, gin.H{"code": -2022, "msg": "Position side not match."}
+	case service.ErrDuplicateClientID:
+		return 400, gin.H{"code": -4116, "msg": "ClientOrderId is duplicated."}
+	case service.ErrInvalidCallback:
+		return 400, gin.H{"code": -1102, "msg": "Mandatory parameter 'callbackRate' was not sent, was empty/null, or malformed."}
+	case service.ErrInvalidTrigger:
+		return 400, gin.H{"code": -1102, "msg": "Mandatory parameter 'workingType' was not sent, was empty/null, or malformed."}
+	case service.ErrInvalidTimeInForce:
+		return 400, gin.H{"code": -1115, "msg": "Invalid timeInForce."}
+	case service.ErrInvalidGoodTillDate:
+		return 400, gin.H{"code": -5040, "msg": "The goodTillDate timestamp must be greater than the current time."}
+	case service.ErrFOKNotFilled:
+		return 400, gin.H{"code": -5021, "msg": "Due to the order could not be filled immediately, the FOK order has been rejected. The order will not be recorded in the order history"}
+	case service.ErrPostOnlyWouldTake:
+		return 400, gin.H{"code": -5022, "msg": "Due to the order could not be executed as maker, the Post Only order will be rejected. The order will not be recorded in the order history"}
 	default:
-		c.JSON(500, gin.H{"code": -1, "msg": err.Error()})
+		return 500, gin.H{"code": -1, "msg": err.Error()}
 	}
 }
 
@@ -762,6 +1501,10 @@ func (h *Handler) RegisterRoutes(router *gin.Engine, authMiddleware gin.HandlerF
 	fapi.GET("/v1/premiumIndex", h.GetMarkPrice)
 	fapi.GET("/v2/ticker/price", h.GetTickerPrice)
 
+	// User data stream (authenticated by the listen key itself) and public market streams
+	router.GET("/ws/:listenKey", h.UserDataStream)
+	router.GET("/stream", h.CombinedMarketStream)
+
 	// Private endpoints (require auth)
 	fapi.Use(authMiddleware)
 	{
@@ -771,11 +1514,23 @@ func (h *Handler) RegisterRoutes(router *gin.Engine, authMiddleware gin.HandlerF
 			// Order endpoints with trading logging
 			v1.POST("/order", middleware.TradingLoggerMiddleware(), h.CreateOrder)
 			v1.DELETE("/order", middleware.TradingLoggerMiddleware(), h.CancelOrder)
+			v1.PUT("/order", middleware.TradingLoggerMiddleware(), h.ModifyOrder)
+			v1.POST("/batchOrders", middleware.TradingLoggerMiddleware(), h.CreateBatchOrders)
+			v1.DELETE("/batchOrders", middleware.TradingLoggerMiddleware(), h.CancelBatchOrders)
 			v1.GET("/order", h.GetQueryOrder)
 			v1.GET("/openOrders", h.GetOpenOrders)
+			v1.GET("/forceOrders", h.GetForceOrders)
+			v1.GET("/userTrades", h.GetUserTrades)
+			v1.GET("/allOrders", h.GetAllOrders)
+			v1.GET("/income", h.GetIncome)
+			// User data stream listen key
+			v1.POST("/listenKey", h.CreateListenKey)
+			v1.PUT("/listenKey", h.KeepaliveListenKey)
+			v1.DELETE("/listenKey", h.CloseListenKey)
 			v1.DELETE("/allOpenOrders", middleware.TradingLoggerMiddleware(), h.CancelAllOpenOrders)
 			v1.POST("/leverage", middleware.TradingLoggerMiddleware(), h.SetLeverage)
 			v1.POST("/marginType", middleware.TradingLoggerMiddleware(), h.SetMarginType)
+			v1.POST("/positionMargin", middleware.TradingLoggerMiddleware(), h.AdjustPositionMargin)
 			// Algo orders (SL/TP) with trading logging
 			v1.POST("/algoOrder", middleware.TradingLoggerMiddleware(), h.CreateAlgoOrder)
 			v1.DELETE("/algoOrder", middleware.TradingLoggerMiddleware(), h.CancelAlgoOrder)
@@ -0,0 +1,239 @@
+package binance
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ccxt-simulator/internal/exchange"
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// marketStreamRequest is a live SUBSCRIBE/UNSUBSCRIBE request
+type marketStreamRequest struct {
+	Method string          `json:"method"`
+	Params []string        `json:"params"`
+	ID     json.RawMessage `json:"id"`
+}
+
+// marketSession is a single public market stream connection
+type marketSession struct {
+	conn     *websocket.Conn
+	combined bool // Wrap payloads as {"stream","data"} (/stream endpoint)
+	writeMu  sync.Mutex
+
+	streams  map[string]time.Time // stream name -> last push time
+	streamMu sync.Mutex
+}
+
+func (s *marketSession) write(v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.conn.SetWriteDeadline(time.Now().Add(userStreamWriteWait))
+	return s.conn.WriteJSON(v)
+}
+
+// streamInterval returns the push interval of a supported stream name
+// <symbol>@markPrice pushes every 3s, <symbol>@markPrice@1s every second and
+// <symbol>@bookTicker on every tick
+func streamInterval(name string) (time.Duration, bool) {
+	parts := strings.Split(name, "@")
+	if len(parts) < 2 || parts[0] == "" {
+		return 0, false
+	}
+	switch strings.Join(parts[1:], "@") {
+	case "markPrice":
+		return 3 * time.Second, true
+	case "markPrice@1s":
+		return time.Second, true
+	case "bookTicker":
+		return 0, true
+	}
+	return 0, false
+}
+
+// MarketStream serves public market streams; streams are given by the raw
+// /ws/<streamName> path, the combined /stream?streams=a/b query or live SUBSCRIBE requests
+func (h *Handler) MarketStream(c *gin.Context, streams []string, combined bool) {
+	for _, name := range streams {
+		if _, ok := streamInterval(name); !ok {
+			c.JSON(400, gin.H{"code": -1121, "msg": "Invalid stream name: " + name})
+			return
+		}
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	session := &marketSession{
+		conn:     conn,
+		combined: combined,
+		streams:  make(map[string]time.Time),
+	}
+	for _, name := range streams {
+		session.streams[name] = time.Time{}
+	}
+
+	ticks := h.priceService.MarketData().Subscribe("binance")
+	defer h.priceService.MarketData().Unsubscribe("binance", ticks)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			h.handleMarketRequest(session, message)
+		}
+	}()
+
+	pingTicker := time.NewTicker(userStreamPingEvery)
+	defer pingTicker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-pingTicker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(userStreamWriteWait)); err != nil {
+				return
+			}
+		case update := <-ticks:
+			if err := h.pushMarketUpdate(session, update); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// CombinedMarketStream handles GET /stream?streams=<a>/<b>
+func (h *Handler) CombinedMarketStream(c *gin.Context) {
+	var streams []string
+	for _, name := range strings.Split(c.Query("streams"), "/") {
+		if name != "" {
+			streams = append(streams, name)
+		}
+	}
+	h.MarketStream(c, streams, true)
+}
+
+// handleMarketRequest processes a live SUBSCRIBE/UNSUBSCRIBE/LIST_SUBSCRIPTIONS request
+func (h *Handler) handleMarketRequest(session *marketSession, message []byte) {
+	var req marketStreamRequest
+	if err := json.Unmarshal(message, &req); err != nil {
+		session.write(gin.H{"error": gin.H{"code": 3, "msg": "Invalid JSON"}, "id": nil})
+		return
+	}
+
+	switch req.Method {
+	case "SUBSCRIBE", "UNSUBSCRIBE":
+		for _, name := range req.Params {
+			if _, ok := streamInterval(name); !ok {
+				session.write(gin.H{"error": gin.H{"code": 2, "msg": "Invalid request: unknown stream " + name}, "id": req.ID})
+				return
+			}
+		}
+		session.streamMu.Lock()
+		for _, name := range req.Params {
+			if req.Method == "SUBSCRIBE" {
+				session.streams[name] = time.Time{}
+			} else {
+				delete(session.streams, name)
+			}
+		}
+		session.streamMu.Unlock()
+		session.write(gin.H{"result": nil, "id": req.ID})
+
+	case "LIST_SUBSCRIPTIONS":
+		session.streamMu.Lock()
+		names := make([]string, 0, len(session.streams))
+		for name := range session.streams {
+			names = append(names, name)
+		}
+		session.streamMu.Unlock()
+		session.write(gin.H{"result": names, "id": req.ID})
+
+	default:
+		session.write(gin.H{"error": gin.H{"code": 2, "msg": "Invalid request: unknown method"}, "id": req.ID})
+	}
+}
+
+// pushMarketUpdate pushes a tick to every subscribed stream of its symbol that is due
+func (h *Handler) pushMarketUpdate(session *marketSession, update exchange.PriceUpdate) error {
+	symbol := strings.ToLower(update.Symbol)
+	now := time.Now()
+
+	session.streamMu.Lock()
+	var due []string
+	for name, last := range session.streams {
+		if !strings.HasPrefix(name, symbol+"@") {
+			continue
+		}
+		interval, _ := streamInterval(name)
+		if now.Sub(last) < interval {
+			continue
+		}
+		session.streams[name] = now
+		due = append(due, name)
+	}
+	session.streamMu.Unlock()
+
+	for _, name := range due {
+		var data gin.H
+		if strings.HasSuffix(name, "@bookTicker") {
+			data = formatBookTicker(update)
+		} else {
+			data = formatMarkPriceUpdate(update)
+		}
+		payload := data
+		if session.combined {
+			payload = gin.H{"stream": name, "data": data}
+		}
+		if err := session.write(payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatMarkPriceUpdate formats a tick as a markPriceUpdate event
+// Feeds without a mark or index stream report their main price for both
+func formatMarkPriceUpdate(update exchange.PriceUpdate) gin.H {
+	interval := models.FundingInterval(models.ExchangeBinance)
+	nextFunding := time.Now().UTC().Truncate(interval).Add(interval)
+
+	return gin.H{
+		"e": "markPriceUpdate",
+		"E": time.Now().UnixMilli(),
+		"s": update.Symbol,
+		"p": strconv.FormatFloat(update.Mark(), 'f', 8, 64),
+		"i": strconv.FormatFloat(update.Index(), 'f', 8, 64),
+		"P": strconv.FormatFloat(update.Index(), 'f', 8, 64),
+		"r": strconv.FormatFloat(models.DefaultFundingRate, 'f', 8, 64),
+		"T": nextFunding.UnixMilli(),
+	}
+}
+
+// formatBookTicker formats a tick as a bookTicker event
+func formatBookTicker(update exchange.PriceUpdate) gin.H {
+	return gin.H{
+		"e": "bookTicker",
+		"u": update.Timestamp,
+		"E": time.Now().UnixMilli(),
+		"T": update.Timestamp,
+		"s": update.Symbol,
+		"b": strconv.FormatFloat(update.BestBid(), 'f', 8, 64),
+		"B": "0",
+		"a": strconv.FormatFloat(update.BestAsk(), 'f', 8, 64),
+		"A": "0",
+	}
+}
@@ -0,0 +1,403 @@
+package binance
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ccxt-simulator/internal/middleware"
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/ccxt-simulator/internal/service"
+	"github.com/ccxt-simulator/pkg/keygen"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	listenKeyTTL        = 60 * time.Minute
+	userStreamPingEvery = 3 * time.Minute
+	userStreamWriteWait = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// listenKey is an active user data stream session
+type listenKey struct {
+	accountID uint
+	expiresAt time.Time
+	closed    chan struct{} // Closed when the key is deleted or expires
+}
+
+// listenKeyStore tracks listen keys; each account has at most one active key
+type listenKeyStore struct {
+	keys      map[string]*listenKey
+	byAccount map[uint]string
+	mu        sync.Mutex
+}
+
+func newListenKeyStore() *listenKeyStore {
+	return &listenKeyStore{
+		keys:      make(map[string]*listenKey),
+		byAccount: make(map[uint]string),
+	}
+}
+
+// getOrCreate returns the account's active key (extending it) or creates a new one
+func (s *listenKeyStore) getOrCreate(accountID uint) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.byAccount[accountID]; ok {
+		if entry := s.keys[key]; entry != nil && time.Now().Before(entry.expiresAt) {
+			entry.expiresAt = time.Now().Add(listenKeyTTL)
+			return key, nil
+		}
+		s.removeLocked(key)
+	}
+
+	key, err := keygen.GenerateListenKey()
+	if err != nil {
+		return "", err
+	}
+	s.keys[key] = &listenKey{
+		accountID: accountID,
+		expiresAt: time.Now().Add(listenKeyTTL),
+		closed:    make(chan struct{}),
+	}
+	s.byAccount[accountID] = key
+	return key, nil
+}
+
+// keepalive extends the account's active key
+func (s *listenKeyStore) keepalive(accountID uint) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.byAccount[accountID]
+	if !ok {
+		return "", false
+	}
+	entry := s.keys[key]
+	if entry == nil || time.Now().After(entry.expiresAt) {
+		s.removeLocked(key)
+		return "", false
+	}
+	entry.expiresAt = time.Now().Add(listenKeyTTL)
+	return key, true
+}
+
+// closeAccount deletes the account's active key and disconnects its streams
+func (s *listenKeyStore) closeAccount(accountID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.byAccount[accountID]; ok {
+		s.removeLocked(key)
+	}
+}
+
+// get returns a key if it exists and has not expired
+func (s *listenKeyStore) get(key string) (*listenKey, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.keys[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		s.removeLocked(key)
+		return nil, false
+	}
+	return entry, true
+}
+
+func (s *listenKeyStore) removeLocked(key string) {
+	entry, ok := s.keys[key]
+	if !ok {
+		return
+	}
+	close(entry.closed)
+	delete(s.keys, key)
+	if s.byAccount[entry.accountID] == key {
+		delete(s.byAccount, entry.accountID)
+	}
+}
+
+// CreateListenKey handles POST /fapi/v1/listenKey
+func (h *Handler) CreateListenKey(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"code": -2015, "msg": "Invalid API-key."})
+		return
+	}
+
+	key, err := h.listenKeys.getOrCreate(account.ID)
+	if err != nil {
+		c.JSON(500, gin.H{"code": -1, "msg": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"listenKey": key})
+}
+
+// KeepaliveListenKey handles PUT /fapi/v1/listenKey
+func (h *Handler) KeepaliveListenKey(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"code": -2015, "msg": "Invalid API-key."})
+		return
+	}
+
+	key, ok := h.listenKeys.keepalive(account.ID)
+	if !ok {
+		c.JSON(400, gin.H{"code": -1125, "msg": "This listenKey does not exist."})
+		return
+	}
+
+	c.JSON(200, gin.H{"listenKey": key})
+}
+
+// CloseListenKey handles DELETE /fapi/v1/listenKey
+func (h *Handler) CloseListenKey(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"code": -2015, "msg": "Invalid API-key."})
+		return
+	}
+
+	h.listenKeys.closeAccount(account.ID)
+	c.JSON(200, gin.H{})
+}
+
+// UserDataStream handles GET /ws/:listenKey (WebSocket user data stream)
+// Public stream names (e.g. btcusdt@bookTicker) share the path and are served as market streams
+func (h *Handler) UserDataStream(c *gin.Context) {
+	key := c.Param("listenKey")
+	if strings.Contains(key, "@") {
+		h.MarketStream(c, []string{key}, false)
+		return
+	}
+
+	entry, ok := h.listenKeys.get(key)
+	if !ok {
+		c.JSON(400, gin.H{"code": -1125, "msg": "This listenKey does not exist."})
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	events := h.tradingService.Events().Subscribe(entry.accountID)
+	defer h.tradingService.Events().Unsubscribe(entry.accountID, events)
+
+	// Client messages are ignored; the read loop only detects disconnects
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	pingTicker := time.NewTicker(userStreamPingEvery)
+	defer pingTicker.Stop()
+	expiryTimer := time.NewTimer(time.Until(entry.expiresAt))
+	defer expiryTimer.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-entry.closed:
+			h.writeListenKeyExpired(conn, key)
+			return
+		case <-expiryTimer.C:
+			// Keepalive may have extended the key since the timer was set
+			if current, ok := h.listenKeys.get(key); ok {
+				expiryTimer.Reset(time.Until(current.expiresAt))
+				continue
+			}
+			h.writeListenKeyExpired(conn, key)
+			return
+		case <-pingTicker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(userStreamWriteWait)); err != nil {
+				return
+			}
+		case event := <-events:
+			conn.SetWriteDeadline(time.Now().Add(userStreamWriteWait))
+			if err := conn.WriteJSON(h.formatUserEvent(event)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h *Handler) writeListenKeyExpired(conn *websocket.Conn, key string) {
+	conn.SetWriteDeadline(time.Now().Add(userStreamWriteWait))
+	conn.WriteJSON(gin.H{
+		"e":         "listenKeyExpired",
+		"E":         time.Now().UnixMilli(),
+		"listenKey": key,
+	})
+}
+
+// formatUserEvent formats a user event as a Binance user data stream payload
+func (h *Handler) formatUserEvent(event service.UserEvent) gin.H {
+	if event.Type == service.UserEventAccountUpdate {
+		return h.formatAccountUpdate(event)
+	}
+	return h.formatOrderTradeUpdate(event)
+}
+
+// formatOrderTradeUpdate formats an ORDER_TRADE_UPDATE event
+func (h *Handler) formatOrderTradeUpdate(event service.UserEvent) gin.H {
+	order := event.Order
+
+	orderType := orderTypeName(order)
+	timeInForce := order.TimeInForce
+	if timeInForce == "" {
+		timeInForce = "GTC"
+	}
+	var goodTillDate int64
+	if order.GoodTillDate != nil {
+		goodTillDate = order.GoodTillDate.UnixMilli()
+	}
+
+	executionType := string(order.Status)
+	switch order.Status {
+	case models.OrderStatusFilled, models.OrderStatusPartiallyFilled:
+		executionType = "TRADE"
+	case models.OrderStatusRejected:
+		executionType = "CANCELED"
+	case models.OrderStatusTriggered:
+		// A triggered STOP or TAKE_PROFIT order expires into the limit order it placed
+		executionType = "EXPIRED"
+	}
+	if event.Liquidation {
+		executionType = "CALCULATED"
+	}
+	if event.Amendment {
+		executionType = "AMENDMENT"
+	}
+
+	var lastQty, lastPrice, commission, realizedPnL float64
+	var tradeID uint
+	var isMaker bool
+	tradeTime := event.Time.UnixMilli()
+	if event.Trade != nil {
+		lastQty = event.Trade.Quantity
+		lastPrice = event.Trade.Price
+		commission = event.Trade.Fee
+		realizedPnL = event.Trade.RealizedPnL
+		tradeID = event.Trade.ID
+		isMaker = event.Trade.IsMaker
+		tradeTime = event.Trade.ExecutedAt.UnixMilli()
+	}
+
+	update := gin.H{
+		"s":   order.Symbol,
+		"c":   order.ClientOrderID,
+		"S":   string(order.Side),
+		"o":   orderType,
+		"f":   timeInForce,
+		"q":   strconv.FormatFloat(order.Quantity, 'f', 8, 64),
+		"p":   strconv.FormatFloat(order.Price, 'f', 8, 64),
+		"ap":  strconv.FormatFloat(order.AvgPrice, 'f', 8, 64),
+		"sp":  strconv.FormatFloat(order.StopPrice, 'f', 8, 64),
+		"x":   executionType,
+		"X":   orderStatusName(order.Status),
+		"i":   order.ID,
+		"l":   strconv.FormatFloat(lastQty, 'f', 8, 64),
+		"z":   strconv.FormatFloat(order.FilledQty, 'f', 8, 64),
+		"L":   strconv.FormatFloat(lastPrice, 'f', 8, 64),
+		"N":   "USDT",
+		"n":   strconv.FormatFloat(commission, 'f', 8, 64),
+		"T":   tradeTime,
+		"t":   tradeID,
+		"b":   "0",
+		"a":   "0",
+		"m":   isMaker,
+		"R":   order.ReduceOnly,
+		"wt":  workingTypeName(order),
+		"ot":  orderType,
+		"ps":  string(order.PositionSide),
+		"cp":  order.ClosePosition,
+		"rp":  strconv.FormatFloat(realizedPnL, 'f', 8, 64),
+		"pP":  false,
+		"si":  0,
+		"ss":  0,
+		"V":   "NONE",
+		"pm":  "NONE",
+		"gtd": goodTillDate,
+	}
+	if order.Type == models.OrderTypeTrailingStop {
+		update["AP"] = strconv.FormatFloat(order.ActivationPrice, 'f', 8, 64)
+		update["cr"] = strconv.FormatFloat(order.CallbackRate*100, 'f', 2, 64)
+	}
+
+	return gin.H{
+		"e": "ORDER_TRADE_UPDATE",
+		"E": event.Time.UnixMilli(),
+		"T": tradeTime,
+		"o": update,
+	}
+}
+
+// formatAccountUpdate formats an ACCOUNT_UPDATE event
+func (h *Handler) formatAccountUpdate(event service.UserEvent) gin.H {
+	positions := make([]gin.H, 0, len(event.Positions))
+	for _, pos := range event.Positions {
+		// Binance API spec: position amount is negative for SHORT positions
+		positionAmt := pos.Quantity
+		if pos.Side == models.PositionSideShort {
+			positionAmt = -pos.Quantity
+		}
+
+		unrealizedPnL := 0.0
+		if pos.Quantity > 0 {
+			if markPrice, err := h.priceService.GetPrice("binance", pos.Symbol); err == nil {
+				unrealizedPnL = pos.CalculateUnrealizedPnL(markPrice)
+			}
+		}
+
+		positions = append(positions, gin.H{
+			"s":   pos.Symbol,
+			"pa":  strconv.FormatFloat(positionAmt, 'f', 8, 64),
+			"ep":  strconv.FormatFloat(pos.EntryPrice, 'f', 8, 64),
+			"bep": strconv.FormatFloat(pos.EntryPrice, 'f', 8, 64),
+			"cr":  "0",
+			"up":  strconv.FormatFloat(unrealizedPnL, 'f', 8, 64),
+			"mt":  string(pos.MarginMode),
+			"iw":  strconv.FormatFloat(isolatedWallet(&pos), 'f', 8, 64),
+			"ps":  string(pos.Side),
+		})
+	}
+
+	return gin.H{
+		"e": "ACCOUNT_UPDATE",
+		"E": event.Time.UnixMilli(),
+		"T": event.Time.UnixMilli(),
+		"a": gin.H{
+			"m": event.Reason,
+			"B": []gin.H{
+				{
+					"a":  "USDT",
+					"wb": strconv.FormatFloat(event.Account.BalanceUSDT, 'f', 8, 64),
+					"cw": strconv.FormatFloat(event.Account.BalanceUSDT, 'f', 8, 64),
+					"bc": "0",
+				},
+			},
+			"P": positions,
+		},
+	}
+}
@@ -2,14 +2,19 @@ package bitget
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ccxt-simulator/internal/middleware"
 	"github.com/ccxt-simulator/internal/models"
+	"github.com/ccxt-simulator/internal/repository"
 	"github.com/ccxt-simulator/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
+// maxBatchOrders is the most orders accepted by batch-place-order
+const maxBatchOrders = 50
+
 // Handler handles Bitget-compatible API requests
 type Handler struct {
 	tradingService      *service.TradingService
@@ -141,6 +146,23 @@ func (h *Handler) GetPositions(c *gin.Context) {
 	})
 }
 
+// placeOrderRequest is the body of POST /api/v2/mix/order/place-order
+// batch-place-order sends the symbol and margin fields once and the rest per order
+type placeOrderRequest struct {
+	Symbol      string `json:"symbol"`
+	ProductType string `json:"productType"`
+	MarginMode  string `json:"marginMode"`
+	MarginCoin  string `json:"marginCoin"`
+	Size        string `json:"size"`
+	Price       string `json:"price"`
+	Side        string `json:"side"`
+	TradeSide   string `json:"tradeSide"`
+	OrderType   string `json:"orderType"`
+	Force       string `json:"force"` // gtc, ioc, fok, post_only
+	ReduceOnly  string `json:"reduceOnly"`
+	ClientOid   string `json:"clientOid"`
+}
+
 // PlaceOrder handles POST /api/v2/mix/order/place-order
 func (h *Handler) PlaceOrder(c *gin.Context) {
 	account := middleware.GetAccount(c)
@@ -149,24 +171,94 @@ func (h *Handler) PlaceOrder(c *gin.Context) {
 		return
 	}
 
+	var req placeOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorResponse(c, "40001", err.Error())
+		return
+	}
+
+	order, err := h.placeOrder(account.ID, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"code":        "00000",
+		"msg":         "success",
+		"requestTime": time.Now().UnixMilli(),
+		"data": gin.H{
+			"orderId":   strconv.Itoa(int(order.ID)),
+			"clientOid": order.ClientOrderID,
+		},
+	})
+}
+
+// BatchPlaceOrder handles POST /api/v2/mix/order/batch-place-order
+// Orders are placed independently and reported in successList or failureList
+func (h *Handler) BatchPlaceOrder(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, "40001", "Invalid API key")
+		return
+	}
+
 	var req struct {
-		Symbol      string `json:"symbol"`
-		ProductType string `json:"productType"`
-		MarginMode  string `json:"marginMode"`
-		MarginCoin  string `json:"marginCoin"`
-		Size        string `json:"size"`
-		Price       string `json:"price"`
-		Side        string `json:"side"`
-		TradeSide   string `json:"tradeSide"`
-		OrderType   string `json:"orderType"`
-		ReduceOnly  string `json:"reduceOnly"`
+		Symbol      string              `json:"symbol"`
+		ProductType string              `json:"productType"`
+		MarginMode  string              `json:"marginMode"`
+		MarginCoin  string              `json:"marginCoin"`
+		OrderList   []placeOrderRequest `json:"orderList"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.errorResponse(c, "40001", err.Error())
 		return
 	}
+	if len(req.OrderList) == 0 || len(req.OrderList) > maxBatchOrders {
+		h.errorResponse(c, "40001", "orderList must contain 1 to 50 orders")
+		return
+	}
+
+	successList := make([]gin.H, 0)
+	failureList := make([]gin.H, 0)
+	for i := range req.OrderList {
+		item := &req.OrderList[i]
+		item.Symbol = req.Symbol
+		item.ProductType = req.ProductType
+		item.MarginMode = req.MarginMode
+		item.MarginCoin = req.MarginCoin
 
+		order, err := h.placeOrder(account.ID, item)
+		if err != nil {
+			code, msg := errorCode(err)
+			failureList = append(failureList, gin.H{
+				"orderId":   "",
+				"clientOid": item.ClientOid,
+				"errorCode": code,
+				"errorMsg":  msg,
+			})
+			continue
+		}
+		successList = append(successList, gin.H{
+			"orderId":   strconv.Itoa(int(order.ID)),
+			"clientOid": order.ClientOrderID,
+		})
+	}
+
+	c.JSON(200, gin.H{
+		"code":        "00000",
+		"msg":         "success",
+		"requestTime": time.Now().UnixMilli(),
+		"data": gin.H{
+			"successList": successList,
+			"failureList": failureList,
+		},
+	})
+}
+
+// placeOrder opens or reduces a position from a Bitget order request
+func (h *Handler) placeOrder(accountID uint, req *placeOrderRequest) (*models.Order, error) {
 	quantity, _ := strconv.ParseFloat(req.Size, 64)
 	price, _ := strconv.ParseFloat(req.Price, 64)
 
@@ -193,45 +285,46 @@ func (h *Handler) PlaceOrder(c *gin.Context) {
 	}
 
 	isClose := req.TradeSide == "close" || req.ReduceOnly == "YES"
+	timeInForce := strings.ToUpper(req.Force)
+	if req.Force == "post_only" {
+		timeInForce = models.TimeInForceGTX
+	}
+
 	var order *models.Order
 	var err error
 
 	if !isClose {
 		openReq := &service.OpenPositionRequest{
-			AccountID: account.ID,
-			Symbol:    req.Symbol,
-			Side:      posSide,
-			Quantity:  quantity,
-			OrderType: orderType,
-			Price:     price,
+			AccountID:     accountID,
+			Symbol:        req.Symbol,
+			Side:          posSide,
+			Quantity:      quantity,
+			OrderType:     orderType,
+			Price:         price,
+			TimeInForce:   timeInForce,
+			ClientOrderID: req.ClientOid,
 		}
 		order, _, err = h.tradingService.OpenPosition(openReq, models.ExchangeBitget)
 	} else {
 		closeReq := &service.ClosePositionRequest{
-			AccountID: account.ID,
-			Symbol:    req.Symbol,
-			Side:      posSide,
-			Quantity:  &quantity,
-			OrderType: orderType,
-			Price:     price,
+			AccountID:     accountID,
+			Symbol:        req.Symbol,
+			Side:          posSide,
+			Quantity:      &quantity,
+			OrderType:     orderType,
+			Price:         price,
+			TimeInForce:   timeInForce,
+			ClientOrderID: req.ClientOid,
 		}
 		order, _, err = h.tradingService.ClosePosition(closeReq, models.ExchangeBitget)
 	}
 
-	if err != nil {
-		h.handleError(c, err)
-		return
+	// Bitget accepts IOC, FOK and post-only orders that cannot be met and cancels them right away
+	if service.IsTimeInForceExpiry(err) {
+		err = nil
 	}
 
-	c.JSON(200, gin.H{
-		"code":        "00000",
-		"msg":         "success",
-		"requestTime": time.Now().UnixMilli(),
-		"data": gin.H{
-			"orderId":   strconv.Itoa(int(order.ID)),
-			"clientOid": order.ClientOrderID,
-		},
-	})
+	return order, err
 }
 
 // PlacePlanOrder handles POST /api/v2/mix/order/place-plan-order (SL/TP)
@@ -255,6 +348,7 @@ func (h *Handler) PlacePlanOrder(c *gin.Context) {
 		TriggerPrice string `json:"triggerPrice"`
 		TriggerType  string `json:"triggerType"` // mark_price, fill_price
 		PlanType     string `json:"planType"`    // normal_plan, profit_plan, loss_plan
+		ClientOid    string `json:"clientOid"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -265,6 +359,24 @@ func (h *Handler) PlacePlanOrder(c *gin.Context) {
 	quantity, _ := strconv.ParseFloat(req.Size, 64)
 	triggerPrice, _ := strconv.ParseFloat(req.TriggerPrice, 64)
 
+	// fill_price (the default) triggers on the last price and mark_price on the mark price
+	var triggerPriceType models.TriggerPriceType
+	switch req.TriggerType {
+	case "", "fill_price":
+		triggerPriceType = models.TriggerPriceLast
+	case "mark_price":
+		triggerPriceType = models.TriggerPriceMark
+	default:
+		h.handleError(c, service.ErrInvalidTrigger)
+		return
+	}
+
+	// Limit plan orders rest a limit order at price once triggered; market ones close at market
+	var price float64
+	if req.OrderType == "limit" {
+		price, _ = strconv.ParseFloat(req.Price, 64)
+	}
+
 	var posSide models.PositionSide
 	if req.Side == "buy" {
 		posSide = models.PositionSideLong
@@ -282,17 +394,19 @@ func (h *Handler) PlacePlanOrder(c *gin.Context) {
 		orderType = models.OrderTypeStopMarket
 	}
 
-	closeReq := &service.ClosePositionRequest{
-		AccountID:  account.ID,
-		Symbol:     req.Symbol,
-		Side:       posSide,
-		Quantity:   &quantity,
-		OrderType:  orderType,
-		StopPrice:  triggerPrice,
-		ReduceOnly: true,
-	}
-
-	order, _, err := h.tradingService.ClosePosition(closeReq, models.ExchangeBitget)
+	order, err := h.tradingService.CreateConditionalOrder(&service.ConditionalOrderRequest{
+		AccountID:     account.ID,
+		Symbol:        req.Symbol,
+		Side:          posSide,
+		Quantity:      quantity,
+		OrderType:     orderType,
+		StopPrice:     triggerPrice,
+		Price:         price,
+		ReduceOnly:    true,
+		ClientOrderID: req.ClientOid,
+
+		TriggerPriceType: triggerPriceType,
+	}, models.ExchangeBitget)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -352,6 +466,157 @@ func (h *Handler) GetOpenOrders(c *gin.Context) {
 	})
 }
 
+// GetOrdersHistory handles GET /api/v2/mix/order/orders-history
+// Only completed orders are listed; idLessThan pages to older orders and endId is the cursor of the next page
+func (h *Handler) GetOrdersHistory(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, "40001", "Invalid API key")
+		return
+	}
+
+	q := historyQuery(c)
+	q.Statuses = []models.OrderStatus{models.OrderStatusFilled, models.OrderStatusCanceled, models.OrderStatusExpired, models.OrderStatusRejected}
+
+	orders, err := h.tradingService.GetOrderHistoryPage(account.ID, q)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(orders))
+	endID := ""
+	for i := range orders {
+		data = append(data, formatOrder(&orders[i]))
+		endID = strconv.Itoa(int(orders[i].ID))
+	}
+
+	c.JSON(200, gin.H{
+		"code":        "00000",
+		"msg":         "success",
+		"requestTime": time.Now().UnixMilli(),
+		"data": gin.H{
+			"entrustedList": data,
+			"endId":         endID,
+		},
+	})
+}
+
+// GetFills handles GET /api/v2/mix/order/fills
+// idLessThan pages to older fills and endId is the cursor of the next page
+func (h *Handler) GetFills(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, "40001", "Invalid API key")
+		return
+	}
+
+	trades, err := h.tradingService.GetTradeHistory(account.ID, historyQuery(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(trades))
+	endID := ""
+	for _, trade := range trades {
+		side := "buy"
+		if trade.Side == models.OrderSideSell {
+			side = "sell"
+		}
+		// Buying into a long or selling into a short opens; the opposite closes
+		tradeSide := "close"
+		if (trade.Order.PositionSide == models.PositionSideShort) == (trade.Side == models.OrderSideSell) {
+			tradeSide = "open"
+		}
+		tradeScope := "taker"
+		if trade.IsMaker {
+			tradeScope = "maker"
+		}
+
+		data = append(data, gin.H{
+			"tradeId":     strconv.Itoa(int(trade.ID)),
+			"symbol":      trade.Symbol,
+			"orderId":     strconv.Itoa(int(trade.OrderID)),
+			"price":       strconv.FormatFloat(trade.Price, 'f', 8, 64),
+			"baseVolume":  strconv.FormatFloat(trade.Quantity, 'f', 8, 64),
+			"quoteVolume": strconv.FormatFloat(trade.Price*trade.Quantity, 'f', 8, 64),
+			"feeDetail": []gin.H{{
+				"deduction":         "no",
+				"feeCoin":           trade.FeeCurrency,
+				"totalDeductionFee": "0",
+				"totalFee":          strconv.FormatFloat(-trade.Fee, 'f', 8, 64),
+			}},
+			"side":             side,
+			"profit":           strconv.FormatFloat(trade.RealizedPnL, 'f', 8, 64),
+			"enterPointSource": "API",
+			"tradeSide":        tradeSide,
+			"posMode":          "hedge_mode",
+			"tradeScope":       tradeScope,
+			"cTime":            strconv.FormatInt(trade.ExecutedAt.UnixMilli(), 10),
+		})
+		endID = strconv.Itoa(int(trade.ID))
+	}
+
+	c.JSON(200, gin.H{
+		"code":        "00000",
+		"msg":         "success",
+		"requestTime": time.Now().UnixMilli(),
+		"data": gin.H{
+			"fillList": data,
+			"endId":    endID,
+		},
+	})
+}
+
+// historyQuery parses symbol, orderId, startTime/endTime, limit and the idLessThan cursor of history endpoints
+func historyQuery(c *gin.Context) repository.HistoryQuery {
+	q := repository.HistoryQuery{Symbol: c.Query("symbol")}
+
+	q.Limit, _ = strconv.Atoi(c.Query("limit"))
+	if q.Limit <= 0 || q.Limit > 100 {
+		q.Limit = 100
+	}
+
+	if orderID, err := strconv.ParseUint(c.Query("orderId"), 10, 64); err == nil {
+		q.OrderID = uint(orderID)
+	}
+	if ms, err := strconv.ParseInt(c.Query("startTime"), 10, 64); err == nil {
+		q.Start = time.UnixMilli(ms)
+	}
+	if ms, err := strconv.ParseInt(c.Query("endTime"), 10, 64); err == nil {
+		q.End = time.UnixMilli(ms)
+	}
+	if id, err := strconv.ParseUint(c.Query("idLessThan"), 10, 64); err == nil {
+		q.BeforeID = uint(id)
+	}
+
+	return q
+}
+
+// GetOrderDetail handles GET /api/v2/mix/order/detail
+func (h *Handler) GetOrderDetail(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, "40001", "Invalid API key")
+		return
+	}
+
+	orderID, _ := strconv.ParseUint(c.Query("orderId"), 10, 64)
+	order, err := h.tradingService.FindOrder(account.ID, uint(orderID), c.Query("clientOid"))
+	if err != nil {
+		h.errorResponse(c, "40768", "Order does not exist")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"code":        "00000",
+		"msg":         "success",
+		"requestTime": time.Now().UnixMilli(),
+		"data":        formatOrder(order),
+	})
+}
+
 // GetPendingPlanOrders handles GET /api/v2/mix/order/orders-plan-pending (SL/TP orders)
 func (h *Handler) GetPendingPlanOrders(c *gin.Context) {
 	account := middleware.GetAccount(c)
@@ -369,6 +634,14 @@ func (h *Handler) GetPendingPlanOrders(c *gin.Context) {
 		if order.Type == models.OrderTypeTakeProfit {
 			planType = "profit_plan"
 		}
+		orderType := "market"
+		if order.Price > 0 {
+			orderType = "limit"
+		}
+		triggerType := "fill_price"
+		if order.TriggerPriceType == models.TriggerPriceMark {
+			triggerType = "mark_price"
+		}
 
 		data = append(data, gin.H{
 			"orderId":      strconv.Itoa(int(order.ID)),
@@ -376,6 +649,9 @@ func (h *Handler) GetPendingPlanOrders(c *gin.Context) {
 			"symbol":       order.Symbol,
 			"planType":     planType,
 			"triggerPrice": strconv.FormatFloat(order.StopPrice, 'f', 8, 64),
+			"price":        strconv.FormatFloat(order.Price, 'f', 8, 64),
+			"orderType":    orderType,
+			"triggerType":  triggerType,
 			"size":         strconv.FormatFloat(order.Quantity, 'f', 8, 64),
 			"state":        "not_trigger",
 			"cTime":        strconv.FormatInt(order.CreatedAt.UnixMilli(), 10),
@@ -434,6 +710,7 @@ func (h *Handler) CancelOrder(c *gin.Context) {
 	var req struct {
 		Symbol      string `json:"symbol"`
 		OrderId     string `json:"orderId"`
+		ClientOid   string `json:"clientOid"`
 		ProductType string `json:"productType"`
 	}
 
@@ -441,14 +718,109 @@ func (h *Handler) CancelOrder(c *gin.Context) {
 		h.errorResponse(c, "40001", err.Error())
 		return
 	}
+	if req.OrderId == "" && req.ClientOid == "" {
+		h.errorResponse(c, "40019", "Parameter orderId or clientOid cannot be empty")
+		return
+	}
+
+	orderID, _ := strconv.ParseUint(req.OrderId, 10, 64)
+	order, err := h.tradingService.FindOrder(account.ID, uint(orderID), req.ClientOid)
+	if err == nil && order.Symbol == req.Symbol {
+		order, err = h.tradingService.CancelOrder(account.ID, order.ID)
+	} else {
+		err = service.ErrOrderNotFound
+	}
+	if err != nil {
+		h.errorResponse(c, "40768", "Order does not exist")
+		return
+	}
 
 	c.JSON(200, gin.H{
 		"code":        "00000",
 		"msg":         "success",
 		"requestTime": time.Now().UnixMilli(),
 		"data": gin.H{
-			"orderId":   req.OrderId,
-			"clientOid": "",
+			"orderId":   strconv.Itoa(int(order.ID)),
+			"clientOid": order.ClientOrderID,
+		},
+	})
+}
+
+// ModifyOrder handles POST /api/v2/mix/order/modify-order
+func (h *Handler) ModifyOrder(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, "40001", "Invalid API key")
+		return
+	}
+
+	var req struct {
+		Symbol      string `json:"symbol"`
+		ProductType string `json:"productType"`
+		OrderId     string `json:"orderId"`
+		ClientOid   string `json:"clientOid"`
+		NewSize     string `json:"newSize"`
+		NewPrice    string `json:"newPrice"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorResponse(c, "40001", err.Error())
+		return
+	}
+
+	var order *models.Order
+	var err error
+	if req.OrderId != "" {
+		orderID, _ := strconv.ParseUint(req.OrderId, 10, 64)
+		order, err = h.tradingService.GetOrderStatus(account.ID, uint(orderID))
+	} else {
+		order, err = h.tradingService.GetOrderByClientID(account.ID, req.ClientOid)
+	}
+	if err != nil || order.Symbol != req.Symbol {
+		h.errorResponse(c, "40768", "Order does not exist")
+		return
+	}
+
+	amend := &service.AmendOrderRequest{}
+	if req.NewSize != "" {
+		size, err := strconv.ParseFloat(req.NewSize, 64)
+		if err != nil {
+			h.errorResponse(c, "40012", "Invalid size")
+			return
+		}
+		amend.Quantity = &size
+	}
+	if req.NewPrice != "" {
+		price, err := strconv.ParseFloat(req.NewPrice, 64)
+		if err != nil {
+			h.errorResponse(c, "40020", "Invalid price")
+			return
+		}
+		amend.Price = &price
+	}
+
+	order, err = h.tradingService.AmendOrder(account.ID, order.ID, amend)
+	if err != nil {
+		switch err {
+		case service.ErrOrderNotOpen:
+			h.errorResponse(c, "40768", "Order does not exist")
+		case service.ErrOrderNotModified:
+			h.errorResponse(c, "40020", "Order information has not been modified")
+		case service.ErrInvalidPrice:
+			h.errorResponse(c, "40020", "Invalid price")
+		default:
+			h.handleError(c, err)
+		}
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"code":        "00000",
+		"msg":         "success",
+		"requestTime": time.Now().UnixMilli(),
+		"data": gin.H{
+			"orderId":   strconv.Itoa(int(order.ID)),
+			"clientOid": order.ClientOrderID,
 		},
 	})
 }
@@ -577,6 +949,72 @@ func (h *Handler) GetTicker(c *gin.Context) {
 
 // Helper functions
 
+// formatOrder formats an order for Bitget response
+func formatOrder(order *models.Order) gin.H {
+	side := "buy"
+	if order.Side == models.OrderSideSell {
+		side = "sell"
+	}
+
+	orderType := "market"
+	if order.Type == models.OrderTypeLimit {
+		orderType = "limit"
+	}
+	orderSource := "normal"
+	if order.IsLiquidation() {
+		orderSource = "liquidation"
+	}
+	force := "gtc"
+	switch {
+	case order.Type != models.OrderTypeLimit:
+		force = "ioc"
+	case order.TimeInForce == models.TimeInForceGTX:
+		force = "post_only"
+	case order.TimeInForce == models.TimeInForceIOC, order.TimeInForce == models.TimeInForceFOK:
+		force = strings.ToLower(order.TimeInForce)
+	}
+
+	var status string
+	switch order.Status {
+	case models.OrderStatusPartiallyFilled:
+		status = "partially_filled"
+	case models.OrderStatusFilled:
+		status = "filled"
+	case models.OrderStatusCanceled, models.OrderStatusExpired, models.OrderStatusRejected:
+		status = "canceled"
+	case models.OrderStatusTriggered:
+		status = "executed"
+	default:
+		status = "live"
+	}
+
+	result := gin.H{
+		"symbol":      order.Symbol,
+		"orderId":     strconv.Itoa(int(order.ID)),
+		"clientOid":   order.ClientOrderID,
+		"price":       strconv.FormatFloat(order.Price, 'f', 8, 64),
+		"size":        strconv.FormatFloat(order.Quantity, 'f', 8, 64),
+		"baseVolume":  strconv.FormatFloat(order.FilledQty, 'f', 8, 64),
+		"quoteVolume": strconv.FormatFloat(order.FilledQty*order.AvgPrice, 'f', 8, 64),
+		"priceAvg":    strconv.FormatFloat(order.AvgPrice, 'f', 8, 64),
+		"orderType":   orderType,
+		"side":        side,
+		"force":       force,
+		"status":      status,
+		"orderSource": orderSource,
+		"marginCoin":  "USDT",
+		"reduceOnly":  strconv.FormatBool(order.ReduceOnly),
+		"cTime":       strconv.FormatInt(order.CreatedAt.UnixMilli(), 10),
+		"uTime":       strconv.FormatInt(order.UpdatedAt.UnixMilli(), 10),
+	}
+
+	// A triggered limit plan order links to the limit order it placed
+	if order.TriggeredOrderID != nil {
+		result["executeOrderId"] = strconv.Itoa(int(*order.TriggeredOrderID))
+	}
+	return result
+}
+
 func (h *Handler) errorResponse(c *gin.Context, code, msg string) {
 	c.JSON(200, gin.H{
 		"code":        code,
@@ -587,17 +1025,31 @@ func (h *Handler) errorResponse(c *gin.Context, code, msg string) {
 }
 
 func (h *Handler) handleError(c *gin.Context, err error) {
+	code, msg := errorCode(err)
+	h.errorResponse(c, code, msg)
+}
+
+// errorCode maps service errors to Bitget error codes
+func errorCode(err error) (string, string) {
 	switch err {
 	case service.ErrInsufficientBalance:
-		h.errorResponse(c, "45110", "Insufficient balance")
+		return "45110", "Insufficient balance"
 	case service.ErrInvalidSymbol:
-		h.errorResponse(c, "40018", "Invalid symbol")
+		return "40018", "Invalid symbol"
 	case service.ErrInvalidQuantity:
-		h.errorResponse(c, "40012", "Invalid size")
+		return "40012", "Invalid size"
 	case service.ErrNoOpenPosition:
-		h.errorResponse(c, "45112", "No position to close")
+		return "45112", "No position to close"
+	case service.ErrDuplicateClientID:
+		return "40786", "Duplicate clientOid"
+	case service.ErrInvalidTimeInForce:
+		return "40017", "Parameter force error"
+	case service.ErrPostOnlyWouldTake:
+		return "40017", "The post-only order would take liquidity"
+	case service.ErrInvalidTrigger:
+		return "40017", "Parameter triggerType error"
 	default:
-		h.errorResponse(c, "50000", err.Error())
+		return "50000", err.Error()
 	}
 }
 
@@ -637,9 +1089,14 @@ func (h *Handler) RegisterRoutes(router *gin.Engine, authMiddleware gin.HandlerF
 		order := mixApi.Group("/order")
 		{
 			order.POST("/place-order", middleware.TradingLoggerMiddleware(), h.PlaceOrder)
+			order.POST("/batch-place-order", middleware.TradingLoggerMiddleware(), h.BatchPlaceOrder)
 			order.POST("/cancel-order", middleware.TradingLoggerMiddleware(), h.CancelOrder)
 			order.POST("/cancel-all-orders", middleware.TradingLoggerMiddleware(), h.CancelAllOrders)
+			order.POST("/modify-order", middleware.TradingLoggerMiddleware(), h.ModifyOrder)
 			order.GET("/orders-pending", h.GetOpenOrders)
+			order.GET("/detail", h.GetOrderDetail)
+			order.GET("/orders-history", h.GetOrdersHistory)
+			order.GET("/fills", h.GetFills)
 			// Plan orders (SL/TP)
 			order.POST("/place-plan-order", middleware.TradingLoggerMiddleware(), h.PlacePlanOrder)
 			order.POST("/cancel-plan-order", middleware.TradingLoggerMiddleware(), h.CancelPlanOrder)
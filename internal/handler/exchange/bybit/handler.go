@@ -1,28 +1,40 @@
 package bybit
 
 import (
+	"errors"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ccxt-simulator/internal/middleware"
 	"github.com/ccxt-simulator/internal/models"
+	"github.com/ccxt-simulator/internal/repository"
 	"github.com/ccxt-simulator/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
+// maxBatchOrders is the most orders accepted by create-batch and cancel-batch
+const maxBatchOrders = 20
+
+// errConditionalOpen is returned for conditional orders that are not reduce-only
+var errConditionalOpen = errors.New("conditional orders must be reduce-only")
+
 // Handler handles Bybit-compatible API requests
 type Handler struct {
 	tradingService      *service.TradingService
 	priceService        *service.PriceService
 	exchangeInfoService *service.ExchangeInfoService
+	fundingService      *service.FundingService
+	wsLogin             middleware.BybitLoginFunc
 }
 
 // NewHandler creates a new Bybit handler
-func NewHandler(tradingService *service.TradingService, priceService *service.PriceService, exchangeInfoService *service.ExchangeInfoService) *Handler {
+func NewHandler(tradingService *service.TradingService, priceService *service.PriceService, exchangeInfoService *service.ExchangeInfoService, fundingService *service.FundingService) *Handler {
 	return &Handler{
 		tradingService:      tradingService,
 		priceService:        priceService,
 		exchangeInfoService: exchangeInfoService,
+		fundingService:      fundingService,
 	}
 }
 
@@ -78,29 +90,85 @@ func (h *Handler) GetWalletBalance(c *gin.Context) {
 		"retCode": 0,
 		"retMsg":  "OK",
 		"result": gin.H{
-			"list": []gin.H{
-				{
-					"accountType":           "UNIFIED",
-					"accountIMRate":         "0",
-					"accountMMRate":         "0",
-					"totalEquity":           strconv.FormatFloat(balance["equity"], 'f', 8, 64),
-					"totalWalletBalance":    strconv.FormatFloat(balance["balance"], 'f', 8, 64),
-					"totalMarginBalance":    strconv.FormatFloat(balance["balance"], 'f', 8, 64),
-					"totalAvailableBalance": strconv.FormatFloat(balance["available"], 'f', 8, 64),
-					"totalPerpUPL":          strconv.FormatFloat(balance["unrealized_pnl"], 'f', 8, 64),
-					"totalInitialMargin":    strconv.FormatFloat(balance["margin"], 'f', 8, 64),
-					"coin": []gin.H{
-						{
-							"coin":                "USDT",
-							"equity":              strconv.FormatFloat(balance["equity"], 'f', 8, 64),
-							"walletBalance":       strconv.FormatFloat(balance["balance"], 'f', 8, 64),
-							"availableToWithdraw": strconv.FormatFloat(balance["available"], 'f', 8, 64),
-							"unrealisedPnl":       strconv.FormatFloat(balance["unrealized_pnl"], 'f', 8, 64),
-							"cumRealisedPnl":      "0",
-						},
-					},
-				},
-			},
+			"list": []gin.H{formatWallet(balance)},
+		},
+		"time": time.Now().UnixMilli(),
+	})
+}
+
+// GetTransactionLog handles GET /v5/account/transaction-log
+func (h *Handler) GetTransactionLog(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, 10003, "Invalid apiKey")
+		return
+	}
+
+	symbol := c.Query("symbol")
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	var startTime, endTime time.Time
+	if ms, err := strconv.ParseInt(c.Query("startTime"), 10, 64); err == nil {
+		startTime = time.UnixMilli(ms)
+	}
+	if ms, err := strconv.ParseInt(c.Query("endTime"), 10, 64); err == nil {
+		endTime = time.UnixMilli(ms)
+	}
+
+	list := make([]gin.H, 0)
+
+	// Only funding settlements are recorded in the transaction log
+	if logType := c.Query("type"); logType == "" || logType == "SETTLEMENT" {
+		fees, err := h.fundingService.GetFundingFees(account.ID, symbol, startTime, endTime, limit)
+		if err != nil {
+			h.errorResponse(c, 10000, err.Error())
+			return
+		}
+
+		for _, fee := range fees {
+			side := "Buy"
+			if fee.Side == models.PositionSideShort {
+				side = "Sell"
+			}
+
+			// Bybit reports funding as an expense: positive is paid, negative is received
+			list = append(list, gin.H{
+				"id":              strconv.Itoa(int(fee.ID)),
+				"symbol":          fee.Symbol,
+				"category":        "linear",
+				"side":            side,
+				"transactionTime": strconv.FormatInt(fee.FundingTime.UnixMilli(), 10),
+				"type":            "SETTLEMENT",
+				"qty":             strconv.FormatFloat(fee.Quantity, 'f', 8, 64),
+				"size":            strconv.FormatFloat(fee.Quantity, 'f', 8, 64),
+				"currency":        "USDT",
+				"tradePrice":      strconv.FormatFloat(fee.MarkPrice, 'f', 8, 64),
+				"funding":         strconv.FormatFloat(-fee.Amount, 'f', 8, 64),
+				"fee":             "0",
+				"feeRate":         strconv.FormatFloat(fee.FundingRate, 'f', 8, 64),
+				"cashFlow":        "0",
+				"change":          strconv.FormatFloat(fee.Amount, 'f', 8, 64),
+				"cashBalance":     strconv.FormatFloat(fee.BalanceAfter, 'f', 8, 64),
+				"bonusChange":     "",
+				"tradeId":         "",
+				"orderId":         "",
+				"orderLinkId":     "",
+			})
+		}
+	}
+
+	c.JSON(200, gin.H{
+		"retCode": 0,
+		"retMsg":  "OK",
+		"result": gin.H{
+			"list":           list,
+			"nextPageCursor": "",
 		},
 		"time": time.Now().UnixMilli(),
 	})
@@ -126,28 +194,25 @@ func (h *Handler) GetPositionInfo(c *gin.Context) {
 		if symbol != "" && pos.Symbol != symbol {
 			continue
 		}
+		list = append(list, formatPosition(&pos))
+	}
 
-		side := "Buy"
-		if pos.Side == models.PositionSideShort {
-			side = "Sell"
+	// A queried symbol without a position is reported flat with its configured leverage
+	if symbol != "" && len(list) == 0 {
+		setting, err := h.tradingService.GetSymbolSetting(account.ID, symbol)
+		if err != nil {
+			h.errorResponse(c, 10000, err.Error())
+			return
 		}
-
-		list = append(list, gin.H{
-			"symbol":        pos.Symbol,
-			"side":          side,
-			"size":          strconv.FormatFloat(pos.Quantity, 'f', 8, 64),
-			"avgPrice":      strconv.FormatFloat(pos.EntryPrice, 'f', 8, 64),
-			"markPrice":     strconv.FormatFloat(pos.MarkPrice, 'f', 8, 64),
-			"positionValue": strconv.FormatFloat(pos.MarkPrice*pos.Quantity, 'f', 8, 64),
-			"leverage":      strconv.Itoa(pos.Leverage),
-			"unrealisedPnl": strconv.FormatFloat(pos.UnrealizedPnL, 'f', 8, 64),
-			"liqPrice":      strconv.FormatFloat(pos.LiquidationPrice, 'f', 8, 64),
-			"tradeMode":     0,
-			"positionIdx":   0,
-			"riskId":        1,
-			"createdTime":   strconv.FormatInt(pos.CreatedAt.UnixMilli(), 10),
-			"updatedTime":   strconv.FormatInt(pos.UpdatedAt.UnixMilli(), 10),
-		})
+		markPrice, _ := h.priceService.GetPrice("bybit", symbol)
+		list = append(list, formatPosition(&models.Position{
+			Symbol:     symbol,
+			MarkPrice:  markPrice,
+			Leverage:   setting.Leverage,
+			MarginMode: setting.MarginMode,
+			CreatedAt:  setting.CreatedAt,
+			UpdatedAt:  setting.UpdatedAt,
+		}))
 	}
 
 	c.JSON(200, gin.H{
@@ -161,6 +226,25 @@ func (h *Handler) GetPositionInfo(c *gin.Context) {
 	})
 }
 
+// createOrderRequest is the body of POST /v5/order/create and an element of create-batch
+type createOrderRequest struct {
+	Category    string `json:"category"`
+	Symbol      string `json:"symbol"`
+	Side        string `json:"side"`
+	OrderType   string `json:"orderType"`
+	Qty         string `json:"qty"`
+	Price       string `json:"price"`
+	TimeInForce string `json:"timeInForce"`
+	PositionIdx int    `json:"positionIdx"`
+	ReduceOnly  bool   `json:"reduceOnly"`
+	OrderLinkId string `json:"orderLinkId"`
+
+	// Conditional orders only; triggerDirection 1 triggers on a rise to triggerPrice, 2 on a fall
+	TriggerPrice     string `json:"triggerPrice"`
+	TriggerDirection int    `json:"triggerDirection"`
+	TriggerBy        string `json:"triggerBy"` // LastPrice (default), IndexPrice or MarkPrice
+}
+
 // CreateOrder handles POST /v5/order/create
 func (h *Handler) CreateOrder(c *gin.Context) {
 	account := middleware.GetAccount(c)
@@ -169,22 +253,78 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	var req createOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorResponse(c, 10001, err.Error())
+		return
+	}
+
+	order, err := h.placeOrder(account.ID, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"retCode": 0,
+		"retMsg":  "OK",
+		"result": gin.H{
+			"orderId":     strconv.Itoa(int(order.ID)),
+			"orderLinkId": order.ClientOrderID,
+		},
+		"time": time.Now().UnixMilli(),
+	})
+}
+
+// CreateBatchOrders handles POST /v5/order/create-batch
+// Orders are placed independently; retExtInfo carries the result code of each one
+func (h *Handler) CreateBatchOrders(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, 10003, "Invalid apiKey")
+		return
+	}
+
 	var req struct {
-		Category    string `json:"category"`
-		Symbol      string `json:"symbol"`
-		Side        string `json:"side"`
-		OrderType   string `json:"orderType"`
-		Qty         string `json:"qty"`
-		Price       string `json:"price"`
-		PositionIdx int    `json:"positionIdx"`
-		ReduceOnly  bool   `json:"reduceOnly"`
+		Category string               `json:"category"`
+		Request  []createOrderRequest `json:"request"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.errorResponse(c, 10001, err.Error())
 		return
 	}
+	if len(req.Request) == 0 || len(req.Request) > maxBatchOrders {
+		h.errorResponse(c, 10001, "Invalid request: the batch must contain 1 to 20 orders")
+		return
+	}
+
+	list := make([]gin.H, 0, len(req.Request))
+	codes := make([]gin.H, 0, len(req.Request))
+	for i := range req.Request {
+		item := &req.Request[i]
+		order, err := h.placeOrder(account.ID, item)
+		if err != nil {
+			code, msg := errorCode(err)
+			list = append(list, gin.H{"category": req.Category, "symbol": item.Symbol, "orderId": "", "orderLinkId": item.OrderLinkId, "createAt": ""})
+			codes = append(codes, gin.H{"code": code, "msg": msg})
+			continue
+		}
+		list = append(list, gin.H{
+			"category":    req.Category,
+			"symbol":      order.Symbol,
+			"orderId":     strconv.Itoa(int(order.ID)),
+			"orderLinkId": order.ClientOrderID,
+			"createAt":    strconv.FormatInt(order.CreatedAt.UnixMilli(), 10),
+		})
+		codes = append(codes, gin.H{"code": 0, "msg": "OK"})
+	}
+
+	batchResponse(c, list, codes)
+}
 
+// placeOrder opens or reduces a position from a Bybit order request
+func (h *Handler) placeOrder(accountID uint, req *createOrderRequest) (*models.Order, error) {
 	quantity, _ := strconv.ParseFloat(req.Qty, 64)
 	price, _ := strconv.ParseFloat(req.Price, 64)
 
@@ -203,45 +343,104 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 		orderType = models.OrderTypeMarket
 	}
 
+	if req.TriggerPrice != "" {
+		return h.placeConditionalOrder(accountID, req, quantity, orderType, price)
+	}
+
+	timeInForce := req.TimeInForce
+	if timeInForce == "PostOnly" {
+		timeInForce = models.TimeInForceGTX
+	}
+
 	var order *models.Order
 	var err error
 
 	if !req.ReduceOnly {
 		openReq := &service.OpenPositionRequest{
-			AccountID: account.ID,
-			Symbol:    req.Symbol,
-			Side:      posSide,
-			Quantity:  quantity,
-			OrderType: orderType,
-			Price:     price,
+			AccountID:     accountID,
+			Symbol:        req.Symbol,
+			Side:          posSide,
+			Quantity:      quantity,
+			OrderType:     orderType,
+			Price:         price,
+			TimeInForce:   timeInForce,
+			ClientOrderID: req.OrderLinkId,
 		}
 		order, _, err = h.tradingService.OpenPosition(openReq, models.ExchangeBybit)
 	} else {
 		closeReq := &service.ClosePositionRequest{
-			AccountID: account.ID,
-			Symbol:    req.Symbol,
-			Side:      posSide,
-			Quantity:  &quantity,
-			OrderType: orderType,
-			Price:     price,
+			AccountID:     accountID,
+			Symbol:        req.Symbol,
+			Side:          posSide,
+			Quantity:      &quantity,
+			OrderType:     orderType,
+			Price:         price,
+			TimeInForce:   timeInForce,
+			ClientOrderID: req.OrderLinkId,
 		}
 		order, _, err = h.tradingService.ClosePosition(closeReq, models.ExchangeBybit)
 	}
 
-	if err != nil {
-		h.handleError(c, err)
-		return
+	// Bybit accepts IOC, FOK and post-only orders that cannot be met and cancels them right away
+	if service.IsTimeInForceExpiry(err) {
+		err = nil
 	}
 
-	c.JSON(200, gin.H{
-		"retCode": 0,
-		"retMsg":  "OK",
-		"result": gin.H{
-			"orderId":     strconv.Itoa(int(order.ID)),
-			"orderLinkId": order.ClientOrderID,
-		},
-		"time": time.Now().UnixMilli(),
-	})
+	return order, err
+}
+
+// placeConditionalOrder places a reduce-only conditional order that waits for triggerPrice
+// Closing a long, a fall to the trigger is a stop loss and a rise a take profit; closing a short, the reverse
+// Limit conditional orders rest a limit order at price once triggered
+func (h *Handler) placeConditionalOrder(accountID uint, req *createOrderRequest, quantity float64, orderType models.OrderType, price float64) (*models.Order, error) {
+	if !req.ReduceOnly {
+		return nil, errConditionalOpen
+	}
+	triggerPrice, _ := strconv.ParseFloat(req.TriggerPrice, 64)
+
+	// In hedge mode positionIdx names the position; in one-way mode a sell closes a long
+	var posSide models.PositionSide
+	switch {
+	case req.PositionIdx == 1:
+		posSide = models.PositionSideLong
+	case req.PositionIdx == 2:
+		posSide = models.PositionSideShort
+	case req.Side == "Sell":
+		posSide = models.PositionSideLong
+	default:
+		posSide = models.PositionSideShort
+	}
+
+	// Without a triggerDirection the trigger's side of the current price decides it
+	rises := req.TriggerDirection == 1
+	if req.TriggerDirection == 0 {
+		if current, err := h.priceService.GetPrice(string(models.ExchangeBybit), req.Symbol); err == nil {
+			rises = triggerPrice > current
+		}
+	}
+	conditionalType := models.OrderTypeTakeProfit
+	if (posSide == models.PositionSideLong) != rises {
+		conditionalType = models.OrderTypeStopMarket
+	}
+
+	limitPrice := 0.0
+	if orderType == models.OrderTypeLimit {
+		limitPrice = price
+	}
+
+	return h.tradingService.CreateConditionalOrder(&service.ConditionalOrderRequest{
+		AccountID:     accountID,
+		Symbol:        req.Symbol,
+		Side:          posSide,
+		Quantity:      quantity,
+		OrderType:     conditionalType,
+		StopPrice:     triggerPrice,
+		Price:         limitPrice,
+		ReduceOnly:    true,
+		ClientOrderID: req.OrderLinkId,
+		// Bybit triggerBy values are the trigger price type names with a Price suffix
+		TriggerPriceType: models.TriggerPriceType(strings.ToUpper(strings.TrimSuffix(req.TriggerBy, "Price"))),
+	}, models.ExchangeBybit)
 }
 
 // SetTradingStop handles POST /v5/position/trading-stop (SL/TP)
@@ -266,6 +465,8 @@ func (h *Handler) SetTradingStop(c *gin.Context) {
 		SlSize       string `json:"slSize"`
 		TpLimitPrice string `json:"tpLimitPrice"`
 		SlLimitPrice string `json:"slLimitPrice"`
+		TrailingStop string `json:"trailingStop"` // price distance, "0" cancels
+		ActivePrice  string `json:"activePrice"`
 		PositionIdx  int    `json:"positionIdx"`
 	}
 
@@ -300,6 +501,20 @@ func (h *Handler) SetTradingStop(c *gin.Context) {
 		}
 	}
 
+	// Set Trailing Stop
+	if req.TrailingStop != "" {
+		distance, _ := strconv.ParseFloat(req.TrailingStop, 64)
+		activePrice, _ := strconv.ParseFloat(req.ActivePrice, 64)
+		if _, err := h.tradingService.SetTrailingStop(account.ID, req.Symbol, posSide, distance, activePrice, models.ExchangeBybit); err != nil {
+			if err == service.ErrPositionNotFound {
+				h.errorResponse(c, 10001, "can not set tp/sl/ts for zero position")
+				return
+			}
+			h.handleError(c, err)
+			return
+		}
+	}
+
 	c.JSON(200, gin.H{
 		"retCode": 0,
 		"retMsg":  "OK",
@@ -309,6 +524,7 @@ func (h *Handler) SetTradingStop(c *gin.Context) {
 }
 
 // GetOpenOrders handles GET /v5/order/realtime
+// Like Bybit, a lookup by orderId or orderLinkId also returns the order once it is closed
 func (h *Handler) GetOpenOrders(c *gin.Context) {
 	account := middleware.GetAccount(c)
 	if account == nil {
@@ -317,27 +533,143 @@ func (h *Handler) GetOpenOrders(c *gin.Context) {
 	}
 
 	symbol := c.Query("symbol")
-	orders, _ := h.tradingService.GetOpenOrders(account.ID, symbol)
-
 	list := make([]gin.H, 0)
-	for _, order := range orders {
+
+	if orderIDStr, orderLinkID := c.Query("orderId"), c.Query("orderLinkId"); orderIDStr != "" || orderLinkID != "" {
+		orderID, _ := strconv.ParseUint(orderIDStr, 10, 64)
+		order, err := h.tradingService.FindOrder(account.ID, uint(orderID), orderLinkID)
+		if err == nil && (symbol == "" || order.Symbol == symbol) {
+			list = append(list, formatOrder(order))
+		}
+	} else {
+		orders, _ := h.tradingService.GetOpenOrders(account.ID, symbol)
+		for _, order := range orders {
+			list = append(list, formatOrder(&order))
+		}
+	}
+
+	c.JSON(200, gin.H{
+		"retCode": 0,
+		"retMsg":  "OK",
+		"result": gin.H{
+			"category": "linear",
+			"list":     list,
+		},
+		"time": time.Now().UnixMilli(),
+	})
+}
+
+// GetOrderHistory handles GET /v5/order/history
+func (h *Handler) GetOrderHistory(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, 10003, "Invalid apiKey")
+		return
+	}
+
+	q, limit, ok := h.historyQuery(c)
+	if !ok {
+		return
+	}
+	if orderID, err := strconv.ParseUint(c.Query("orderId"), 10, 64); err == nil {
+		q.OrderID = uint(orderID)
+	}
+
+	orders, err := h.tradingService.GetOrderHistoryPage(account.ID, q)
+	if err != nil {
+		h.errorResponse(c, 10000, err.Error())
+		return
+	}
+
+	nextPageCursor := ""
+	if len(orders) > limit {
+		orders = orders[:limit]
+		nextPageCursor = strconv.Itoa(int(orders[limit-1].ID))
+	}
+
+	list := make([]gin.H, 0, len(orders))
+	for i := range orders {
+		list = append(list, formatOrder(&orders[i]))
+	}
+
+	c.JSON(200, gin.H{
+		"retCode": 0,
+		"retMsg":  "OK",
+		"result": gin.H{
+			"category":       "linear",
+			"list":           list,
+			"nextPageCursor": nextPageCursor,
+		},
+		"time": time.Now().UnixMilli(),
+	})
+}
+
+// GetExecutionList handles GET /v5/execution/list
+func (h *Handler) GetExecutionList(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, 10003, "Invalid apiKey")
+		return
+	}
+
+	q, limit, ok := h.historyQuery(c)
+	if !ok {
+		return
+	}
+	if orderID, err := strconv.ParseUint(c.Query("orderId"), 10, 64); err == nil {
+		q.OrderID = uint(orderID)
+	}
+
+	trades, err := h.tradingService.GetTradeHistory(account.ID, q)
+	if err != nil {
+		h.errorResponse(c, 10000, err.Error())
+		return
+	}
+
+	nextPageCursor := ""
+	if len(trades) > limit {
+		trades = trades[:limit]
+		nextPageCursor = strconv.Itoa(int(trades[limit-1].ID))
+	}
+
+	list := make([]gin.H, 0, len(trades))
+	for _, trade := range trades {
 		side := "Buy"
-		if order.Side == models.OrderSideSell {
+		if trade.Side == models.OrderSideSell {
 			side = "Sell"
 		}
+		orderType := "Market"
+		if trade.Order.Type == models.OrderTypeLimit {
+			orderType = "Limit"
+		}
+		closedSize := "0"
+		if trade.Order.ReduceOnly || trade.RealizedPnL != 0 {
+			closedSize = strconv.FormatFloat(trade.Quantity, 'f', 8, 64)
+		}
+		feeRate := 0.0
+		if value := trade.Price * trade.Quantity; value > 0 {
+			feeRate = trade.Fee / value
+		}
 
 		list = append(list, gin.H{
-			"orderId":     strconv.Itoa(int(order.ID)),
-			"orderLinkId": order.ClientOrderID,
-			"symbol":      order.Symbol,
+			"symbol":      trade.Symbol,
+			"orderId":     strconv.Itoa(int(trade.OrderID)),
+			"orderLinkId": trade.Order.ClientOrderID,
 			"side":        side,
-			"orderType":   string(order.Type),
-			"price":       strconv.FormatFloat(order.Price, 'f', 8, 64),
-			"qty":         strconv.FormatFloat(order.Quantity, 'f', 8, 64),
-			"cumExecQty":  strconv.FormatFloat(order.FilledQty, 'f', 8, 64),
-			"orderStatus": string(order.Status),
-			"createdTime": strconv.FormatInt(order.CreatedAt.UnixMilli(), 10),
-			"updatedTime": strconv.FormatInt(order.UpdatedAt.UnixMilli(), 10),
+			"orderPrice":  strconv.FormatFloat(trade.Order.Price, 'f', 8, 64),
+			"orderQty":    strconv.FormatFloat(trade.Order.Quantity, 'f', 8, 64),
+			"leavesQty":   strconv.FormatFloat(trade.Order.Quantity-trade.Order.FilledQty, 'f', 8, 64),
+			"orderType":   orderType,
+			"execId":      strconv.Itoa(int(trade.ID)),
+			"execPrice":   strconv.FormatFloat(trade.Price, 'f', 8, 64),
+			"execQty":     strconv.FormatFloat(trade.Quantity, 'f', 8, 64),
+			"execValue":   strconv.FormatFloat(trade.Price*trade.Quantity, 'f', 8, 64),
+			"execFee":     strconv.FormatFloat(trade.Fee, 'f', 8, 64),
+			"feeRate":     strconv.FormatFloat(feeRate, 'f', 8, 64),
+			"execType":    "Trade",
+			"execTime":    strconv.FormatInt(trade.ExecutedAt.UnixMilli(), 10),
+			"isMaker":     trade.IsMaker,
+			"closedSize":  closedSize,
 		})
 	}
 
@@ -345,8 +677,190 @@ func (h *Handler) GetOpenOrders(c *gin.Context) {
 		"retCode": 0,
 		"retMsg":  "OK",
 		"result": gin.H{
-			"category": "linear",
-			"list":     list,
+			"category":       "linear",
+			"list":           list,
+			"nextPageCursor": nextPageCursor,
+		},
+		"time": time.Now().UnixMilli(),
+	})
+}
+
+// GetClosedPnL handles GET /v5/position/closed-pnl
+func (h *Handler) GetClosedPnL(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, 10003, "Invalid apiKey")
+		return
+	}
+
+	q, limit, ok := h.historyQuery(c)
+	if !ok {
+		return
+	}
+
+	records, err := h.tradingService.GetClosedPnLHistory(account.ID, q)
+	if err != nil {
+		h.errorResponse(c, 10000, err.Error())
+		return
+	}
+
+	nextPageCursor := ""
+	if len(records) > limit {
+		records = records[:limit]
+		nextPageCursor = strconv.Itoa(int(records[limit-1].ID))
+	}
+
+	list := make([]gin.H, 0, len(records))
+	for _, record := range records {
+		// side is the side of the closing order
+		side := "Sell"
+		if record.Side == models.PositionSideShort {
+			side = "Buy"
+		}
+
+		list = append(list, gin.H{
+			"symbol":        record.Symbol,
+			"orderId":       "",
+			"side":          side,
+			"qty":           strconv.FormatFloat(record.Quantity, 'f', 8, 64),
+			"orderPrice":    strconv.FormatFloat(record.ExitPrice, 'f', 8, 64),
+			"orderType":     "Market",
+			"execType":      "Trade",
+			"closedSize":    strconv.FormatFloat(record.Quantity, 'f', 8, 64),
+			"cumEntryValue": strconv.FormatFloat(record.EntryPrice*record.Quantity, 'f', 8, 64),
+			"avgEntryPrice": strconv.FormatFloat(record.EntryPrice, 'f', 8, 64),
+			"cumExitValue":  strconv.FormatFloat(record.ExitPrice*record.Quantity, 'f', 8, 64),
+			"avgExitPrice":  strconv.FormatFloat(record.ExitPrice, 'f', 8, 64),
+			"closedPnl":     strconv.FormatFloat(record.RealizedPnL, 'f', 8, 64),
+			"fillCount":     "1",
+			"leverage":      strconv.Itoa(record.Leverage),
+			"createdTime":   strconv.FormatInt(record.OpenedAt.UnixMilli(), 10),
+			"updatedTime":   strconv.FormatInt(record.ClosedAt.UnixMilli(), 10),
+		})
+	}
+
+	c.JSON(200, gin.H{
+		"retCode": 0,
+		"retMsg":  "OK",
+		"result": gin.H{
+			"category":       "linear",
+			"list":           list,
+			"nextPageCursor": nextPageCursor,
+		},
+		"time": time.Now().UnixMilli(),
+	})
+}
+
+// historyQuery parses symbol, startTime/endTime, limit and cursor of history endpoints
+// One extra record is queried to tell whether a next page exists; it writes the error response on a bad cursor
+func (h *Handler) historyQuery(c *gin.Context) (repository.HistoryQuery, int, bool) {
+	q := repository.HistoryQuery{Symbol: c.Query("symbol")}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	q.Limit = limit + 1
+
+	if ms, err := strconv.ParseInt(c.Query("startTime"), 10, 64); err == nil {
+		q.Start = time.UnixMilli(ms)
+	}
+	if ms, err := strconv.ParseInt(c.Query("endTime"), 10, 64); err == nil {
+		q.End = time.UnixMilli(ms)
+	}
+	if cursor := c.Query("cursor"); cursor != "" {
+		id, err := strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			h.errorResponse(c, 10001, "invalid cursor")
+			return q, 0, false
+		}
+		q.BeforeID = uint(id)
+	}
+
+	return q, limit, true
+}
+
+// AmendOrder handles POST /v5/order/amend
+func (h *Handler) AmendOrder(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, 10003, "Invalid apiKey")
+		return
+	}
+
+	var req struct {
+		Category     string `json:"category"`
+		Symbol       string `json:"symbol"`
+		OrderId      string `json:"orderId"`
+		OrderLinkId  string `json:"orderLinkId"`
+		Qty          string `json:"qty"`
+		Price        string `json:"price"`
+		TriggerPrice string `json:"triggerPrice"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorResponse(c, 10001, err.Error())
+		return
+	}
+
+	var order *models.Order
+	var err error
+	if req.OrderId != "" {
+		orderID, _ := strconv.ParseUint(req.OrderId, 10, 64)
+		order, err = h.tradingService.GetOrderStatus(account.ID, uint(orderID))
+	} else {
+		order, err = h.tradingService.GetOrderByClientID(account.ID, req.OrderLinkId)
+	}
+	if err != nil || order.Symbol != req.Symbol {
+		h.errorResponse(c, 110001, "order not exists or too late to replace")
+		return
+	}
+
+	amend := &service.AmendOrderRequest{}
+	for _, field := range []struct {
+		raw    string
+		name   string
+		target **float64
+	}{
+		{req.Qty, "qty", &amend.Quantity},
+		{req.Price, "price", &amend.Price},
+		{req.TriggerPrice, "triggerPrice", &amend.StopPrice},
+	} {
+		if field.raw == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(field.raw, 64)
+		if err != nil {
+			h.errorResponse(c, 10001, "Invalid "+field.name)
+			return
+		}
+		*field.target = &value
+	}
+
+	order, err = h.tradingService.AmendOrder(account.ID, order.ID, amend)
+	if err != nil {
+		switch err {
+		case service.ErrOrderNotOpen:
+			h.errorResponse(c, 110001, "order not exists or too late to replace")
+		case service.ErrOrderNotModified:
+			h.errorResponse(c, 10001, "The order remains unchanged as the parameters entered match the existing ones.")
+		case service.ErrInvalidPrice:
+			h.errorResponse(c, 10001, "Invalid price")
+		default:
+			h.handleError(c, err)
+		}
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"retCode": 0,
+		"retMsg":  "OK",
+		"result": gin.H{
+			"orderId":     strconv.Itoa(int(order.ID)),
+			"orderLinkId": order.ClientOrderID,
 		},
 		"time": time.Now().UnixMilli(),
 	})
@@ -361,27 +875,116 @@ func (h *Handler) CancelOrder(c *gin.Context) {
 	}
 
 	var req struct {
-		Category string `json:"category"`
-		Symbol   string `json:"symbol"`
-		OrderId  string `json:"orderId"`
+		Category    string `json:"category"`
+		Symbol      string `json:"symbol"`
+		OrderId     string `json:"orderId"`
+		OrderLinkId string `json:"orderLinkId"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.errorResponse(c, 10001, err.Error())
 		return
 	}
+	if req.OrderId == "" && req.OrderLinkId == "" {
+		h.errorResponse(c, 10001, "orderId or orderLinkId is required")
+		return
+	}
+
+	orderID, _ := strconv.ParseUint(req.OrderId, 10, 64)
+	order, err := h.tradingService.FindOrder(account.ID, uint(orderID), req.OrderLinkId)
+	if err == nil && order.Symbol == req.Symbol {
+		order, err = h.tradingService.CancelOrder(account.ID, order.ID)
+	} else {
+		err = service.ErrOrderNotFound
+	}
+	if err != nil {
+		h.errorResponse(c, 110001, "Order does not exist")
+		return
+	}
 
 	c.JSON(200, gin.H{
 		"retCode": 0,
 		"retMsg":  "OK",
 		"result": gin.H{
-			"orderId":     req.OrderId,
-			"orderLinkId": "",
+			"orderId":     strconv.Itoa(int(order.ID)),
+			"orderLinkId": order.ClientOrderID,
 		},
 		"time": time.Now().UnixMilli(),
 	})
 }
 
+// CancelBatchOrders handles POST /v5/order/cancel-batch
+// Orders are canceled independently; retExtInfo carries the result code of each one
+func (h *Handler) CancelBatchOrders(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, 10003, "Invalid apiKey")
+		return
+	}
+
+	var req struct {
+		Category string `json:"category"`
+		Request  []struct {
+			Symbol      string `json:"symbol"`
+			OrderId     string `json:"orderId"`
+			OrderLinkId string `json:"orderLinkId"`
+		} `json:"request"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorResponse(c, 10001, err.Error())
+		return
+	}
+	if len(req.Request) == 0 || len(req.Request) > maxBatchOrders {
+		h.errorResponse(c, 10001, "Invalid request: the batch must contain 1 to 20 orders")
+		return
+	}
+
+	list := make([]gin.H, 0, len(req.Request))
+	codes := make([]gin.H, 0, len(req.Request))
+	for _, item := range req.Request {
+		var order *models.Order
+		var err error
+		if item.OrderId != "" {
+			orderID, _ := strconv.ParseUint(item.OrderId, 10, 64)
+			order, err = h.tradingService.GetOrderStatus(account.ID, uint(orderID))
+		} else {
+			order, err = h.tradingService.GetOrderByClientID(account.ID, item.OrderLinkId)
+		}
+		if err == nil && order.Symbol == item.Symbol {
+			order, err = h.tradingService.CancelOrder(account.ID, order.ID)
+		} else {
+			err = service.ErrOrderNotFound
+		}
+
+		if err != nil {
+			list = append(list, gin.H{"category": req.Category, "symbol": item.Symbol, "orderId": item.OrderId, "orderLinkId": item.OrderLinkId})
+			codes = append(codes, gin.H{"code": 110001, "msg": "Order does not exist"})
+			continue
+		}
+		list = append(list, gin.H{
+			"category":    req.Category,
+			"symbol":      order.Symbol,
+			"orderId":     strconv.Itoa(int(order.ID)),
+			"orderLinkId": order.ClientOrderID,
+		})
+		codes = append(codes, gin.H{"code": 0, "msg": "OK"})
+	}
+
+	batchResponse(c, list, codes)
+}
+
+// batchResponse writes the result of a batch operation; the batch itself always succeeds
+func batchResponse(c *gin.Context, list, codes []gin.H) {
+	c.JSON(200, gin.H{
+		"retCode":    0,
+		"retMsg":     "OK",
+		"result":     gin.H{"list": list},
+		"retExtInfo": gin.H{"list": codes},
+		"time":       time.Now().UnixMilli(),
+	})
+}
+
 // CancelAllOrders handles POST /v5/order/cancel-all
 func (h *Handler) CancelAllOrders(c *gin.Context) {
 	account := middleware.GetAccount(c)
@@ -451,6 +1054,167 @@ func (h *Handler) SetLeverage(c *gin.Context) {
 	})
 }
 
+// SwitchIsolated handles POST /v5/position/switch-isolated
+func (h *Handler) SwitchIsolated(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, 10003, "Invalid apiKey")
+		return
+	}
+
+	var req struct {
+		Category     string `json:"category"`
+		Symbol       string `json:"symbol"`
+		TradeMode    int    `json:"tradeMode"` // 0: cross, 1: isolated
+		BuyLeverage  string `json:"buyLeverage"`
+		SellLeverage string `json:"sellLeverage"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorResponse(c, 10001, err.Error())
+		return
+	}
+
+	marginMode := models.MarginModeCross
+	if req.TradeMode == 1 {
+		marginMode = models.MarginModeIsolated
+	}
+
+	if err := h.tradingService.SetMarginMode(account.ID, req.Symbol, marginMode); err != nil {
+		switch err {
+		case service.ErrSettingNotChanged:
+			h.errorResponse(c, 110026, "Cross/isolated margin mode is not modified")
+		case service.ErrPositionExists:
+			h.errorResponse(c, 110024, "You have an existing position or active order, so margin mode cannot be switched")
+		default:
+			h.errorResponse(c, 10000, err.Error())
+		}
+		return
+	}
+
+	if leverage, _ := strconv.Atoi(req.BuyLeverage); leverage > 0 {
+		if err := h.tradingService.SetLeverage(account.ID, req.Symbol, leverage); err != nil {
+			h.errorResponse(c, 10001, err.Error())
+			return
+		}
+	}
+
+	c.JSON(200, gin.H{
+		"retCode": 0,
+		"retMsg":  "OK",
+		"result":  gin.H{},
+		"time":    time.Now().UnixMilli(),
+	})
+}
+
+// AddMargin handles POST /v5/position/add-margin
+func (h *Handler) AddMargin(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, 10003, "Invalid apiKey")
+		return
+	}
+
+	var req struct {
+		Category    string `json:"category"`
+		Symbol      string `json:"symbol"`
+		Margin      string `json:"margin"` // Positive to add, negative to reduce
+		PositionIdx int    `json:"positionIdx"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorResponse(c, 10001, err.Error())
+		return
+	}
+
+	amount, err := strconv.ParseFloat(req.Margin, 64)
+	if err != nil || amount == 0 {
+		h.errorResponse(c, 10001, "margin is invalid")
+		return
+	}
+
+	var side models.PositionSide
+	switch req.PositionIdx {
+	case 1:
+		side = models.PositionSideLong
+	case 2:
+		side = models.PositionSideShort
+	}
+
+	position, err := h.tradingService.AdjustIsolatedMargin(account.ID, req.Symbol, side, amount)
+	if err != nil {
+		switch err {
+		case service.ErrPositionNotFound:
+			h.errorResponse(c, 110001, "Position does not exist")
+		case service.ErrNotIsolated:
+			h.errorResponse(c, 10001, "Margin can only be adjusted in isolated margin mode")
+		case service.ErrInsufficientBalance:
+			h.errorResponse(c, 110012, "Insufficient available balance")
+		case service.ErrInsufficientMargin:
+			h.errorResponse(c, 110011, "Liquidation will be triggered immediately by this adjustment")
+		default:
+			h.errorResponse(c, 10000, err.Error())
+		}
+		return
+	}
+
+	result := formatPosition(position)
+	result["category"] = "linear"
+	result["positionIdx"] = req.PositionIdx
+	result["autoAddMargin"] = 0
+
+	c.JSON(200, gin.H{
+		"retCode": 0,
+		"retMsg":  "OK",
+		"result":  result,
+		"time":    time.Now().UnixMilli(),
+	})
+}
+
+// SwitchPositionMode handles POST /v5/position/switch-mode
+func (h *Handler) SwitchPositionMode(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, 10003, "Invalid apiKey")
+		return
+	}
+
+	var req struct {
+		Category string `json:"category"`
+		Symbol   string `json:"symbol"`
+		Coin     string `json:"coin"`
+		Mode     int    `json:"mode"` // 0: one-way, 3: hedge
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorResponse(c, 10001, err.Error())
+		return
+	}
+	if req.Symbol == "" {
+		h.errorResponse(c, 10001, "symbol is required")
+		return
+	}
+
+	if err := h.tradingService.SetPositionMode(account.ID, req.Symbol, req.Mode == 3); err != nil {
+		switch err {
+		case service.ErrSettingNotChanged:
+			h.errorResponse(c, 110025, "Position mode is not modified")
+		case service.ErrPositionExists:
+			h.errorResponse(c, 110024, "You have an existing position or active order, so position mode cannot be switched")
+		default:
+			h.errorResponse(c, 10000, err.Error())
+		}
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"retCode": 0,
+		"retMsg":  "OK",
+		"result":  gin.H{},
+		"time":    time.Now().UnixMilli(),
+	})
+}
+
 // GetTickers handles GET /v5/market/tickers
 func (h *Handler) GetTickers(c *gin.Context) {
 	symbol := c.Query("symbol")
@@ -513,6 +1277,182 @@ func (h *Handler) GetTickers(c *gin.Context) {
 
 // Helper functions
 
+// formatOrder formats an order for Bybit response
+func formatOrder(order *models.Order) gin.H {
+	side := "Buy"
+	if order.Side == models.OrderSideSell {
+		side = "Sell"
+	}
+
+	// Liquidation orders are market orders created by the system; conditional orders with a price are Limit
+	orderType := "Market"
+	if order.Type == models.OrderTypeLimit || order.Price > 0 {
+		orderType = "Limit"
+	}
+	createType := "CreateByUser"
+	if order.IsLiquidation() {
+		createType = "CreateByLiquidate"
+	}
+	stopOrderType := ""
+	switch order.Type {
+	case models.OrderTypeStopMarket, models.OrderTypeStopLoss:
+		stopOrderType = "StopLoss"
+	case models.OrderTypeTakeProfit:
+		stopOrderType = "TakeProfit"
+	case models.OrderTypeTrailingStop:
+		stopOrderType = "TrailingStop"
+	}
+
+	// Market orders are IOC; rejectReason tells why a limit order expired at placement
+	timeInForce, rejectReason := "IOC", "EC_NoError"
+	if order.Type == models.OrderTypeLimit {
+		switch order.TimeInForce {
+		case models.TimeInForceIOC:
+			timeInForce = "IOC"
+			if order.Status == models.OrderStatusExpired {
+				rejectReason = "EC_NoImmediateQtyToFill"
+			}
+		case models.TimeInForceFOK:
+			timeInForce = "FOK"
+			if order.Status == models.OrderStatusExpired {
+				rejectReason = "EC_NoEnoughQtyToFill"
+			}
+		case models.TimeInForceGTX:
+			timeInForce = "PostOnly"
+			if order.Status == models.OrderStatusExpired {
+				rejectReason = "EC_PostOnlyWillTakeLiquidity"
+			}
+		default:
+			timeInForce = "GTC"
+		}
+	}
+
+	result := gin.H{
+		"orderId":       strconv.Itoa(int(order.ID)),
+		"orderLinkId":   order.ClientOrderID,
+		"symbol":        order.Symbol,
+		"side":          side,
+		"orderType":     orderType,
+		"price":         strconv.FormatFloat(order.Price, 'f', 8, 64),
+		"qty":           strconv.FormatFloat(order.Quantity, 'f', 8, 64),
+		"cumExecQty":    strconv.FormatFloat(order.FilledQty, 'f', 8, 64),
+		"cumExecValue":  strconv.FormatFloat(order.FilledQty*order.AvgPrice, 'f', 8, 64),
+		"avgPrice":      strconv.FormatFloat(order.AvgPrice, 'f', 8, 64),
+		"triggerPrice":  strconv.FormatFloat(order.StopPrice, 'f', 8, 64),
+		"stopOrderType": stopOrderType,
+		"orderStatus":   convertOrderStatus(order.Status),
+		"timeInForce":   timeInForce,
+		"rejectReason":  rejectReason,
+		"createType":    createType,
+		"reduceOnly":    order.ReduceOnly,
+		"createdTime":   strconv.FormatInt(order.CreatedAt.UnixMilli(), 10),
+		"updatedTime":   strconv.FormatInt(order.UpdatedAt.UnixMilli(), 10),
+	}
+
+	// A trailing stop's triggerPrice trails its watermark; watermarkPrice is a simulator extension
+	if order.Type == models.OrderTypeTrailingStop {
+		result["watermarkPrice"] = strconv.FormatFloat(order.Watermark, 'f', 8, 64)
+	}
+	if order.TriggerPriceType != "" {
+		result["triggerBy"] = triggerByName(order.TriggerPriceType)
+	}
+	// triggeredOrderId is a simulator extension: the limit order placed by a triggered Limit conditional order
+	if order.TriggeredOrderID != nil {
+		result["triggeredOrderId"] = strconv.Itoa(int(*order.TriggeredOrderID))
+	}
+	return result
+}
+
+// triggerByName returns the Bybit triggerBy of a trigger price type
+func triggerByName(priceType models.TriggerPriceType) string {
+	switch priceType {
+	case models.TriggerPriceMark:
+		return "MarkPrice"
+	case models.TriggerPriceIndex:
+		return "IndexPrice"
+	default:
+		return "LastPrice"
+	}
+}
+
+// formatPosition formats a position for Bybit response
+func formatPosition(pos *models.Position) gin.H {
+	// Closed positions are reported with an empty side and zero size
+	side := "Buy"
+	if pos.Side == models.PositionSideShort {
+		side = "Sell"
+	}
+	if pos.Quantity == 0 {
+		side = ""
+	}
+	tradeMode := 0
+	if pos.MarginMode == models.MarginModeIsolated {
+		tradeMode = 1
+	}
+
+	return gin.H{
+		"symbol":          pos.Symbol,
+		"side":            side,
+		"size":            strconv.FormatFloat(pos.Quantity, 'f', 8, 64),
+		"avgPrice":        strconv.FormatFloat(pos.EntryPrice, 'f', 8, 64),
+		"markPrice":       strconv.FormatFloat(pos.MarkPrice, 'f', 8, 64),
+		"positionValue":   strconv.FormatFloat(pos.MarkPrice*pos.Quantity, 'f', 8, 64),
+		"leverage":        strconv.Itoa(pos.Leverage),
+		"unrealisedPnl":   strconv.FormatFloat(pos.UnrealizedPnL, 'f', 8, 64),
+		"liqPrice":        strconv.FormatFloat(pos.LiquidationPrice, 'f', 8, 64),
+		"positionIM":      strconv.FormatFloat(pos.Margin, 'f', 8, 64),
+		"positionBalance": strconv.FormatFloat(pos.Margin, 'f', 8, 64),
+		"tradeMode":       tradeMode,
+		"positionIdx":     0,
+		"riskId":          1,
+		"createdTime":     strconv.FormatInt(pos.CreatedAt.UnixMilli(), 10),
+		"updatedTime":     strconv.FormatInt(pos.UpdatedAt.UnixMilli(), 10),
+	}
+}
+
+// formatWallet formats an account balance as a Bybit unified wallet
+func formatWallet(balance map[string]float64) gin.H {
+	return gin.H{
+		"accountType":           "UNIFIED",
+		"accountIMRate":         "0",
+		"accountMMRate":         "0",
+		"totalEquity":           strconv.FormatFloat(balance["equity"], 'f', 8, 64),
+		"totalWalletBalance":    strconv.FormatFloat(balance["balance"], 'f', 8, 64),
+		"totalMarginBalance":    strconv.FormatFloat(balance["balance"], 'f', 8, 64),
+		"totalAvailableBalance": strconv.FormatFloat(balance["available"], 'f', 8, 64),
+		"totalPerpUPL":          strconv.FormatFloat(balance["unrealized_pnl"], 'f', 8, 64),
+		"totalInitialMargin":    strconv.FormatFloat(balance["margin"], 'f', 8, 64),
+		"coin": []gin.H{
+			{
+				"coin":                "USDT",
+				"equity":              strconv.FormatFloat(balance["equity"], 'f', 8, 64),
+				"walletBalance":       strconv.FormatFloat(balance["balance"], 'f', 8, 64),
+				"availableToWithdraw": strconv.FormatFloat(balance["available"], 'f', 8, 64),
+				"unrealisedPnl":       strconv.FormatFloat(balance["unrealized_pnl"], 'f', 8, 64),
+				"cumRealisedPnl":      "0",
+			},
+		},
+	}
+}
+
+// convertOrderStatus maps order status to Bybit orderStatus
+func convertOrderStatus(status models.OrderStatus) string {
+	switch status {
+	case models.OrderStatusPartiallyFilled:
+		return "PartiallyFilled"
+	case models.OrderStatusFilled:
+		return "Filled"
+	case models.OrderStatusCanceled, models.OrderStatusExpired:
+		return "Cancelled"
+	case models.OrderStatusRejected:
+		return "Rejected"
+	case models.OrderStatusTriggered:
+		return "Triggered"
+	default:
+		return "New"
+	}
+}
+
 func (h *Handler) errorResponse(c *gin.Context, code int, msg string) {
 	c.JSON(200, gin.H{
 		"retCode": code,
@@ -523,17 +1463,33 @@ func (h *Handler) errorResponse(c *gin.Context, code int, msg string) {
 }
 
 func (h *Handler) handleError(c *gin.Context, err error) {
+	code, msg := errorCode(err)
+	h.errorResponse(c, code, msg)
+}
+
+// errorCode maps service errors to Bybit error codes
+func errorCode(err error) (int, string) {
 	switch err {
 	case service.ErrInsufficientBalance:
-		h.errorResponse(c, 110007, "Insufficient account balance")
+		return 110007, "Insufficient account balance"
 	case service.ErrInvalidSymbol:
-		h.errorResponse(c, 10001, "Invalid symbol")
+		return 10001, "Invalid symbol"
 	case service.ErrInvalidQuantity:
-		h.errorResponse(c, 10001, "Invalid qty")
+		return 10001, "Invalid qty"
 	case service.ErrNoOpenPosition:
-		h.errorResponse(c, 110028, "position not exist")
+		return 110028, "position not exist"
+	case service.ErrDuplicateClientID:
+		return 110072, "OrderLinkedID is duplicate"
+	case service.ErrInvalidCallback:
+		return 10001, "Invalid trailingStop"
+	case service.ErrInvalidTrigger:
+		return 10001, "Invalid triggerBy"
+	case service.ErrInvalidTimeInForce:
+		return 10001, "Invalid timeInForce"
+	case service.ErrPostOnlyWouldTake:
+		return 10001, "The post-only order would take liquidity"
 	default:
-		h.errorResponse(c, 10000, err.Error())
+		return 10000, err.Error()
 	}
 }
 
@@ -555,21 +1511,35 @@ func (h *Handler) RegisterRoutes(router *gin.Engine, authMiddleware gin.HandlerF
 		account := v5.Group("/account")
 		{
 			account.GET("/wallet-balance", h.GetWalletBalance)
+			account.GET("/transaction-log", h.GetTransactionLog)
 		}
 
 		position := v5.Group("/position")
 		{
 			position.GET("/list", h.GetPositionInfo)
 			position.POST("/set-leverage", middleware.TradingLoggerMiddleware(), h.SetLeverage)
+			position.POST("/switch-isolated", middleware.TradingLoggerMiddleware(), h.SwitchIsolated)
+			position.POST("/switch-mode", middleware.TradingLoggerMiddleware(), h.SwitchPositionMode)
+			position.POST("/add-margin", middleware.TradingLoggerMiddleware(), h.AddMargin)
 			position.POST("/trading-stop", middleware.TradingLoggerMiddleware(), h.SetTradingStop)
+			position.GET("/closed-pnl", h.GetClosedPnL)
 		}
 
 		order := v5.Group("/order")
 		{
 			order.POST("/create", middleware.TradingLoggerMiddleware(), h.CreateOrder)
+			order.POST("/amend", middleware.TradingLoggerMiddleware(), h.AmendOrder)
 			order.POST("/cancel", middleware.TradingLoggerMiddleware(), h.CancelOrder)
 			order.POST("/cancel-all", middleware.TradingLoggerMiddleware(), h.CancelAllOrders)
+			order.POST("/create-batch", middleware.TradingLoggerMiddleware(), h.CreateBatchOrders)
+			order.POST("/cancel-batch", middleware.TradingLoggerMiddleware(), h.CancelBatchOrders)
 			order.GET("/realtime", h.GetOpenOrders)
+			order.GET("/history", h.GetOrderHistory)
+		}
+
+		execution := v5.Group("/execution")
+		{
+			execution.GET("/list", h.GetExecutionList)
 		}
 	}
 }
@@ -0,0 +1,310 @@
+package bybit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ccxt-simulator/internal/middleware"
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/ccxt-simulator/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const wsWriteWait = 10 * time.Second
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// privateTopics lists the supported private topics
+var privateTopics = map[string]bool{
+	"order":     true,
+	"execution": true,
+	"position":  true,
+	"wallet":    true,
+}
+
+// wsRequest is a Bybit WebSocket operation request
+type wsRequest struct {
+	ReqID string            `json:"req_id"`
+	Op    string            `json:"op"`
+	Args  []json.RawMessage `json:"args"`
+}
+
+// privateSession is a single private WebSocket connection
+type privateSession struct {
+	conn    *websocket.Conn
+	connID  string
+	writeMu sync.Mutex
+
+	account  *models.Account
+	topics   map[string]bool
+	topicsMu sync.RWMutex
+}
+
+func (s *privateSession) write(v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return s.conn.WriteJSON(v)
+}
+
+func (s *privateSession) reply(req wsRequest, success bool, msg string) {
+	s.write(gin.H{
+		"success": success,
+		"ret_msg": msg,
+		"op":      req.Op,
+		"conn_id": s.connID,
+		"req_id":  req.ReqID,
+	})
+}
+
+func (s *privateSession) subscribed(topic string) bool {
+	s.topicsMu.RLock()
+	defer s.topicsMu.RUnlock()
+	return s.topics[topic]
+}
+
+// topicName strips the category suffix (e.g. "order.linear" -> "order")
+func topicName(topic string) string {
+	if i := strings.Index(topic, "."); i >= 0 {
+		return topic[:i]
+	}
+	return topic
+}
+
+// PrivateWebSocket handles GET /v5/private
+func (h *Handler) PrivateWebSocket(c *gin.Context) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	session := &privateSession{
+		conn:   conn,
+		connID: uuid.New().String(),
+		topics: make(map[string]bool),
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+
+		var req wsRequest
+		if err := json.Unmarshal(message, &req); err != nil {
+			session.write(gin.H{"success": false, "ret_msg": "Params Error", "conn_id": session.connID})
+			continue
+		}
+
+		switch req.Op {
+		case "ping":
+			session.write(gin.H{
+				"req_id":  req.ReqID,
+				"op":      "pong",
+				"args":    []string{strconv.FormatInt(time.Now().UnixMilli(), 10)},
+				"conn_id": session.connID,
+			})
+
+		case "auth":
+			if session.account != nil {
+				session.reply(req, false, "Request already authorized")
+				continue
+			}
+			var apiKey, signature string
+			var expires int64
+			if len(req.Args) < 3 ||
+				json.Unmarshal(req.Args[0], &apiKey) != nil ||
+				json.Unmarshal(req.Args[1], &expires) != nil ||
+				json.Unmarshal(req.Args[2], &signature) != nil {
+				session.reply(req, false, "Params Error")
+				continue
+			}
+			account, msg := h.wsLogin(apiKey, expires, signature)
+			if account == nil {
+				session.reply(req, false, msg)
+				continue
+			}
+			session.account = account
+			session.reply(req, true, "")
+
+			events := h.tradingService.Events().Subscribe(account.ID)
+			defer h.tradingService.Events().Unsubscribe(account.ID, events)
+			go h.pumpEvents(session, events, done)
+
+		case "subscribe", "unsubscribe":
+			if session.account == nil {
+				session.reply(req, false, "Request not authorized")
+				continue
+			}
+
+			topics := make([]string, 0, len(req.Args))
+			valid := true
+			for _, raw := range req.Args {
+				var topic string
+				if json.Unmarshal(raw, &topic) != nil || !privateTopics[topicName(topic)] {
+					valid = false
+					break
+				}
+				topics = append(topics, topicName(topic))
+			}
+			if !valid {
+				session.reply(req, false, "Invalid topic")
+				continue
+			}
+
+			session.topicsMu.Lock()
+			for _, topic := range topics {
+				if req.Op == "subscribe" {
+					session.topics[topic] = true
+				} else {
+					delete(session.topics, topic)
+				}
+			}
+			session.topicsMu.Unlock()
+			session.reply(req, true, "")
+
+		default:
+			session.reply(req, false, "Params Error")
+		}
+	}
+}
+
+// RegisterWebSocketRoutes registers the Bybit public and private WebSocket endpoints
+func (h *Handler) RegisterWebSocketRoutes(router *gin.Engine, login middleware.BybitLoginFunc) {
+	h.wsLogin = login
+	router.GET("/v5/public/linear", h.PublicWebSocket)
+	router.GET("/v5/private", h.PrivateWebSocket)
+}
+
+// pumpEvents forwards the account's user events to subscribed topics
+func (h *Handler) pumpEvents(session *privateSession, events chan service.UserEvent, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case event := <-events:
+			if event.Type == service.UserEventAccountUpdate {
+				h.pushAccountUpdate(session, event)
+			} else {
+				h.pushOrderUpdate(session, event)
+			}
+		}
+	}
+}
+
+// pushOrderUpdate pushes order and execution topics
+func (h *Handler) pushOrderUpdate(session *privateSession, event service.UserEvent) {
+	order := event.Order
+
+	if session.subscribed("order") {
+		data := formatOrder(order)
+		data["category"] = "linear"
+		data["leavesQty"] = strconv.FormatFloat(order.Quantity-order.FilledQty, 'f', 8, 64)
+		data["positionIdx"] = 0
+		data["cumExecFee"] = "0"
+		data["updatedTime"] = strconv.FormatInt(event.Time.UnixMilli(), 10)
+		if event.Trade != nil {
+			data["cumExecFee"] = strconv.FormatFloat(event.Trade.Fee, 'f', 8, 64)
+		}
+		h.push(session, "order", event.Time, []gin.H{data})
+	}
+
+	trade := event.Trade
+	if trade == nil || !session.subscribed("execution") {
+		return
+	}
+
+	// Liquidation fills are reported as bust trades
+	execType := "Trade"
+	if event.Liquidation {
+		execType = "BustTrade"
+	}
+	feeRate := 0.0
+	if value := trade.Price * trade.Quantity; value > 0 {
+		feeRate = trade.Fee / value
+	}
+	closedSize := 0.0
+	if order.ReduceOnly {
+		closedSize = trade.Quantity
+	}
+	orderType := formatOrder(order)["orderType"]
+	side := "Buy"
+	if trade.Side == models.OrderSideSell {
+		side = "Sell"
+	}
+
+	h.push(session, "execution", event.Time, []gin.H{
+		{
+			"category":    "linear",
+			"symbol":      trade.Symbol,
+			"execId":      strconv.Itoa(int(trade.ID)),
+			"execPrice":   strconv.FormatFloat(trade.Price, 'f', 8, 64),
+			"execQty":     strconv.FormatFloat(trade.Quantity, 'f', 8, 64),
+			"execValue":   strconv.FormatFloat(trade.Price*trade.Quantity, 'f', 8, 64),
+			"execFee":     strconv.FormatFloat(trade.Fee, 'f', 8, 64),
+			"feeRate":     strconv.FormatFloat(feeRate, 'f', 8, 64),
+			"execType":    execType,
+			"execTime":    strconv.FormatInt(trade.ExecutedAt.UnixMilli(), 10),
+			"isMaker":     trade.IsMaker,
+			"orderId":     strconv.Itoa(int(order.ID)),
+			"orderLinkId": order.ClientOrderID,
+			"orderPrice":  strconv.FormatFloat(order.Price, 'f', 8, 64),
+			"orderQty":    strconv.FormatFloat(order.Quantity, 'f', 8, 64),
+			"orderType":   orderType,
+			"side":        side,
+			"leavesQty":   strconv.FormatFloat(order.Quantity-order.FilledQty, 'f', 8, 64),
+			"closedSize":  strconv.FormatFloat(closedSize, 'f', 8, 64),
+			"execPnl":     strconv.FormatFloat(trade.RealizedPnL, 'f', 8, 64),
+		},
+	})
+}
+
+// pushAccountUpdate pushes position and wallet topics
+func (h *Handler) pushAccountUpdate(session *privateSession, event service.UserEvent) {
+	if session.subscribed("position") && len(event.Positions) > 0 {
+		data := make([]gin.H, 0, len(event.Positions))
+		for _, pos := range event.Positions {
+			if pos.Quantity > 0 {
+				if markPrice, err := h.priceService.GetPrice("bybit", pos.Symbol); err == nil {
+					pos.MarkPrice = markPrice
+					pos.UnrealizedPnL = pos.CalculateUnrealizedPnL(markPrice)
+				}
+			}
+			position := formatPosition(&pos)
+			position["category"] = "linear"
+			position["positionStatus"] = "Normal"
+			data = append(data, position)
+		}
+		h.push(session, "position", event.Time, data)
+	}
+
+	if session.subscribed("wallet") {
+		balance, err := h.tradingService.GetBalance(session.account.ID, models.ExchangeBybit)
+		if err != nil {
+			return
+		}
+		h.push(session, "wallet", event.Time, []gin.H{formatWallet(balance)})
+	}
+}
+
+// push sends topic data in Bybit v5 push format
+func (h *Handler) push(session *privateSession, topic string, eventTime time.Time, data []gin.H) {
+	session.write(gin.H{
+		"id":           uuid.New().String(),
+		"topic":        topic,
+		"creationTime": eventTime.UnixMilli(),
+		"data":         data,
+	})
+}
@@ -0,0 +1,171 @@
+package bybit
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ccxt-simulator/internal/exchange"
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// publicSession is a single public WebSocket connection
+type publicSession struct {
+	conn    *websocket.Conn
+	connID  string
+	writeMu sync.Mutex
+
+	topics   map[string]bool // e.g. tickers.BTCUSDT
+	topicsMu sync.RWMutex
+}
+
+func (s *publicSession) write(v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return s.conn.WriteJSON(v)
+}
+
+func (s *publicSession) reply(req wsRequest, success bool, msg string) {
+	s.write(gin.H{
+		"success": success,
+		"ret_msg": msg,
+		"op":      req.Op,
+		"conn_id": s.connID,
+		"req_id":  req.ReqID,
+	})
+}
+
+func (s *publicSession) subscribed(topic string) bool {
+	s.topicsMu.RLock()
+	defer s.topicsMu.RUnlock()
+	return s.topics[topic]
+}
+
+// PublicWebSocket handles GET /v5/public/linear
+func (h *Handler) PublicWebSocket(c *gin.Context) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	session := &publicSession{
+		conn:   conn,
+		connID: uuid.New().String(),
+		topics: make(map[string]bool),
+	}
+
+	ticks := h.priceService.MarketData().Subscribe("bybit")
+	defer h.priceService.MarketData().Unsubscribe("bybit", ticks)
+
+	done := make(chan struct{})
+	defer close(done)
+	go h.pumpTicks(session, ticks, done)
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+
+		var req wsRequest
+		if err := json.Unmarshal(message, &req); err != nil {
+			session.write(gin.H{"success": false, "ret_msg": "Params Error", "conn_id": session.connID})
+			continue
+		}
+
+		switch req.Op {
+		case "ping":
+			session.reply(req, true, "pong")
+
+		case "subscribe", "unsubscribe":
+			topics := make([]string, 0, len(req.Args))
+			valid := true
+			for _, raw := range req.Args {
+				var topic string
+				if json.Unmarshal(raw, &topic) != nil || !strings.HasPrefix(topic, "tickers.") || len(topic) == len("tickers.") {
+					valid = false
+					break
+				}
+				topics = append(topics, topic)
+			}
+			if !valid {
+				session.reply(req, false, "Invalid topic")
+				continue
+			}
+
+			session.topicsMu.Lock()
+			for _, topic := range topics {
+				if req.Op == "subscribe" {
+					session.topics[topic] = true
+				} else {
+					delete(session.topics, topic)
+				}
+			}
+			session.topicsMu.Unlock()
+			session.reply(req, true, "")
+
+		default:
+			session.reply(req, false, "Params Error")
+		}
+	}
+}
+
+// pumpTicks forwards live ticks to subscribed ticker topics
+func (h *Handler) pumpTicks(session *publicSession, ticks chan exchange.PriceUpdate, done chan struct{}) {
+	var seq int64
+	for {
+		select {
+		case <-done:
+			return
+		case update := <-ticks:
+			topic := "tickers." + update.Symbol
+			if !session.subscribed(topic) {
+				continue
+			}
+			seq++
+			session.write(gin.H{
+				"topic": topic,
+				"type":  "snapshot",
+				"data":  formatTicker(update),
+				"cs":    seq,
+				"ts":    time.Now().UnixMilli(),
+			})
+		}
+	}
+}
+
+// formatTicker formats a tick for the tickers topic
+// 24h statistics are not tracked, so they report the current price and zero volume
+func formatTicker(update exchange.PriceUpdate) gin.H {
+	price := strconv.FormatFloat(update.Price, 'f', 8, 64)
+	interval := models.FundingInterval(models.ExchangeBybit)
+	nextFunding := time.Now().UTC().Truncate(interval).Add(interval)
+
+	return gin.H{
+		"symbol":          update.Symbol,
+		"tickDirection":   "ZeroPlusTick",
+		"lastPrice":       price,
+		"markPrice":       price,
+		"indexPrice":      price,
+		"prevPrice24h":    price,
+		"price24hPcnt":    "0",
+		"highPrice24h":    price,
+		"lowPrice24h":     price,
+		"bid1Price":       strconv.FormatFloat(update.BestBid(), 'f', 8, 64),
+		"bid1Size":        "0",
+		"ask1Price":       strconv.FormatFloat(update.BestAsk(), 'f', 8, 64),
+		"ask1Size":        "0",
+		"openInterest":    "0",
+		"turnover24h":     "0",
+		"volume24h":       "0",
+		"fundingRate":     strconv.FormatFloat(models.DefaultFundingRate, 'f', 8, 64),
+		"nextFundingTime": strconv.FormatInt(nextFunding.UnixMilli(), 10),
+	}
+}
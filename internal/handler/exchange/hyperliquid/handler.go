@@ -1,35 +1,47 @@
 package hyperliquid
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ccxt-simulator/internal/middleware"
 	"github.com/ccxt-simulator/internal/models"
 	"github.com/ccxt-simulator/internal/service"
+	"github.com/ccxt-simulator/pkg/ethsig"
 	"github.com/gin-gonic/gin"
 )
 
 // Handler handles Hyperliquid-compatible API requests
 type Handler struct {
 	tradingService      *service.TradingService
+	accountService      *service.AccountService
+	cancelScheduler     *cancelScheduler
 	priceService        *service.PriceService
 	exchangeInfoService *service.ExchangeInfoService
+	fundingService      *service.FundingService
 }
 
 // NewHandler creates a new Hyperliquid handler
-func NewHandler(tradingService *service.TradingService, priceService *service.PriceService, exchangeInfoService *service.ExchangeInfoService) *Handler {
-	return &Handler{
+func NewHandler(tradingService *service.TradingService, accountService *service.AccountService, priceService *service.PriceService, exchangeInfoService *service.ExchangeInfoService, fundingService *service.FundingService) *Handler {
+	h := &Handler{
 		tradingService:      tradingService,
+		accountService:      accountService,
 		priceService:        priceService,
 		exchangeInfoService: exchangeInfoService,
+		fundingService:      fundingService,
 	}
+	h.cancelScheduler = newCancelScheduler(tradingService)
+	return h
 }
 
 // GetUserState handles POST /info (type: clearinghouseState)
-func (h *Handler) GetUserState(c *gin.Context) {
-	account := middleware.GetAccount(c)
+func (h *Handler) GetUserState(c *gin.Context, user string) {
+	account := h.infoAccount(c, user)
 	if account == nil {
-		c.JSON(401, gin.H{"error": "Unauthorized"})
 		return
 	}
 
@@ -90,9 +102,8 @@ func (h *Handler) GetUserState(c *gin.Context) {
 
 // GetOpenOrders handles POST /info (type: openOrders)
 func (h *Handler) GetOpenOrders(c *gin.Context, user string) {
-	account := middleware.GetAccount(c)
+	account := h.infoAccount(c, user)
 	if account == nil {
-		c.JSON(401, gin.H{"error": "Unauthorized"})
 		return
 	}
 
@@ -104,7 +115,7 @@ func (h *Handler) GetOpenOrders(c *gin.Context, user string) {
 			"coin":      convertSymbol(order.Symbol),
 			"oid":       order.ID,
 			"cloid":     order.ClientOrderID,
-			"side":      string(order.Side),
+			"side":      formatSide(order.Side),
 			"limitPx":   strconv.FormatFloat(order.Price, 'f', 8, 64),
 			"sz":        strconv.FormatFloat(order.Quantity, 'f', 8, 64),
 			"origSz":    strconv.FormatFloat(order.Quantity, 'f', 8, 64),
@@ -115,8 +126,9 @@ func (h *Handler) GetOpenOrders(c *gin.Context, user string) {
 	c.JSON(200, result)
 }
 
-// PlaceOrder handles POST /exchange (action: order)
-func (h *Handler) PlaceOrder(c *gin.Context, req map[string]interface{}) {
+// PlaceOrders handles POST /exchange (action: order)
+// Each order of the batch gets its own status; grouping (na/normalTpsl/positionTpsl) decides how TP/SL children size
+func (h *Handler) PlaceOrders(c *gin.Context, req map[string]interface{}) {
 	account := middleware.GetAccount(c)
 	if account == nil {
 		c.JSON(401, gin.H{"error": "Unauthorized"})
@@ -125,205 +137,464 @@ func (h *Handler) PlaceOrder(c *gin.Context, req map[string]interface{}) {
 
 	orders, ok := req["orders"].([]interface{})
 	if !ok || len(orders) == 0 {
-		c.JSON(400, gin.H{"error": "Invalid orders"})
+		h.errorResponse(c, "Invalid orders")
 		return
 	}
 
-	// Process first order
-	orderMap := orders[0].(map[string]interface{})
+	grouping, _ := req["grouping"].(string)
+	if grouping == "" {
+		grouping = "na"
+	}
 
-	// Get asset and convert to symbol
-	a, _ := orderMap["a"].(float64)
-	symbol := assetIndexToSymbol(int(a))
+	statuses := make([]interface{}, 0, len(orders))
+	for _, raw := range orders {
+		statuses = append(statuses, h.placeOrder(account, raw, grouping))
+	}
 
-	// Get order details
-	isBuy, _ := orderMap["b"].(bool)
-	priceStr, _ := orderMap["p"].(string)
-	sizeStr, _ := orderMap["s"].(string)
-	reduceOnly, _ := orderMap["r"].(bool)
+	h.statusesResponse(c, statuses)
+}
 
-	quantity, _ := strconv.ParseFloat(sizeStr, 64)
-	price, _ := strconv.ParseFloat(priceStr, 64)
+// placeOrder places one order wire {a, b, p, s, r, t, c} and returns its status
+func (h *Handler) placeOrder(account *models.Account, raw interface{}, grouping string) gin.H {
+	fields, ok := raw.(map[string]interface{})
+	if !ok {
+		return gin.H{"error": "Invalid order"}
+	}
 
-	var posSide models.PositionSide
-	if isBuy {
-		posSide = models.PositionSideLong
-	} else {
-		posSide = models.PositionSideShort
+	asset, err := h.resolveAsset(fields["a"])
+	if err != nil {
+		return gin.H{"error": fmt.Sprintf("Unknown asset %v", fields["a"])}
+	}
+
+	isBuy, _ := fields["b"].(bool)
+	priceStr, _ := fields["p"].(string)
+	sizeStr, _ := fields["s"].(string)
+	reduceOnly, _ := fields["r"].(bool)
+	cloid, _ := fields["c"].(string)
+	orderType, _ := fields["t"].(map[string]interface{})
+
+	if trigger, ok := orderType["trigger"].(map[string]interface{}); ok {
+		return h.placeTriggerOrder(account, asset, isBuy, priceStr, sizeStr, cloid, trigger, grouping)
 	}
 
-	orderType := models.OrderTypeLimit
-	if t, ok := orderMap["t"].(map[string]interface{}); ok {
-		if _, isMarket := t["market"]; isMarket {
-			orderType = models.OrderTypeMarket
+	if !validSize(sizeStr, asset.SzDecimals) {
+		return gin.H{"error": "Order has invalid size."}
+	}
+	price, err := strconv.ParseFloat(priceStr, 64)
+	if err != nil || price <= 0 {
+		return gin.H{"error": "Order has invalid price."}
+	}
+	quantity, _ := strconv.ParseFloat(sizeStr, 64)
+
+	// Market orders are sent as aggressive IOC limits; IOC and ALO limits are checked against
+	// the best quote by the trading service and GTC limits rest on the book
+	tif := "Gtc"
+	if limit, ok := orderType["limit"].(map[string]interface{}); ok {
+		if t, ok := limit["tif"].(string); ok {
+			tif = t
 		}
 	}
+	modelType := models.OrderTypeLimit
+	timeInForce := models.TimeInForceGTC
+	if _, isMarket := orderType["market"]; isMarket {
+		modelType = models.OrderTypeMarket
+	} else if tif == "Ioc" {
+		timeInForce = models.TimeInForceIOC
+	} else if tif == "Alo" {
+		timeInForce = models.TimeInForceGTX
+	}
 
 	var order *models.Order
-	var err error
-
-	if !reduceOnly {
-		openReq := &service.OpenPositionRequest{
-			AccountID: account.ID,
-			Symbol:    symbol,
-			Side:      posSide,
-			Quantity:  quantity,
-			OrderType: orderType,
-			Price:     price,
+	if reduceOnly {
+		// Reduce-only buys close shorts, reduce-only sells close longs
+		side := models.PositionSideLong
+		if isBuy {
+			side = models.PositionSideShort
 		}
-		order, _, err = h.tradingService.OpenPosition(openReq, models.ExchangeHyperliquid)
+		order, _, err = h.tradingService.ClosePosition(&service.ClosePositionRequest{
+			AccountID:     account.ID,
+			Symbol:        asset.Symbol(),
+			Side:          side,
+			Quantity:      &quantity,
+			OrderType:     modelType,
+			Price:         price,
+			ReduceOnly:    true,
+			ClientOrderID: cloid,
+			TimeInForce:   timeInForce,
+		}, models.ExchangeHyperliquid)
 	} else {
-		closeReq := &service.ClosePositionRequest{
-			AccountID: account.ID,
-			Symbol:    symbol,
-			Side:      posSide,
-			Quantity:  &quantity,
-			OrderType: orderType,
-			Price:     price,
+		side := models.PositionSideShort
+		if isBuy {
+			side = models.PositionSideLong
 		}
-		order, _, err = h.tradingService.ClosePosition(closeReq, models.ExchangeHyperliquid)
+		order, _, err = h.tradingService.OpenPosition(&service.OpenPositionRequest{
+			AccountID:     account.ID,
+			Symbol:        asset.Symbol(),
+			Side:          side,
+			Quantity:      quantity,
+			OrderType:     modelType,
+			Price:         price,
+			ClientOrderID: cloid,
+			TimeInForce:   timeInForce,
+		}, models.ExchangeHyperliquid)
+	}
+	if err != nil {
+		return gin.H{"error": orderErrorMessage(err, asset.Index)}
+	}
+
+	return orderStatus(order, cloid)
+}
+
+// placeTriggerOrder places a TP/SL trigger order that closes the position it is attached to
+// With positionTpsl grouping a zero size closes the whole position
+func (h *Handler) placeTriggerOrder(account *models.Account, asset *service.HyperliquidAsset, isBuy bool, priceStr, sizeStr, cloid string, trigger map[string]interface{}, grouping string) gin.H {
+	triggerPxStr, _ := trigger["triggerPx"].(string)
+	triggerPx, err := strconv.ParseFloat(triggerPxStr, 64)
+	if err != nil || triggerPx <= 0 {
+		return gin.H{"error": "Order has invalid trigger price."}
+	}
+
+	closeAll := false
+	var quantity float64
+	if size, err := strconv.ParseFloat(sizeStr, 64); err == nil && size == 0 && grouping == "positionTpsl" {
+		closeAll = true
+	} else if !validSize(sizeStr, asset.SzDecimals) {
+		return gin.H{"error": "Order has invalid size."}
+	} else {
+		quantity = size
+	}
+
+	orderType := models.OrderTypeStopMarket
+	if tpsl, _ := trigger["tpsl"].(string); tpsl == "tp" {
+		orderType = models.OrderTypeTakeProfit
+	}
+
+	var price float64
+	if isMarket, _ := trigger["isMarket"].(bool); !isMarket {
+		price, _ = strconv.ParseFloat(priceStr, 64)
 	}
 
+	// A buy trigger closes a short, a sell trigger closes a long
+	side := models.PositionSideLong
+	if isBuy {
+		side = models.PositionSideShort
+	}
+
+	order, err := h.tradingService.CreateConditionalOrder(&service.ConditionalOrderRequest{
+		AccountID:     account.ID,
+		Symbol:        asset.Symbol(),
+		Side:          side,
+		Quantity:      quantity,
+		OrderType:     orderType,
+		StopPrice:     triggerPx,
+		Price:         price,
+		ClosePosition: closeAll,
+		ReduceOnly:    true,
+		ClientOrderID: cloid,
+		// Hyperliquid trigger orders trigger on the mark price
+		TriggerPriceType: models.TriggerPriceMark,
+	}, models.ExchangeHyperliquid)
 	if err != nil {
-		h.handleError(c, err)
+		return gin.H{"error": orderErrorMessage(err, asset.Index)}
+	}
+
+	return orderStatus(order, cloid)
+}
+
+// CancelOrders handles POST /exchange (action: cancel)
+func (h *Handler) CancelOrders(c *gin.Context, req map[string]interface{}) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	cancels, ok := req["cancels"].([]interface{})
+	if !ok || len(cancels) == 0 {
+		h.errorResponse(c, "Invalid cancels")
+		return
+	}
+
+	statuses := make([]interface{}, 0, len(cancels))
+	for _, raw := range cancels {
+		fields, _ := raw.(map[string]interface{})
+		oid, _ := fields["o"].(float64)
+		statuses = append(statuses, h.cancelOrder(account, fields["a"], func() (*models.Order, error) {
+			return h.tradingService.GetOrderStatus(account.ID, uint(oid))
+		}))
+	}
+
+	h.cancelResponse(c, statuses)
+}
+
+// CancelOrdersByCloid handles POST /exchange (action: cancelByCloid)
+func (h *Handler) CancelOrdersByCloid(c *gin.Context, req map[string]interface{}) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	cancels, ok := req["cancels"].([]interface{})
+	if !ok || len(cancels) == 0 {
+		h.errorResponse(c, "Invalid cancels")
+		return
+	}
+
+	statuses := make([]interface{}, 0, len(cancels))
+	for _, raw := range cancels {
+		fields, _ := raw.(map[string]interface{})
+		cloid, _ := fields["cloid"].(string)
+		statuses = append(statuses, h.cancelOrder(account, fields["asset"], func() (*models.Order, error) {
+			return h.tradingService.GetOrderByClientID(account.ID, cloid)
+		}))
+	}
+
+	h.cancelResponse(c, statuses)
+}
+
+// cancelOrder cancels the order returned by lookup if it is open and belongs to the asset
+func (h *Handler) cancelOrder(account *models.Account, rawAsset interface{}, lookup func() (*models.Order, error)) interface{} {
+	asset, err := h.resolveAsset(rawAsset)
+	if err != nil {
+		return gin.H{"error": fmt.Sprintf("Unknown asset %v", rawAsset)}
+	}
+
+	notFound := gin.H{"error": fmt.Sprintf("Order was never placed, already canceled, or filled. asset=%d", asset.Index)}
+	order, err := lookup()
+	if err != nil || order.Symbol != asset.Symbol() {
+		return notFound
+	}
+	if _, err := h.tradingService.CancelOrder(account.ID, order.ID); err != nil {
+		return notFound
+	}
+	return "success"
+}
+
+// ModifyOrder handles POST /exchange (action: modify)
+func (h *Handler) ModifyOrder(c *gin.Context, req map[string]interface{}) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	status := h.modifyOrder(account, req)
+	if msg, failed := status["error"].(string); failed {
+		h.errorResponse(c, msg)
 		return
 	}
 
 	c.JSON(200, gin.H{
 		"status": "ok",
 		"response": gin.H{
-			"type": "order",
-			"data": gin.H{
-				"statuses": []gin.H{
-					{
-						"resting": gin.H{
-							"oid": order.ID,
-						},
-					},
-				},
-			},
+			"type": "default",
 		},
 	})
 }
 
-// PlaceTpSl handles POST /exchange (action: order with tpsl)
-func (h *Handler) PlaceTpSl(c *gin.Context, req map[string]interface{}) {
+// BatchModifyOrders handles POST /exchange (action: batchModify)
+func (h *Handler) BatchModifyOrders(c *gin.Context, req map[string]interface{}) {
 	account := middleware.GetAccount(c)
 	if account == nil {
 		c.JSON(401, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	orders, ok := req["orders"].([]interface{})
-	if !ok || len(orders) == 0 {
-		c.JSON(400, gin.H{"error": "Invalid orders"})
+	modifies, ok := req["modifies"].([]interface{})
+	if !ok || len(modifies) == 0 {
+		h.errorResponse(c, "Invalid modifies")
 		return
 	}
 
-	orderMap := orders[0].(map[string]interface{})
+	statuses := make([]interface{}, 0, len(modifies))
+	for _, raw := range modifies {
+		fields, _ := raw.(map[string]interface{})
+		statuses = append(statuses, h.modifyOrder(account, fields))
+	}
 
-	a, _ := orderMap["a"].(float64)
-	symbol := assetIndexToSymbol(int(a))
+	h.statusesResponse(c, statuses)
+}
 
-	isBuy, _ := orderMap["b"].(bool)
-	sizeStr, _ := orderMap["s"].(string)
-	quantity, _ := strconv.ParseFloat(sizeStr, 64)
+// modifyOrder replaces the open order identified by oid (order ID or cloid) with a new order wire
+// The original is canceled first so the replacement may keep its cloid, and reopened if the replacement is rejected
+func (h *Handler) modifyOrder(account *models.Account, fields map[string]interface{}) gin.H {
+	var existing *models.Order
+	var err error
+	switch oid := fields["oid"].(type) {
+	case float64:
+		existing, err = h.tradingService.GetOrderStatus(account.ID, uint(oid))
+	case string:
+		existing, err = h.tradingService.GetOrderByClientID(account.ID, oid)
+	default:
+		err = service.ErrOrderNotFound
+	}
+	if err != nil || !existing.IsPending() {
+		return gin.H{"error": "Cannot modify canceled or filled order"}
+	}
 
-	var posSide models.PositionSide
-	if isBuy {
-		posSide = models.PositionSideLong
+	var status gin.H
+	err = h.tradingService.ReplaceOrder(account.ID, existing.ID, func() error {
+		status = h.placeOrder(account, fields["order"], "na")
+		if msg, failed := status["error"].(string); failed {
+			return errors.New(msg)
+		}
+		return nil
+	})
+	if status != nil {
+		return status
+	}
+	if err == service.ErrOrderNotOpen {
+		return gin.H{"error": "Cannot modify canceled or filled order"}
+	}
+	return gin.H{"error": err.Error()}
+}
+
+// ScheduleCancel handles POST /exchange (action: scheduleCancel)
+// Setting a time arms the dead man's switch that cancels all open orders; omitting it disarms the switch
+func (h *Handler) ScheduleCancel(c *gin.Context, req map[string]interface{}) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	at, ok := req["time"].(float64)
+	if !ok {
+		h.cancelScheduler.clear(account.ID)
 	} else {
-		posSide = models.PositionSideShort
-	}
-
-	// Determine TP or SL based on trigger
-	var orderType models.OrderType
-	var triggerPrice float64
-
-	if t, ok := orderMap["t"].(map[string]interface{}); ok {
-		if trigger, ok := t["trigger"].(map[string]interface{}); ok {
-			if tp, ok := trigger["triggerPx"].(string); ok {
-				triggerPrice, _ = strconv.ParseFloat(tp, 64)
-			}
-			if isMarket, _ := trigger["isMarket"].(bool); isMarket {
-				// Check if it's TP or SL based on direction
-				if tpsl, ok := trigger["tpsl"].(string); ok {
-					if tpsl == "tp" {
-						orderType = models.OrderTypeTakeProfit
-					} else {
-						orderType = models.OrderTypeStopMarket
-					}
-				}
-			}
+		when := time.UnixMilli(int64(at))
+		if when.Before(time.Now().Add(minScheduleCancelDelay)) {
+			h.errorResponse(c, "Scheduled cancel time too early, must be at least 5 seconds after current time")
+			return
 		}
+		h.cancelScheduler.schedule(account.ID, when)
 	}
 
-	closeReq := &service.ClosePositionRequest{
-		AccountID:  account.ID,
-		Symbol:     symbol,
-		Side:       posSide,
-		Quantity:   &quantity,
-		OrderType:  orderType,
-		StopPrice:  triggerPrice,
-		ReduceOnly: true,
+	c.JSON(200, gin.H{
+		"status": "ok",
+		"response": gin.H{
+			"type": "default",
+		},
+	})
+}
+
+// SetLeverage handles POST /exchange (action: updateLeverage)
+func (h *Handler) SetLeverage(c *gin.Context, req map[string]interface{}) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return
 	}
 
-	order, _, err := h.tradingService.ClosePosition(closeReq, models.ExchangeHyperliquid)
+	leverage, _ := req["leverage"].(float64)
+
+	asset, err := h.resolveAsset(req["asset"])
 	if err != nil {
-		h.handleError(c, err)
+		h.errorResponse(c, fmt.Sprintf("Unknown asset %v", req["asset"]))
+		return
+	}
+	if leverage < 1 || (asset.MaxLeverage > 0 && int(leverage) > asset.MaxLeverage) {
+		h.errorResponse(c, "Invalid leverage value")
+		return
+	}
+
+	if isCross, ok := req["isCross"].(bool); ok {
+		marginMode := models.MarginModeIsolated
+		if isCross {
+			marginMode = models.MarginModeCross
+		}
+		err := h.tradingService.SetMarginMode(account.ID, asset.Symbol(), marginMode)
+		if err == service.ErrPositionExists {
+			h.errorResponse(c, "Cannot switch leverage type with open position.")
+			return
+		}
+		if err != nil && err != service.ErrSettingNotChanged {
+			h.errorResponse(c, err.Error())
+			return
+		}
+	}
+
+	if err := h.tradingService.SetLeverage(account.ID, asset.Symbol(), int(leverage)); err != nil {
+		h.errorResponse(c, err.Error())
 		return
 	}
 
 	c.JSON(200, gin.H{
 		"status": "ok",
 		"response": gin.H{
-			"type": "order",
-			"data": gin.H{
-				"statuses": []gin.H{
-					{
-						"resting": gin.H{
-							"oid": order.ID,
-						},
-					},
-				},
-			},
+			"type": "default",
 		},
 	})
 }
 
-// CancelOrder handles POST /exchange (action: cancel)
-func (h *Handler) CancelOrder(c *gin.Context) {
+// UpdateIsolatedMargin handles POST /exchange (action: updateIsolatedMargin)
+// ntli is the signed USD amount scaled by 1e6; positive adds margin, negative removes it
+func (h *Handler) UpdateIsolatedMargin(c *gin.Context, req map[string]interface{}) {
 	account := middleware.GetAccount(c)
 	if account == nil {
 		c.JSON(401, gin.H{"error": "Unauthorized"})
 		return
 	}
 
+	asset, err := h.resolveAsset(req["asset"])
+	if err != nil {
+		h.errorResponse(c, fmt.Sprintf("Unknown asset %v", req["asset"]))
+		return
+	}
+
+	isBuy, _ := req["isBuy"].(bool)
+	ntli, _ := req["ntli"].(float64)
+	side := models.PositionSideShort
+	if isBuy {
+		side = models.PositionSideLong
+	}
+
+	if _, err := h.tradingService.AdjustIsolatedMargin(account.ID, asset.Symbol(), side, ntli/1e6); err != nil {
+		switch err {
+		case service.ErrPositionNotFound:
+			h.errorResponse(c, fmt.Sprintf("No position to update margin for. asset=%d", asset.Index))
+		case service.ErrNotIsolated:
+			h.errorResponse(c, "Cannot update margin for a cross margin position.")
+		case service.ErrInsufficientBalance:
+			h.errorResponse(c, "Insufficient balance to add margin.")
+		case service.ErrInsufficientMargin:
+			h.errorResponse(c, "Insufficient margin to remove.")
+		default:
+			h.errorResponse(c, err.Error())
+		}
+		return
+	}
+
 	c.JSON(200, gin.H{
 		"status": "ok",
 		"response": gin.H{
-			"type": "cancel",
-			"data": gin.H{
-				"statuses": []string{"success"},
-			},
+			"type": "default",
 		},
 	})
 }
 
-// SetLeverage handles POST /exchange (action: updateLeverage)
-func (h *Handler) SetLeverage(c *gin.Context, req map[string]interface{}) {
+// ApproveAgent handles POST /exchange (action: approveAgent)
+// The middleware has already verified the action was signed by the account wallet
+func (h *Handler) ApproveAgent(c *gin.Context, req map[string]interface{}) {
 	account := middleware.GetAccount(c)
 	if account == nil {
 		c.JSON(401, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	asset, _ := req["asset"].(float64)
-	leverage, _ := req["leverage"].(float64)
-
-	symbol := assetIndexToSymbol(int(asset))
+	agentAddress, _ := req["agentAddress"].(string)
+	agentName, _ := req["agentName"].(string)
+	if _, err := ethsig.AddressBytes(agentAddress); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid agent address"})
+		return
+	}
+	if strings.EqualFold(agentAddress, account.APIKey) {
+		c.JSON(400, gin.H{"error": "Agent address cannot be the user address"})
+		return
+	}
 
-	if err := h.tradingService.SetLeverage(account.ID, symbol, int(leverage)); err != nil {
+	if _, err := h.accountService.ApproveAgent(account.ID, agentAddress, agentName); err != nil {
 		c.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
@@ -331,7 +602,7 @@ func (h *Handler) SetLeverage(c *gin.Context, req map[string]interface{}) {
 	c.JSON(200, gin.H{
 		"status": "ok",
 		"response": gin.H{
-			"type": "updateLeverage",
+			"type": "default",
 		},
 	})
 }
@@ -359,15 +630,16 @@ func (h *Handler) GetMeta(c *gin.Context) {
 		}
 	}
 
-	c.JSON(200, gin.H{
-		"universe": []gin.H{
-			{"name": "BTC", "szDecimals": 5},
-			{"name": "ETH", "szDecimals": 4},
-			{"name": "SOL", "szDecimals": 2},
-			{"name": "DOGE", "szDecimals": 0},
-			{"name": "XRP", "szDecimals": 1},
-		},
-	})
+	universe := make([]gin.H, 0, len(service.DefaultHyperliquidUniverse))
+	for _, asset := range service.DefaultHyperliquidUniverse {
+		universe = append(universe, gin.H{
+			"name":        asset.Name,
+			"szDecimals":  asset.SzDecimals,
+			"maxLeverage": asset.MaxLeverage,
+		})
+	}
+
+	c.JSON(200, gin.H{"universe": universe})
 }
 
 // InfoHandler handles POST /info route
@@ -386,11 +658,29 @@ func (h *Handler) InfoHandler(c *gin.Context) {
 	case "allMids":
 		h.GetAllMids(c)
 	case "clearinghouseState":
-		h.GetUserState(c)
+		h.GetUserState(c, user)
 	case "meta":
 		h.GetMeta(c)
+	case "metaAndAssetCtxs":
+		h.GetMetaAndAssetCtxs(c)
 	case "openOrders":
 		h.GetOpenOrders(c, user)
+	case "userFills":
+		h.GetUserFills(c, user, time.Time{}, time.Time{})
+	case "userFillsByTime":
+		h.GetUserFills(c, user, infoTime(req["startTime"]), infoTime(req["endTime"]))
+	case "orderStatus":
+		h.GetOrderStatus(c, user, req["oid"])
+	case "historicalOrders":
+		h.GetHistoricalOrders(c, user)
+	case "userFunding":
+		h.GetUserFunding(c, user, infoTime(req["startTime"]), infoTime(req["endTime"]))
+	case "l2Book":
+		coin, _ := req["coin"].(string)
+		h.GetL2Book(c, coin)
+	case "candleSnapshot":
+		candleReq, _ := req["req"].(map[string]interface{})
+		h.GetCandleSnapshot(c, candleReq)
 	default:
 		c.JSON(400, gin.H{"error": "Unknown info type"})
 	}
@@ -405,26 +695,33 @@ func (h *Handler) ExchangeHandler(c *gin.Context) {
 		return
 	}
 
-	action, _ := req["action"].(string)
+	// Signed requests carry an action object; unsigned debug requests may inline the action fields
+	action := req
+	actionType, _ := req["action"].(string)
+	if nested, ok := req["action"].(map[string]interface{}); ok {
+		action = nested
+		actionType, _ = nested["type"].(string)
+	}
 
-	switch action {
+	switch actionType {
 	case "order":
-		// Check if it has trigger (TP/SL order)
-		if orders, ok := req["orders"].([]interface{}); ok && len(orders) > 0 {
-			if orderMap, ok := orders[0].(map[string]interface{}); ok {
-				if t, ok := orderMap["t"].(map[string]interface{}); ok {
-					if _, hasTrigger := t["trigger"]; hasTrigger {
-						h.PlaceTpSl(c, req)
-						return
-					}
-				}
-			}
-		}
-		h.PlaceOrder(c, req)
+		h.PlaceOrders(c, action)
 	case "cancel":
-		h.CancelOrder(c)
+		h.CancelOrders(c, action)
+	case "cancelByCloid":
+		h.CancelOrdersByCloid(c, action)
+	case "modify":
+		h.ModifyOrder(c, action)
+	case "batchModify":
+		h.BatchModifyOrders(c, action)
+	case "scheduleCancel":
+		h.ScheduleCancel(c, action)
 	case "updateLeverage":
-		h.SetLeverage(c, req)
+		h.SetLeverage(c, action)
+	case "updateIsolatedMargin":
+		h.UpdateIsolatedMargin(c, action)
+	case "approveAgent":
+		h.ApproveAgent(c, action)
 	default:
 		c.JSON(400, gin.H{"error": "Unknown action"})
 	}
@@ -443,34 +740,119 @@ func convertSymbol(symbol string) string {
 	return symbol
 }
 
-func assetIndexToSymbol(index int) string {
-	// HL uses asset indices, convert to symbol
-	assets := []string{"BTCUSDT", "ETHUSDT", "SOLUSDT", "DOGEUSDT", "XRPUSDT"}
-	if index >= 0 && index < len(assets) {
-		return assets[index]
+// resolveAsset maps the asset index of an action to its meta universe entry
+func (h *Handler) resolveAsset(raw interface{}) (*service.HyperliquidAsset, error) {
+	index, ok := raw.(float64)
+	if !ok || index != math.Trunc(index) {
+		return nil, service.ErrUnknownAsset
+	}
+
+	if h.exchangeInfoService == nil {
+		if int(index) < 0 || int(index) >= len(service.DefaultHyperliquidUniverse) {
+			return nil, service.ErrUnknownAsset
+		}
+		return &service.DefaultHyperliquidUniverse[int(index)], nil
+	}
+	return h.exchangeInfoService.GetHyperliquidAsset(int(index))
+}
+
+// validSize reports whether a size string is positive and has at most szDecimals decimals
+func validSize(size string, szDecimals int) bool {
+	value, err := strconv.ParseFloat(size, 64)
+	if err != nil || value <= 0 {
+		return false
+	}
+	if dot := strings.IndexByte(size, '.'); dot >= 0 {
+		decimals := strings.TrimRight(size[dot+1:], "0")
+		return len(decimals) <= szDecimals
 	}
-	return "BTCUSDT"
+	return true
 }
 
-func (h *Handler) handleError(c *gin.Context, err error) {
+// errorResponse writes a Hyperliquid action-level error ({"status": "err"})
+func (h *Handler) errorResponse(c *gin.Context, msg string) {
+	c.JSON(200, gin.H{
+		"status":   "err",
+		"response": msg,
+	})
+}
+
+// statusesResponse writes an ok order response with one status per order
+func (h *Handler) statusesResponse(c *gin.Context, statuses []interface{}) {
+	c.JSON(200, gin.H{
+		"status": "ok",
+		"response": gin.H{
+			"type": "order",
+			"data": gin.H{
+				"statuses": statuses,
+			},
+		},
+	})
+}
+
+// cancelResponse writes an ok cancel response with one status per cancel
+func (h *Handler) cancelResponse(c *gin.Context, statuses []interface{}) {
+	c.JSON(200, gin.H{
+		"status": "ok",
+		"response": gin.H{
+			"type": "cancel",
+			"data": gin.H{
+				"statuses": statuses,
+			},
+		},
+	})
+}
+
+// orderStatus formats a placed order as a filled or resting status
+func orderStatus(order *models.Order, cloid string) gin.H {
+	if order.Status == models.OrderStatusFilled {
+		filled := gin.H{
+			"totalSz": strconv.FormatFloat(order.FilledQty, 'f', 8, 64),
+			"avgPx":   strconv.FormatFloat(order.AvgPrice, 'f', 8, 64),
+			"oid":     order.ID,
+		}
+		if cloid != "" {
+			filled["cloid"] = cloid
+		}
+		return gin.H{"filled": filled}
+	}
+
+	resting := gin.H{"oid": order.ID}
+	if cloid != "" {
+		resting["cloid"] = cloid
+	}
+	return gin.H{"resting": resting}
+}
+
+// orderErrorMessage maps a trading error to Hyperliquid's order rejection message
+func orderErrorMessage(err error, asset int) string {
 	switch err {
 	case service.ErrInsufficientBalance:
-		c.JSON(400, gin.H{"error": "Insufficient margin"})
-	case service.ErrInvalidSymbol:
-		c.JSON(400, gin.H{"error": "Invalid asset"})
-	case service.ErrInvalidQuantity:
-		c.JSON(400, gin.H{"error": "Invalid size"})
+		return fmt.Sprintf("Insufficient margin to place order. asset=%d", asset)
 	case service.ErrNoOpenPosition:
-		c.JSON(400, gin.H{"error": "No position to reduce"})
+		return fmt.Sprintf("Reduce only order would increase position. asset=%d", asset)
+	case service.ErrInvalidQuantity:
+		return "Order has invalid size."
+	case service.ErrInvalidPrice:
+		return "Order has invalid price."
+	case service.ErrInvalidSymbol:
+		return fmt.Sprintf("Unknown asset %d", asset)
+	case service.ErrIOCNotFilled:
+		return fmt.Sprintf("Order could not immediately match against any resting orders. asset=%d", asset)
+	case service.ErrPostOnlyWouldTake:
+		return fmt.Sprintf("Post only order would have immediately matched. asset=%d", asset)
+	case service.ErrDuplicateClientID:
+		return fmt.Sprintf("Duplicate cloid. asset=%d", asset)
 	default:
-		c.JSON(500, gin.H{"error": err.Error()})
+		return err.Error()
 	}
 }
 
 // RegisterRoutes registers Hyperliquid-compatible routes
 func (h *Handler) RegisterRoutes(router *gin.Engine, authMiddleware gin.HandlerFunc) {
-	// Public info endpoint
+	// Public info endpoint and market-data WebSocket
 	router.POST("/info", h.InfoHandler)
+	router.GET("/ws", h.WebSocket)
 
 	// Private exchange endpoint (all trading operations go through here)
 	exchange := router.Group("/exchange")
@@ -0,0 +1,417 @@
+package hyperliquid
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/ccxt-simulator/internal/repository"
+	"github.com/ccxt-simulator/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// infoHistoryLimit caps fills and historical orders, like the real /info endpoint
+	infoHistoryLimit = 2000
+	// zeroHash stands in for the L1 transaction hash of simulated fills and funding payments
+	zeroHash = "0x0000000000000000000000000000000000000000000000000000000000000000"
+)
+
+// candleIntervals maps Hyperliquid candle intervals to their durations
+var candleIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"3m":  3 * time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"2h":  2 * time.Hour,
+	"4h":  4 * time.Hour,
+	"8h":  8 * time.Hour,
+	"12h": 12 * time.Hour,
+	"1d":  24 * time.Hour,
+	"3d":  3 * 24 * time.Hour,
+	"1w":  7 * 24 * time.Hour,
+}
+
+// infoAccount resolves the "user" address of an /info request to its account
+// Writes an error response and returns nil when the address is unknown
+func (h *Handler) infoAccount(c *gin.Context, user string) *models.Account {
+	if user == "" {
+		c.JSON(422, gin.H{"error": "Missing user"})
+		return nil
+	}
+
+	account, _, err := h.accountService.GetAccountByWallet(user)
+	if err == service.ErrWalletNotFound {
+		c.JSON(422, gin.H{"error": "Unknown user"})
+		return nil
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return nil
+	}
+	return account
+}
+
+// infoTime converts an optional millisecond timestamp field to a time (zero when absent)
+func infoTime(raw interface{}) time.Time {
+	ms, ok := raw.(float64)
+	if !ok || ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(int64(ms))
+}
+
+// GetUserFills handles POST /info (type: userFills / userFillsByTime)
+// Zero start/end times return the most recent fills
+func (h *Handler) GetUserFills(c *gin.Context, user string, start, end time.Time) {
+	account := h.infoAccount(c, user)
+	if account == nil {
+		return
+	}
+
+	trades, err := h.tradingService.GetTradeHistory(account.ID, repository.HistoryQuery{
+		Start: start,
+		End:   end,
+		Limit: infoHistoryLimit,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	fills := make([]gin.H, 0, len(trades))
+	for _, trade := range trades {
+		fill := gin.H{
+			"coin":      convertSymbol(trade.Symbol),
+			"px":        strconv.FormatFloat(trade.Price, 'f', 8, 64),
+			"sz":        strconv.FormatFloat(trade.Quantity, 'f', 8, 64),
+			"side":      formatSide(trade.Side),
+			"time":      trade.ExecutedAt.UnixMilli(),
+			"dir":       fillDirection(&trade.Order),
+			"closedPnl": strconv.FormatFloat(trade.RealizedPnL, 'f', 8, 64),
+			"hash":      zeroHash,
+			"oid":       trade.OrderID,
+			"crossed":   !trade.IsMaker,
+			"fee":       strconv.FormatFloat(trade.Fee, 'f', 8, 64),
+			"tid":       trade.ID,
+			"feeToken":  "USDC",
+		}
+		if trade.Order.ClientOrderID != "" {
+			fill["cloid"] = trade.Order.ClientOrderID
+		}
+		if trade.Order.IsLiquidation() {
+			fill["liquidation"] = gin.H{
+				"markPx": strconv.FormatFloat(trade.Price, 'f', 8, 64),
+				"method": "market",
+			}
+		}
+		fills = append(fills, fill)
+	}
+
+	c.JSON(200, fills)
+}
+
+// GetOrderStatus handles POST /info (type: orderStatus)
+// oid may be an order ID or a client order ID
+func (h *Handler) GetOrderStatus(c *gin.Context, user string, oid interface{}) {
+	account := h.infoAccount(c, user)
+	if account == nil {
+		return
+	}
+
+	var order *models.Order
+	var err error
+	switch id := oid.(type) {
+	case float64:
+		order, err = h.tradingService.GetOrderStatus(account.ID, uint(id))
+	case string:
+		order, err = h.tradingService.GetOrderByClientID(account.ID, id)
+	default:
+		err = service.ErrOrderNotFound
+	}
+	if err != nil {
+		c.JSON(200, gin.H{"status": "unknownOid"})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"status": "order",
+		"order":  formatOrderWithStatus(order),
+	})
+}
+
+// GetHistoricalOrders handles POST /info (type: historicalOrders)
+func (h *Handler) GetHistoricalOrders(c *gin.Context, user string) {
+	account := h.infoAccount(c, user)
+	if account == nil {
+		return
+	}
+
+	orders, err := h.tradingService.GetOrderHistory(account.ID, "", infoHistoryLimit)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := make([]gin.H, 0, len(orders))
+	for i := range orders {
+		result = append(result, formatOrderWithStatus(&orders[i]))
+	}
+
+	c.JSON(200, result)
+}
+
+// GetUserFunding handles POST /info (type: userFunding)
+func (h *Handler) GetUserFunding(c *gin.Context, user string, start, end time.Time) {
+	account := h.infoAccount(c, user)
+	if account == nil {
+		return
+	}
+
+	fees, err := h.fundingService.GetFundingFees(account.ID, "", start, end, infoHistoryLimit)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := make([]gin.H, 0, len(fees))
+	for _, fee := range fees {
+		szi := fee.Quantity
+		if fee.Side == models.PositionSideShort {
+			szi = -szi
+		}
+		result = append(result, gin.H{
+			"time": fee.FundingTime.UnixMilli(),
+			"hash": zeroHash,
+			"delta": gin.H{
+				"type":        "funding",
+				"coin":        convertSymbol(fee.Symbol),
+				"usdc":        strconv.FormatFloat(fee.Amount, 'f', 8, 64),
+				"szi":         strconv.FormatFloat(szi, 'f', 8, 64),
+				"fundingRate": strconv.FormatFloat(fee.FundingRate, 'f', 8, 64),
+			},
+		})
+	}
+
+	c.JSON(200, result)
+}
+
+// GetMetaAndAssetCtxs handles POST /info (type: metaAndAssetCtxs)
+// Asset contexts come from the price cache; open interest and volume are not simulated
+func (h *Handler) GetMetaAndAssetCtxs(c *gin.Context) {
+	universe := service.DefaultHyperliquidUniverse
+	if h.exchangeInfoService != nil {
+		universe = h.exchangeInfoService.GetHyperliquidUniverse()
+	}
+
+	assets := make([]gin.H, 0, len(universe))
+	ctxs := make([]gin.H, 0, len(universe))
+	for i := range universe {
+		asset := &universe[i]
+		assets = append(assets, gin.H{
+			"name":        asset.Name,
+			"szDecimals":  asset.SzDecimals,
+			"maxLeverage": asset.MaxLeverage,
+		})
+
+		ctx := gin.H{
+			"funding":      "0",
+			"openInterest": "0",
+			"dayNtlVlm":    "0",
+			"premium":      "0",
+			"prevDayPx":    nil,
+			"markPx":       nil,
+			"oraclePx":     nil,
+			"midPx":        nil,
+			"impactPxs":    nil,
+		}
+		if update, err := h.priceService.GetPriceUpdate("hyperliquid", asset.Symbol()); err == nil {
+			price := strconv.FormatFloat(update.Price, 'f', 8, 64)
+			ctx["markPx"] = price
+			ctx["oraclePx"] = price
+			ctx["prevDayPx"] = price
+			ctx["midPx"] = strconv.FormatFloat((update.BestBid()+update.BestAsk())/2, 'f', 8, 64)
+			ctx["impactPxs"] = []string{
+				strconv.FormatFloat(update.BestBid(), 'f', 8, 64),
+				strconv.FormatFloat(update.BestAsk(), 'f', 8, 64),
+			}
+		}
+		if rate, err := h.priceService.GetFundingRate("hyperliquid", asset.Symbol()); err == nil {
+			ctx["funding"] = strconv.FormatFloat(rate, 'f', 8, 64)
+		}
+		ctxs = append(ctxs, ctx)
+	}
+
+	c.JSON(200, []interface{}{gin.H{"universe": assets}, ctxs})
+}
+
+// GetL2Book handles POST /info (type: l2Book)
+// The book has a single level per side at the cached best bid/ask; depth is not simulated
+func (h *Handler) GetL2Book(c *gin.Context, coin string) {
+	update, err := h.priceService.GetPriceUpdate("hyperliquid", coin)
+	if err != nil {
+		c.JSON(200, nil)
+		return
+	}
+
+	level := func(px float64) []gin.H {
+		return []gin.H{{
+			"px": strconv.FormatFloat(px, 'f', 8, 64),
+			"sz": "0",
+			"n":  0,
+		}}
+	}
+
+	c.JSON(200, gin.H{
+		"coin":   coin,
+		"time":   update.Timestamp,
+		"levels": [][]gin.H{level(update.BestBid()), level(update.BestAsk())},
+	})
+}
+
+// GetCandleSnapshot handles POST /info (type: candleSnapshot)
+// Candles are built from live ticks since startup; volume is not simulated
+func (h *Handler) GetCandleSnapshot(c *gin.Context, req map[string]interface{}) {
+	coin, _ := req["coin"].(string)
+	interval, _ := req["interval"].(string)
+	startTime, _ := req["startTime"].(float64)
+	endTime, _ := req["endTime"].(float64)
+
+	duration, ok := candleIntervals[interval]
+	if !ok {
+		c.JSON(422, gin.H{"error": fmt.Sprintf("Invalid interval %s", interval)})
+		return
+	}
+
+	var end time.Time
+	if endTime > 0 {
+		end = time.UnixMilli(int64(endTime))
+	}
+	candles := h.priceService.Candles().GetCandles("hyperliquid", coin, duration, time.UnixMilli(int64(startTime)), end)
+
+	result := make([]gin.H, 0, len(candles))
+	for _, candle := range candles {
+		result = append(result, gin.H{
+			"t": candle.OpenTime.UnixMilli(),
+			"T": candle.OpenTime.Add(duration).UnixMilli() - 1,
+			"s": coin,
+			"i": interval,
+			"o": strconv.FormatFloat(candle.Open, 'f', 8, 64),
+			"c": strconv.FormatFloat(candle.Close, 'f', 8, 64),
+			"h": strconv.FormatFloat(candle.High, 'f', 8, 64),
+			"l": strconv.FormatFloat(candle.Low, 'f', 8, 64),
+			"v": "0",
+			"n": candle.Ticks,
+		})
+	}
+
+	c.JSON(200, result)
+}
+
+// formatSide maps an order side to Hyperliquid's B (bid) / A (ask)
+func formatSide(side models.OrderSide) string {
+	if side == models.OrderSideBuy {
+		return "B"
+	}
+	return "A"
+}
+
+// fillDirection describes a fill as Open/Close Long/Short
+func fillDirection(order *models.Order) string {
+	switch {
+	case order.PositionSide == models.PositionSideShort && order.Side == models.OrderSideSell:
+		return "Open Short"
+	case order.PositionSide == models.PositionSideShort:
+		return "Close Short"
+	case order.Side == models.OrderSideSell:
+		return "Close Long"
+	default:
+		return "Open Long"
+	}
+}
+
+// formatOrderWithStatus formats an order in the frontend order shape with its status
+func formatOrderWithStatus(order *models.Order) gin.H {
+	isTrigger := order.IsConditional()
+
+	orderType := "Limit"
+	tif := interface{}("Gtc")
+	triggerCondition := "N/A"
+	switch {
+	case isTrigger:
+		kind := "Stop"
+		if order.Type == models.OrderTypeTakeProfit {
+			kind = "Take Profit"
+		}
+		orderType = kind + " Market"
+		if order.Price > 0 {
+			orderType = kind + " Limit"
+		}
+		tif = nil
+
+		// Stops on longs and take-profits on shorts trigger as the price falls
+		direction := "above"
+		if (order.PositionSide == models.PositionSideLong) != (order.Type == models.OrderTypeTakeProfit) {
+			direction = "below"
+		}
+		triggerCondition = fmt.Sprintf("Price %s %s", direction, strconv.FormatFloat(order.StopPrice, 'f', -1, 64))
+	case order.Type == models.OrderTypeMarket || order.IsLiquidation():
+		orderType = "Market"
+		tif = "Ioc"
+	case order.TimeInForce == models.TimeInForceIOC:
+		tif = "Ioc"
+	case order.TimeInForce == models.TimeInForceGTX:
+		tif = "Alo"
+	}
+
+	var cloid interface{}
+	if order.ClientOrderID != "" {
+		cloid = order.ClientOrderID
+	}
+
+	// Orders expired at placement by their time in force are reported with the rejection reason
+	status := "open"
+	switch order.Status {
+	case models.OrderStatusFilled:
+		status = "filled"
+	case models.OrderStatusCanceled:
+		status = "canceled"
+	case models.OrderStatusExpired:
+		status = "canceled"
+		if order.TimeInForce == models.TimeInForceIOC {
+			status = "iocCancelRejected"
+		} else if order.TimeInForce == models.TimeInForceGTX {
+			status = "badAloPxRejected"
+		}
+	case models.OrderStatusRejected:
+		status = "rejected"
+	case models.OrderStatusTriggered:
+		status = "triggered"
+	}
+
+	return gin.H{
+		"order": gin.H{
+			"coin":             convertSymbol(order.Symbol),
+			"side":             formatSide(order.Side),
+			"limitPx":          strconv.FormatFloat(order.Price, 'f', 8, 64),
+			"sz":               strconv.FormatFloat(order.Quantity-order.FilledQty, 'f', 8, 64),
+			"oid":              order.ID,
+			"timestamp":        order.CreatedAt.UnixMilli(),
+			"triggerCondition": triggerCondition,
+			"isTrigger":        isTrigger,
+			"triggerPx":        strconv.FormatFloat(order.StopPrice, 'f', 8, 64),
+			"children":         []interface{}{},
+			"isPositionTpsl":   isTrigger && order.ClosePosition,
+			"reduceOnly":       order.ReduceOnly,
+			"orderType":        orderType,
+			"origSz":           strconv.FormatFloat(order.Quantity, 'f', 8, 64),
+			"tif":              tif,
+			"cloid":            cloid,
+		},
+		"status":          status,
+		"statusTimestamp": order.UpdatedAt.UnixMilli(),
+	}
+}
@@ -0,0 +1,65 @@
+package hyperliquid
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ccxt-simulator/internal/service"
+)
+
+// minScheduleCancelDelay is how far in the future a scheduled cancel must be
+const minScheduleCancelDelay = 5 * time.Second
+
+// cancelScheduler implements scheduleCancel, the dead man's switch that cancels
+// all open orders of an account at a given time
+type cancelScheduler struct {
+	tradingService *service.TradingService
+
+	mu     sync.Mutex
+	timers map[uint]*time.Timer // account ID -> pending cancel
+}
+
+func newCancelScheduler(tradingService *service.TradingService) *cancelScheduler {
+	return &cancelScheduler{
+		tradingService: tradingService,
+		timers:         make(map[uint]*time.Timer),
+	}
+}
+
+// schedule replaces any pending cancel of the account with one firing at the given time
+func (s *cancelScheduler) schedule(accountID uint, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if timer, ok := s.timers[accountID]; ok {
+		timer.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Until(at), func() {
+		s.mu.Lock()
+		if s.timers[accountID] != timer {
+			s.mu.Unlock()
+			return
+		}
+		delete(s.timers, accountID)
+		s.mu.Unlock()
+
+		if _, err := s.tradingService.CancelAllOrders(accountID, ""); err != nil {
+			log.Printf("Scheduled cancel failed for account %d: %v", accountID, err)
+		}
+	})
+	s.timers[accountID] = timer
+}
+
+// clear removes the pending cancel of the account, if any
+func (s *cancelScheduler) clear(accountID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if timer, ok := s.timers[accountID]; ok {
+		timer.Stop()
+		delete(s.timers, accountID)
+	}
+}
@@ -0,0 +1,149 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsWriteWait = 10 * time.Second
+	// allMidsInterval batches ticks the way the exchange pushes mids once per block
+	allMidsInterval = 500 * time.Millisecond
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// wsRequest is a Hyperliquid WebSocket request
+type wsRequest struct {
+	Method       string          `json:"method"`
+	Subscription json.RawMessage `json:"subscription"`
+}
+
+// wsSubscription identifies a subscription by type
+type wsSubscription struct {
+	Type string `json:"type"`
+}
+
+// wsSession is a single WebSocket connection
+type wsSession struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	allMids bool
+	mu      sync.Mutex
+}
+
+func (s *wsSession) write(v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return s.conn.WriteJSON(v)
+}
+
+func (s *wsSession) writeError(msg string) {
+	s.write(gin.H{"channel": "error", "data": msg})
+}
+
+// WebSocket handles GET /ws
+func (h *Handler) WebSocket(c *gin.Context) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	session := &wsSession{conn: conn}
+
+	ticks := h.priceService.MarketData().Subscribe("hyperliquid")
+	defer h.priceService.MarketData().Unsubscribe("hyperliquid", ticks)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(allMidsInterval)
+		defer ticker.Stop()
+
+		dirty := false
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticks:
+				dirty = true
+			case <-ticker.C:
+				session.mu.Lock()
+				subscribed := session.allMids
+				session.mu.Unlock()
+				if dirty && subscribed {
+					h.pushAllMids(session)
+				}
+				dirty = false
+			}
+		}
+	}()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+
+		var req wsRequest
+		if err := json.Unmarshal(message, &req); err != nil {
+			session.writeError("Invalid request: " + string(message))
+			continue
+		}
+
+		switch req.Method {
+		case "ping":
+			session.write(gin.H{"channel": "pong"})
+
+		case "subscribe", "unsubscribe":
+			var sub wsSubscription
+			if json.Unmarshal(req.Subscription, &sub) != nil || sub.Type != "allMids" {
+				session.writeError("Invalid subscription: " + string(req.Subscription))
+				continue
+			}
+
+			session.mu.Lock()
+			session.allMids = req.Method == "subscribe"
+			session.mu.Unlock()
+
+			session.write(gin.H{
+				"channel": "subscriptionResponse",
+				"data": gin.H{
+					"method":       req.Method,
+					"subscription": req.Subscription,
+				},
+			})
+			if req.Method == "subscribe" {
+				h.pushAllMids(session)
+			}
+
+		default:
+			session.writeError("Unknown method: " + req.Method)
+		}
+	}
+}
+
+// pushAllMids pushes the current mid price of every coin
+func (h *Handler) pushAllMids(session *wsSession) {
+	mids := make(map[string]string)
+	for symbol, price := range h.priceService.GetAllPrices("hyperliquid") {
+		mids[convertSymbol(symbol)] = strconv.FormatFloat(price, 'f', 8, 64)
+	}
+
+	session.write(gin.H{
+		"channel": "allMids",
+		"data":    gin.H{"mids": mids},
+	})
+}
@@ -5,25 +5,33 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ccxt-simulator/internal/exchange"
 	"github.com/ccxt-simulator/internal/middleware"
 	"github.com/ccxt-simulator/internal/models"
+	"github.com/ccxt-simulator/internal/repository"
 	"github.com/ccxt-simulator/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
+// maxBatchOrders is the most orders accepted by batch-orders and cancel-batch-orders
+const maxBatchOrders = 20
+
 // Handler handles OKX-compatible API requests
 type Handler struct {
 	tradingService      *service.TradingService
 	priceService        *service.PriceService
 	exchangeInfoService *service.ExchangeInfoService
+	fundingService      *service.FundingService
+	wsLogin             middleware.OKXLoginFunc
 }
 
 // NewHandler creates a new OKX handler
-func NewHandler(tradingService *service.TradingService, priceService *service.PriceService, exchangeInfoService *service.ExchangeInfoService) *Handler {
+func NewHandler(tradingService *service.TradingService, priceService *service.PriceService, exchangeInfoService *service.ExchangeInfoService, fundingService *service.FundingService) *Handler {
 	return &Handler{
 		tradingService:      tradingService,
 		priceService:        priceService,
 		exchangeInfoService: exchangeInfoService,
+		fundingService:      fundingService,
 	}
 }
 
@@ -74,30 +82,7 @@ func (h *Handler) GetBalance(c *gin.Context) {
 	c.JSON(200, gin.H{
 		"code": "0",
 		"msg":  "",
-		"data": []gin.H{
-			{
-				"totalEq":     strconv.FormatFloat(balance["equity"], 'f', 8, 64),
-				"isoEq":       "0",
-				"adjEq":       strconv.FormatFloat(balance["equity"], 'f', 8, 64),
-				"ordFroz":     "0",
-				"imr":         strconv.FormatFloat(balance["margin"], 'f', 8, 64),
-				"mmr":         "0",
-				"notionalUsd": strconv.FormatFloat(balance["margin"]*10, 'f', 8, 64),
-				"mgnRatio":    "999",
-				"details": []gin.H{
-					{
-						"ccy":       "USDT",
-						"eq":        strconv.FormatFloat(balance["equity"], 'f', 8, 64),
-						"cashBal":   strconv.FormatFloat(balance["balance"], 'f', 8, 64),
-						"availBal":  strconv.FormatFloat(balance["available"], 'f', 8, 64),
-						"frozenBal": strconv.FormatFloat(balance["margin"], 'f', 8, 64),
-						"upl":       strconv.FormatFloat(balance["unrealized_pnl"], 'f', 8, 64),
-						"uplLiab":   "0",
-					},
-				},
-				"uTime": strconv.FormatInt(time.Now().UnixMilli(), 10),
-			},
-		},
+		"data": []gin.H{formatBalance(balance)},
 	})
 }
 
@@ -118,33 +103,10 @@ func (h *Handler) GetPositions(c *gin.Context) {
 
 	data := make([]gin.H, 0)
 	for _, pos := range positions {
-		okxInstId := convertToOKXSymbol(pos.Symbol)
-		if instId != "" && okxInstId != instId {
+		if instId != "" && convertToOKXSymbol(pos.Symbol) != instId {
 			continue
 		}
-
-		posSide := "long"
-		if pos.Side == models.PositionSideShort {
-			posSide = "short"
-		}
-
-		data = append(data, gin.H{
-			"instId":   okxInstId,
-			"instType": "SWAP",
-			"mgnMode":  string(pos.MarginMode),
-			"posId":    strconv.Itoa(int(pos.ID)),
-			"posSide":  posSide,
-			"pos":      strconv.FormatFloat(pos.Quantity, 'f', 8, 64),
-			"avgPx":    strconv.FormatFloat(pos.EntryPrice, 'f', 8, 64),
-			"markPx":   strconv.FormatFloat(pos.MarkPrice, 'f', 8, 64),
-			"upl":      strconv.FormatFloat(pos.UnrealizedPnL, 'f', 8, 64),
-			"uplRatio": strconv.FormatFloat(pos.UnrealizedPnL/pos.Margin, 'f', 8, 64),
-			"lever":    strconv.Itoa(pos.Leverage),
-			"liqPx":    strconv.FormatFloat(pos.LiquidationPrice, 'f', 8, 64),
-			"margin":   strconv.FormatFloat(pos.Margin, 'f', 8, 64),
-			"cTime":    strconv.FormatInt(pos.CreatedAt.UnixMilli(), 10),
-			"uTime":    strconv.FormatInt(pos.UpdatedAt.UnixMilli(), 10),
-		})
+		data = append(data, h.formatPosition(&pos))
 	}
 
 	c.JSON(200, gin.H{
@@ -154,6 +116,19 @@ func (h *Handler) GetPositions(c *gin.Context) {
 	})
 }
 
+// placeOrderRequest is the body of POST /api/v5/trade/order and an element of batch-orders
+type placeOrderRequest struct {
+	InstId     string `json:"instId"`
+	TdMode     string `json:"tdMode"`
+	Side       string `json:"side"`
+	PosSide    string `json:"posSide"`
+	OrdType    string `json:"ordType"`
+	Sz         string `json:"sz"`
+	Px         string `json:"px"`
+	ReduceOnly string `json:"reduceOnly"`
+	ClOrdId    string `json:"clOrdId"`
+}
+
 // CreateOrder handles POST /api/v5/trade/order
 func (h *Handler) CreateOrder(c *gin.Context) {
 	account := middleware.GetAccount(c)
@@ -162,24 +137,79 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		InstId     string `json:"instId"`
-		TdMode     string `json:"tdMode"`
-		Side       string `json:"side"`
-		PosSide    string `json:"posSide"`
-		OrdType    string `json:"ordType"`
-		Sz         string `json:"sz"`
-		Px         string `json:"px"`
-		ReduceOnly string `json:"reduceOnly"`
+	var req placeOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorResponse(c, "50000", err.Error())
+		return
+	}
+
+	order, err := h.placeOrder(account.ID, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
 	}
 
+	c.JSON(200, gin.H{
+		"code": "0",
+		"msg":  "",
+		"data": []gin.H{
+			{
+				"ordId":   strconv.Itoa(int(order.ID)),
+				"clOrdId": order.ClientOrderID,
+				"tag":     "",
+				"sCode":   "0",
+				"sMsg":    "",
+			},
+		},
+	})
+}
+
+// CreateBatchOrders handles POST /api/v5/trade/batch-orders
+// Orders are placed independently and each reports its own sCode/sMsg
+func (h *Handler) CreateBatchOrders(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, "50111", "API key is invalid")
+		return
+	}
+
+	var req []placeOrderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.errorResponse(c, "50000", err.Error())
 		return
 	}
+	if len(req) == 0 || len(req) > maxBatchOrders {
+		h.errorResponse(c, "51004", "The number of orders in a batch must be between 1 and 20")
+		return
+	}
 
+	data := make([]gin.H, 0, len(req))
+	failed := 0
+	for i := range req {
+		order, err := h.placeOrder(account.ID, &req[i])
+		if err != nil {
+			code, msg := errorCode(err)
+			data = append(data, gin.H{"ordId": "", "clOrdId": req[i].ClOrdId, "tag": "", "sCode": code, "sMsg": msg})
+			failed++
+			continue
+		}
+		data = append(data, gin.H{
+			"ordId":   strconv.Itoa(int(order.ID)),
+			"clOrdId": order.ClientOrderID,
+			"tag":     "",
+			"sCode":   "0",
+			"sMsg":    "",
+		})
+	}
+
+	batchResponse(c, data, failed)
+}
+
+// placeOrder opens or reduces a position from an OKX order request
+func (h *Handler) placeOrder(accountID uint, req *placeOrderRequest) (*models.Order, error) {
 	symbol := convertFromOKXSymbol(req.InstId)
-	quantity, _ := strconv.ParseFloat(req.Sz, 64)
+	sz, _ := strconv.ParseFloat(req.Sz, 64)
+	quantity := h.contractSpec(symbol).ToBase(sz)
 	price, _ := strconv.ParseFloat(req.Px, 64)
 
 	var posSide models.PositionSide
@@ -190,9 +220,16 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 	}
 
 	var orderType models.OrderType
+	var timeInForce string
 	switch req.OrdType {
 	case "limit":
 		orderType = models.OrderTypeLimit
+	case "post_only":
+		orderType, timeInForce = models.OrderTypeLimit, models.TimeInForceGTX
+	case "fok":
+		orderType, timeInForce = models.OrderTypeLimit, models.TimeInForceFOK
+	case "ioc":
+		orderType, timeInForce = models.OrderTypeLimit, models.TimeInForceIOC
 	default:
 		orderType = models.OrderTypeMarket
 	}
@@ -203,44 +240,36 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 
 	if !isReduceOnly {
 		openReq := &service.OpenPositionRequest{
-			AccountID: account.ID,
-			Symbol:    symbol,
-			Side:      posSide,
-			Quantity:  quantity,
-			OrderType: orderType,
-			Price:     price,
+			AccountID:     accountID,
+			Symbol:        symbol,
+			Side:          posSide,
+			Quantity:      quantity,
+			OrderType:     orderType,
+			Price:         price,
+			TimeInForce:   timeInForce,
+			ClientOrderID: req.ClOrdId,
 		}
 		order, _, err = h.tradingService.OpenPosition(openReq, models.ExchangeOKX)
 	} else {
 		closeReq := &service.ClosePositionRequest{
-			AccountID: account.ID,
-			Symbol:    symbol,
-			Side:      posSide,
-			Quantity:  &quantity,
-			OrderType: orderType,
-			Price:     price,
+			AccountID:     accountID,
+			Symbol:        symbol,
+			Side:          posSide,
+			Quantity:      &quantity,
+			OrderType:     orderType,
+			Price:         price,
+			TimeInForce:   timeInForce,
+			ClientOrderID: req.ClOrdId,
 		}
 		order, _, err = h.tradingService.ClosePosition(closeReq, models.ExchangeOKX)
 	}
 
-	if err != nil {
-		h.handleError(c, err)
-		return
+	// OKX accepts IOC, FOK and post-only orders that cannot be met and cancels them right away
+	if service.IsTimeInForceExpiry(err) {
+		err = nil
 	}
 
-	c.JSON(200, gin.H{
-		"code": "0",
-		"msg":  "",
-		"data": []gin.H{
-			{
-				"ordId":   strconv.Itoa(int(order.ID)),
-				"clOrdId": order.ClientOrderID,
-				"tag":     "",
-				"sCode":   "0",
-				"sMsg":    "",
-			},
-		},
-	})
+	return order, err
 }
 
 // CreateAlgoOrder handles POST /api/v5/trade/order-algo (SL/TP orders)
@@ -256,13 +285,23 @@ func (h *Handler) CreateAlgoOrder(c *gin.Context) {
 		TdMode      string `json:"tdMode"`
 		Side        string `json:"side"`
 		PosSide     string `json:"posSide"`
-		OrdType     string `json:"ordType"` // conditional
+		OrdType     string `json:"ordType"` // conditional, move_order_stop
 		Sz          string `json:"sz"`
 		TpTriggerPx string `json:"tpTriggerPx"`
 		TpOrdPx     string `json:"tpOrdPx"`
 		SlTriggerPx string `json:"slTriggerPx"`
 		SlOrdPx     string `json:"slOrdPx"`
 		ReduceOnly  string `json:"reduceOnly"`
+		AlgoClOrdId string `json:"algoClOrdId"`
+
+		// last (default), index or mark
+		TpTriggerPxType string `json:"tpTriggerPxType"`
+		SlTriggerPxType string `json:"slTriggerPxType"`
+
+		// move_order_stop (trailing stop) only
+		CallbackRatio  string `json:"callbackRatio"`
+		CallbackSpread string `json:"callbackSpread"`
+		ActivePx       string `json:"activePx"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -271,7 +310,8 @@ func (h *Handler) CreateAlgoOrder(c *gin.Context) {
 	}
 
 	symbol := convertFromOKXSymbol(req.InstId)
-	quantity, _ := strconv.ParseFloat(req.Sz, 64)
+	sz, _ := strconv.ParseFloat(req.Sz, 64)
+	quantity := h.contractSpec(symbol).ToBase(sz)
 
 	var posSide models.PositionSide
 	if req.PosSide == "long" {
@@ -280,28 +320,80 @@ func (h *Handler) CreateAlgoOrder(c *gin.Context) {
 		posSide = models.PositionSideShort
 	}
 
+	if req.OrdType == "move_order_stop" {
+		h.createTrailingStop(c, account.ID, symbol, posSide, quantity, req.CallbackRatio, req.CallbackSpread, req.ActivePx, req.AlgoClOrdId)
+		return
+	}
+
 	var orderType models.OrderType
 	var triggerPrice float64
+	var ordPx, triggerPxType string
 
 	if req.SlTriggerPx != "" {
 		orderType = models.OrderTypeStopMarket
 		triggerPrice, _ = strconv.ParseFloat(req.SlTriggerPx, 64)
+		ordPx, triggerPxType = req.SlOrdPx, req.SlTriggerPxType
 	} else if req.TpTriggerPx != "" {
 		orderType = models.OrderTypeTakeProfit
 		triggerPrice, _ = strconv.ParseFloat(req.TpTriggerPx, 64)
+		ordPx, triggerPxType = req.TpOrdPx, req.TpTriggerPxType
+	}
+
+	// An ordPx of -1 closes at market once triggered; any other price rests a limit order there
+	var price float64
+	if ordPx != "-1" {
+		price, _ = strconv.ParseFloat(ordPx, 64)
+	}
+
+	order, err := h.tradingService.CreateConditionalOrder(&service.ConditionalOrderRequest{
+		AccountID:     account.ID,
+		Symbol:        symbol,
+		Side:          posSide,
+		Quantity:      quantity,
+		OrderType:     orderType,
+		StopPrice:     triggerPrice,
+		Price:         price,
+		ReduceOnly:    true,
+		ClosePosition: req.Sz == "",
+		ClientOrderID: req.AlgoClOrdId,
+		// OKX trigger price types are the lower-case trigger price type names
+		TriggerPriceType: models.TriggerPriceType(strings.ToUpper(triggerPxType)),
+	}, models.ExchangeOKX)
+	if err != nil {
+		h.handleError(c, err)
+		return
 	}
 
-	closeReq := &service.ClosePositionRequest{
-		AccountID:  account.ID,
-		Symbol:     symbol,
-		Side:       posSide,
-		Quantity:   &quantity,
-		OrderType:  orderType,
-		StopPrice:  triggerPrice,
-		ReduceOnly: true,
+	c.JSON(200, gin.H{
+		"code": "0",
+		"msg":  "",
+		"data": []gin.H{
+			{
+				"algoId":      strconv.Itoa(int(order.ID)),
+				"algoClOrdId": order.ClientOrderID,
+				"sCode":       "0",
+				"sMsg":        "",
+			},
+		},
+	})
+}
+
+// createTrailingStop places a move_order_stop algo order that trails the price by callbackRatio or callbackSpread
+func (h *Handler) createTrailingStop(c *gin.Context, accountID uint, symbol string, side models.PositionSide, quantity float64, callbackRatio, callbackSpread, activePx, algoClOrdId string) {
+	req := &service.ConditionalOrderRequest{
+		AccountID:     accountID,
+		Symbol:        symbol,
+		Side:          side,
+		Quantity:      quantity,
+		OrderType:     models.OrderTypeTrailingStop,
+		ReduceOnly:    true,
+		ClientOrderID: algoClOrdId,
 	}
+	req.CallbackRate, _ = strconv.ParseFloat(callbackRatio, 64)
+	req.CallbackSpread, _ = strconv.ParseFloat(callbackSpread, 64)
+	req.ActivationPrice, _ = strconv.ParseFloat(activePx, 64)
 
-	order, _, err := h.tradingService.ClosePosition(closeReq, models.ExchangeOKX)
+	order, err := h.tradingService.CreateConditionalOrder(req, models.ExchangeOKX)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -339,17 +431,7 @@ func (h *Handler) GetOpenAlgoOrders(c *gin.Context) {
 
 	data := make([]gin.H, 0)
 	for _, order := range orders {
-		data = append(data, gin.H{
-			"algoId":      strconv.Itoa(int(order.ID)),
-			"algoClOrdId": order.ClientOrderID,
-			"instId":      convertToOKXSymbol(order.Symbol),
-			"instType":    "SWAP",
-			"ordType":     "conditional",
-			"sz":          strconv.FormatFloat(order.Quantity, 'f', 8, 64),
-			"triggerPx":   strconv.FormatFloat(order.StopPrice, 'f', 8, 64),
-			"state":       "live",
-			"cTime":       strconv.FormatInt(order.CreatedAt.UnixMilli(), 10),
-		})
+		data = append(data, h.formatAlgoOrder(&order))
 	}
 
 	c.JSON(200, gin.H{
@@ -359,6 +441,34 @@ func (h *Handler) GetOpenAlgoOrders(c *gin.Context) {
 	})
 }
 
+// GetAlgoOrder handles GET /api/v5/trade/order-algo
+func (h *Handler) GetAlgoOrder(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, "50111", "API key is invalid")
+		return
+	}
+
+	algoId, algoClOrdId := c.Query("algoId"), c.Query("algoClOrdId")
+	if algoId == "" && algoClOrdId == "" {
+		h.errorResponse(c, "51000", "Parameter algoId or algoClOrdId error")
+		return
+	}
+
+	orderID, _ := strconv.ParseUint(algoId, 10, 64)
+	order, err := h.tradingService.FindOrder(account.ID, uint(orderID), algoClOrdId)
+	if err != nil {
+		h.errorResponse(c, "51603", "Order does not exist")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"code": "0",
+		"msg":  "",
+		"data": []gin.H{h.formatAlgoOrder(order)},
+	})
+}
+
 // CancelAlgoOrder handles POST /api/v5/trade/cancel-algos
 func (h *Handler) CancelAlgoOrder(c *gin.Context) {
 	account := middleware.GetAccount(c)
@@ -402,22 +512,127 @@ func (h *Handler) CancelOrder(c *gin.Context) {
 	}
 
 	var req struct {
-		InstId string `json:"instId"`
-		OrdId  string `json:"ordId"`
+		InstId  string `json:"instId"`
+		OrdId   string `json:"ordId"`
+		ClOrdId string `json:"clOrdId"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorResponse(c, "50000", err.Error())
+		return
+	}
+	if req.OrdId == "" && req.ClOrdId == "" {
+		h.errorResponse(c, "51000", "Parameter ordId or clOrdId error")
+		return
+	}
+
+	orderID, _ := strconv.ParseUint(req.OrdId, 10, 64)
+	order, err := h.tradingService.FindOrder(account.ID, uint(orderID), req.ClOrdId)
+	if err == nil && order.Symbol == convertFromOKXSymbol(req.InstId) {
+		order, err = h.tradingService.CancelOrder(account.ID, order.ID)
+	} else {
+		err = service.ErrOrderNotFound
+	}
+	if err != nil {
+		h.errorResponse(c, "51400", "Order cancellation failed as the order has been filled, canceled or does not exist")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"code": "0",
+		"msg":  "",
+		"data": []gin.H{
+			{
+				"ordId":   strconv.Itoa(int(order.ID)),
+				"clOrdId": order.ClientOrderID,
+				"sCode":   "0",
+				"sMsg":    "",
+			},
+		},
+	})
+}
+
+// AmendOrder handles POST /api/v5/trade/amend-order
+func (h *Handler) AmendOrder(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, "50111", "API key is invalid")
+		return
+	}
+
+	var req struct {
+		InstId  string `json:"instId"`
+		OrdId   string `json:"ordId"`
+		ClOrdId string `json:"clOrdId"`
+		ReqId   string `json:"reqId"`
+		NewSz   string `json:"newSz"`
+		NewPx   string `json:"newPx"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.errorResponse(c, "50000", err.Error())
 		return
 	}
+	if req.NewSz == "" && req.NewPx == "" {
+		h.errorResponse(c, "51000", "Parameter newSz or newPx error")
+		return
+	}
+
+	var order *models.Order
+	var err error
+	if req.OrdId != "" {
+		orderID, _ := strconv.ParseUint(req.OrdId, 10, 64)
+		order, err = h.tradingService.GetOrderStatus(account.ID, uint(orderID))
+	} else {
+		order, err = h.tradingService.GetOrderByClientID(account.ID, req.ClOrdId)
+	}
+	if err != nil || order.Symbol != convertFromOKXSymbol(req.InstId) {
+		h.errorResponse(c, "51503", "Order modification failed as the order does not exist.")
+		return
+	}
+
+	amend := &service.AmendOrderRequest{}
+	if req.NewSz != "" {
+		sz, err := strconv.ParseFloat(req.NewSz, 64)
+		if err != nil {
+			h.errorResponse(c, "51000", "Parameter newSz error")
+			return
+		}
+		size := h.contractSpec(order.Symbol).ToBase(sz)
+		amend.Quantity = &size
+	}
+	if req.NewPx != "" {
+		price, err := strconv.ParseFloat(req.NewPx, 64)
+		if err != nil {
+			h.errorResponse(c, "51000", "Parameter newPx error")
+			return
+		}
+		amend.Price = &price
+	}
+
+	order, err = h.tradingService.AmendOrder(account.ID, order.ID, amend)
+	if err != nil {
+		switch err {
+		case service.ErrOrderNotOpen:
+			h.errorResponse(c, "51509", "Modification failed as the order has been completed.")
+		case service.ErrOrderNotModified:
+			h.errorResponse(c, "51000", "Parameter newSz or newPx error: no need to modify the order")
+		case service.ErrInvalidPrice:
+			h.errorResponse(c, "51000", "Parameter newPx error")
+		default:
+			h.handleError(c, err)
+		}
+		return
+	}
 
 	c.JSON(200, gin.H{
 		"code": "0",
 		"msg":  "",
 		"data": []gin.H{
 			{
-				"ordId":   req.OrdId,
-				"clOrdId": "",
+				"ordId":   strconv.Itoa(int(order.ID)),
+				"clOrdId": order.ClientOrderID,
+				"reqId":   req.ReqId,
 				"sCode":   "0",
 				"sMsg":    "",
 			},
@@ -426,6 +641,7 @@ func (h *Handler) CancelOrder(c *gin.Context) {
 }
 
 // CancelBatchOrders handles POST /api/v5/trade/cancel-batch-orders
+// Orders are canceled independently and each reports its own sCode/sMsg
 func (h *Handler) CancelBatchOrders(c *gin.Context) {
 	account := middleware.GetAccount(c)
 	if account == nil {
@@ -434,28 +650,71 @@ func (h *Handler) CancelBatchOrders(c *gin.Context) {
 	}
 
 	var req []struct {
-		InstId string `json:"instId"`
-		OrdId  string `json:"ordId"`
+		InstId  string `json:"instId"`
+		OrdId   string `json:"ordId"`
+		ClOrdId string `json:"clOrdId"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.errorResponse(c, "50000", err.Error())
 		return
 	}
+	if len(req) == 0 || len(req) > maxBatchOrders {
+		h.errorResponse(c, "51004", "The number of orders in a batch must be between 1 and 20")
+		return
+	}
 
-	data := make([]gin.H, 0)
+	data := make([]gin.H, 0, len(req))
+	failed := 0
 	for _, r := range req {
+		var order *models.Order
+		var err error
+		if r.OrdId != "" {
+			orderID, _ := strconv.ParseUint(r.OrdId, 10, 64)
+			order, err = h.tradingService.GetOrderStatus(account.ID, uint(orderID))
+		} else {
+			order, err = h.tradingService.GetOrderByClientID(account.ID, r.ClOrdId)
+		}
+		if err == nil && order.Symbol == convertFromOKXSymbol(r.InstId) {
+			order, err = h.tradingService.CancelOrder(account.ID, order.ID)
+		} else {
+			err = service.ErrOrderNotFound
+		}
+
+		if err != nil {
+			data = append(data, gin.H{
+				"ordId":   r.OrdId,
+				"clOrdId": r.ClOrdId,
+				"sCode":   "51400",
+				"sMsg":    "Order cancellation failed as the order has been filled, canceled or does not exist",
+			})
+			failed++
+			continue
+		}
 		data = append(data, gin.H{
-			"ordId":   r.OrdId,
-			"clOrdId": "",
+			"ordId":   strconv.Itoa(int(order.ID)),
+			"clOrdId": order.ClientOrderID,
 			"sCode":   "0",
 			"sMsg":    "",
 		})
 	}
 
+	batchResponse(c, data, failed)
+}
+
+// batchResponse writes the result of a batch operation
+// OKX reports code 1 when every item failed and code 2 when only some did
+func batchResponse(c *gin.Context, data []gin.H, failed int) {
+	code, msg := "0", ""
+	if failed == len(data) {
+		code, msg = "1", "All operations failed"
+	} else if failed > 0 {
+		code, msg = "2", "Batch operation partially succeeded"
+	}
+
 	c.JSON(200, gin.H{
-		"code": "0",
-		"msg":  "",
+		"code": code,
+		"msg":  msg,
 		"data": data,
 	})
 }
@@ -478,24 +737,242 @@ func (h *Handler) GetOpenOrders(c *gin.Context) {
 
 	data := make([]gin.H, 0)
 	for _, order := range orders {
+		data = append(data, h.formatOrder(&order))
+	}
+
+	c.JSON(200, gin.H{
+		"code": "0",
+		"msg":  "",
+		"data": data,
+	})
+}
+
+// GetOrder handles GET /api/v5/trade/order
+func (h *Handler) GetOrder(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, "50111", "API key is invalid")
+		return
+	}
+
+	ordId, clOrdId := c.Query("ordId"), c.Query("clOrdId")
+	if ordId == "" && clOrdId == "" {
+		h.errorResponse(c, "51000", "Parameter ordId or clOrdId error")
+		return
+	}
+
+	orderID, _ := strconv.ParseUint(ordId, 10, 64)
+	order, err := h.tradingService.FindOrder(account.ID, uint(orderID), clOrdId)
+	if err != nil {
+		h.errorResponse(c, "51603", "Order does not exist")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"code": "0",
+		"msg":  "",
+		"data": []gin.H{h.formatOrder(order)},
+	})
+}
+
+// GetFills handles GET /api/v5/trade/fills
+// after/before are billId cursors for older/newer fills; results are newest first
+func (h *Handler) GetFills(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, "50111", "API key is invalid")
+		return
+	}
+
+	q := historyQuery(c)
+	if ordId, err := strconv.ParseUint(c.Query("ordId"), 10, 64); err == nil {
+		q.OrderID = uint(ordId)
+	}
+
+	trades, err := h.tradingService.GetTradeHistory(account.ID, q)
+	if err != nil {
+		h.errorResponse(c, "50000", err.Error())
+		return
+	}
+
+	data := make([]gin.H, 0, len(trades))
+	for _, trade := range trades {
 		posSide := "long"
-		if order.PositionSide == models.PositionSideShort {
+		if trade.Order.PositionSide == models.PositionSideShort {
 			posSide = "short"
 		}
+		execType := "T"
+		if trade.IsMaker {
+			execType = "M"
+		}
 
 		data = append(data, gin.H{
-			"instId":  convertToOKXSymbol(order.Symbol),
-			"ordId":   strconv.Itoa(int(order.ID)),
-			"clOrdId": order.ClientOrderID,
-			"px":      strconv.FormatFloat(order.Price, 'f', 8, 64),
-			"sz":      strconv.FormatFloat(order.Quantity, 'f', 8, 64),
-			"fillSz":  strconv.FormatFloat(order.FilledQty, 'f', 8, 64),
-			"side":    strings.ToLower(string(order.Side)),
-			"posSide": posSide,
-			"ordType": strings.ToLower(string(order.Type)),
-			"state":   "live",
-			"cTime":   strconv.FormatInt(order.CreatedAt.UnixMilli(), 10),
-			"uTime":   strconv.FormatInt(order.UpdatedAt.UnixMilli(), 10),
+			"instType": "SWAP",
+			"instId":   convertToOKXSymbol(trade.Symbol),
+			"tradeId":  strconv.Itoa(int(trade.ID)),
+			"ordId":    strconv.Itoa(int(trade.OrderID)),
+			"clOrdId":  trade.Order.ClientOrderID,
+			"billId":   strconv.Itoa(int(trade.ID)),
+			"tag":      "",
+			"fillPx":   strconv.FormatFloat(trade.Price, 'f', 8, 64),
+			"fillSz":   h.formatSz(trade.Symbol, trade.Quantity),
+			"fillPnl":  strconv.FormatFloat(trade.RealizedPnL, 'f', 8, 64),
+			"side":     strings.ToLower(string(trade.Side)),
+			"posSide":  posSide,
+			"execType": execType,
+			"feeCcy":   trade.FeeCurrency,
+			"fee":      strconv.FormatFloat(-trade.Fee, 'f', 8, 64),
+			"ts":       strconv.FormatInt(trade.ExecutedAt.UnixMilli(), 10),
+			"fillTime": strconv.FormatInt(trade.ExecutedAt.UnixMilli(), 10),
+		})
+	}
+
+	c.JSON(200, gin.H{
+		"code": "0",
+		"msg":  "",
+		"data": data,
+	})
+}
+
+// GetOrdersHistory handles GET /api/v5/trade/orders-history
+// Only completed (filled or canceled) orders are listed; after/before are ordId cursors
+func (h *Handler) GetOrdersHistory(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, "50111", "API key is invalid")
+		return
+	}
+
+	if c.Query("instType") == "" {
+		h.errorResponse(c, "51000", "Parameter instType error")
+		return
+	}
+
+	q := historyQuery(c)
+	switch c.Query("state") {
+	case "":
+		q.Statuses = []models.OrderStatus{models.OrderStatusFilled, models.OrderStatusCanceled, models.OrderStatusExpired, models.OrderStatusRejected}
+	case "filled":
+		q.Statuses = []models.OrderStatus{models.OrderStatusFilled}
+	case "canceled":
+		q.Statuses = []models.OrderStatus{models.OrderStatusCanceled, models.OrderStatusExpired, models.OrderStatusRejected}
+	default:
+		h.errorResponse(c, "51000", "Parameter state error")
+		return
+	}
+
+	orders, err := h.tradingService.GetOrderHistoryPage(account.ID, q)
+	if err != nil {
+		h.errorResponse(c, "50000", err.Error())
+		return
+	}
+
+	data := make([]gin.H, 0, len(orders))
+	for i := range orders {
+		data = append(data, h.formatOrder(&orders[i]))
+	}
+
+	c.JSON(200, gin.H{
+		"code": "0",
+		"msg":  "",
+		"data": data,
+	})
+}
+
+// historyQuery parses instId, begin/end, limit and the after/before ID cursors of history endpoints
+func historyQuery(c *gin.Context) repository.HistoryQuery {
+	var q repository.HistoryQuery
+	if instId := c.Query("instId"); instId != "" {
+		q.Symbol = convertFromOKXSymbol(instId)
+	}
+
+	q.Limit, _ = strconv.Atoi(c.Query("limit"))
+	if q.Limit <= 0 || q.Limit > 100 {
+		q.Limit = 100
+	}
+
+	if ms, err := strconv.ParseInt(c.Query("begin"), 10, 64); err == nil {
+		q.Start = time.UnixMilli(ms)
+	}
+	if ms, err := strconv.ParseInt(c.Query("end"), 10, 64); err == nil {
+		q.End = time.UnixMilli(ms)
+	}
+	if id, err := strconv.ParseUint(c.Query("after"), 10, 64); err == nil {
+		q.BeforeID = uint(id)
+	}
+	if id, err := strconv.ParseUint(c.Query("before"), 10, 64); err == nil {
+		q.AfterID = uint(id)
+	}
+
+	return q
+}
+
+// GetBills handles GET /api/v5/account/bills
+func (h *Handler) GetBills(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, "50111", "API key is invalid")
+		return
+	}
+
+	symbol := ""
+	if instId := c.Query("instId"); instId != "" {
+		symbol = convertFromOKXSymbol(instId)
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	var begin, end time.Time
+	if ms, err := strconv.ParseInt(c.Query("begin"), 10, 64); err == nil {
+		begin = time.UnixMilli(ms)
+	}
+	if ms, err := strconv.ParseInt(c.Query("end"), 10, 64); err == nil {
+		end = time.UnixMilli(ms)
+	}
+
+	data := make([]gin.H, 0)
+
+	// Only funding fee bills (type 8) are recorded
+	if billType := c.Query("type"); billType != "" && billType != "8" {
+		c.JSON(200, gin.H{"code": "0", "msg": "", "data": data})
+		return
+	}
+
+	fees, err := h.fundingService.GetFundingFees(account.ID, symbol, begin, end, limit)
+	if err != nil {
+		h.errorResponse(c, "50000", err.Error())
+		return
+	}
+
+	for _, fee := range fees {
+		// 173: funding fee expense, 174: funding fee income
+		subType := "173"
+		if fee.Amount > 0 {
+			subType = "174"
+		}
+		mgnMode := string(fee.MarginMode)
+		if mgnMode == "" {
+			mgnMode = string(models.MarginModeCross)
+		}
+
+		data = append(data, gin.H{
+			"billId":   strconv.Itoa(int(fee.ID)),
+			"instType": "SWAP",
+			"instId":   convertToOKXSymbol(fee.Symbol),
+			"ccy":      "USDT",
+			"mgnMode":  mgnMode,
+			"type":     "8",
+			"subType":  subType,
+			"balChg":   strconv.FormatFloat(fee.Amount, 'f', 8, 64),
+			"bal":      strconv.FormatFloat(fee.BalanceAfter, 'f', 8, 64),
+			"sz":       h.formatSz(fee.Symbol, fee.Quantity),
+			"px":       strconv.FormatFloat(fee.MarkPrice, 'f', 8, 64),
+			"pnl":      strconv.FormatFloat(fee.Amount, 'f', 8, 64),
+			"fee":      "0",
+			"ordId":    "",
+			"ts":       strconv.FormatInt(fee.FundingTime.UnixMilli(), 10),
 		})
 	}
 
@@ -528,6 +1005,23 @@ func (h *Handler) SetLeverage(c *gin.Context) {
 	symbol := convertFromOKXSymbol(req.InstId)
 	leverage, _ := strconv.Atoi(req.Lever)
 
+	// Leverage is set per margin mode, so switching mode here persists it for the instrument
+	if req.MgnMode != "" {
+		err := h.tradingService.SetMarginMode(account.ID, symbol, models.MarginMode(req.MgnMode))
+		switch err {
+		case nil, service.ErrSettingNotChanged:
+		case service.ErrInvalidMarginMode:
+			h.errorResponse(c, "51000", "Parameter mgnMode error")
+			return
+		case service.ErrPositionExists:
+			h.errorResponse(c, "59000", "Setting failed. Cancel any open orders, close positions, and stop trading bots first.")
+			return
+		default:
+			h.errorResponse(c, "50000", err.Error())
+			return
+		}
+	}
+
 	if err := h.tradingService.SetLeverage(account.ID, symbol, leverage); err != nil {
 		h.errorResponse(c, "50000", err.Error())
 		return
@@ -546,6 +1040,122 @@ func (h *Handler) SetLeverage(c *gin.Context) {
 	})
 }
 
+// AdjustMarginBalance handles POST /api/v5/account/position/margin-balance
+func (h *Handler) AdjustMarginBalance(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, "50111", "API key is invalid")
+		return
+	}
+
+	var req struct {
+		InstId  string `json:"instId"`
+		PosSide string `json:"posSide"` // long, short, net
+		Type    string `json:"type"`    // add, reduce
+		Amt     string `json:"amt"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorResponse(c, "50000", err.Error())
+		return
+	}
+
+	amount, _ := strconv.ParseFloat(req.Amt, 64)
+	if amount <= 0 {
+		h.errorResponse(c, "51000", "Parameter amt error")
+		return
+	}
+
+	delta := amount
+	switch req.Type {
+	case "add":
+	case "reduce":
+		delta = -amount
+	default:
+		h.errorResponse(c, "51000", "Parameter type error")
+		return
+	}
+
+	var side models.PositionSide
+	switch req.PosSide {
+	case "long":
+		side = models.PositionSideLong
+	case "short":
+		side = models.PositionSideShort
+	}
+
+	if _, err := h.tradingService.AdjustIsolatedMargin(account.ID, convertFromOKXSymbol(req.InstId), side, delta); err != nil {
+		switch err {
+		case service.ErrPositionNotFound:
+			h.errorResponse(c, "51023", "Position does not exist")
+		case service.ErrNotIsolated:
+			h.errorResponse(c, "59301", "Margin adjustment is not allowed in cross margin mode")
+		case service.ErrInsufficientBalance:
+			h.errorResponse(c, "51008", "Order failed. Insufficient USDT margin in account")
+		case service.ErrInsufficientMargin:
+			h.errorResponse(c, "59302", "Margin reduction exceeds the maximum reducible amount")
+		default:
+			h.errorResponse(c, "50000", err.Error())
+		}
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"code": "0",
+		"msg":  "",
+		"data": []gin.H{
+			{
+				"instId":  req.InstId,
+				"posSide": req.PosSide,
+				"amt":     req.Amt,
+				"type":    req.Type,
+				"ccy":     "USDT",
+			},
+		},
+	})
+}
+
+// GetLeverageInfo handles GET /api/v5/account/leverage-info
+func (h *Handler) GetLeverageInfo(c *gin.Context) {
+	account := middleware.GetAccount(c)
+	if account == nil {
+		h.errorResponse(c, "50111", "API key is invalid")
+		return
+	}
+
+	instIds := c.Query("instId")
+	mgnMode := c.Query("mgnMode")
+	if instIds == "" {
+		h.errorResponse(c, "50014", "Parameter instId can not be empty")
+		return
+	}
+	if mgnMode == "" {
+		h.errorResponse(c, "50014", "Parameter mgnMode can not be empty")
+		return
+	}
+
+	data := make([]gin.H, 0)
+	for _, instId := range strings.Split(instIds, ",") {
+		setting, err := h.tradingService.GetSymbolSetting(account.ID, convertFromOKXSymbol(instId))
+		if err != nil {
+			h.errorResponse(c, "50000", err.Error())
+			return
+		}
+		data = append(data, gin.H{
+			"instId":  instId,
+			"mgnMode": mgnMode,
+			"posSide": "net",
+			"lever":   strconv.Itoa(setting.Leverage),
+		})
+	}
+
+	c.JSON(200, gin.H{
+		"code": "0",
+		"msg":  "",
+		"data": data,
+	})
+}
+
 // GetMarkPrice handles GET /api/v5/public/mark-price
 func (h *Handler) GetMarkPrice(c *gin.Context) {
 	instId := c.Query("instId")
@@ -599,6 +1209,212 @@ func (h *Handler) GetTickers(c *gin.Context) {
 
 // Helper functions
 
+// formatOrder formats an order for OKX response
+func (h *Handler) formatOrder(order *models.Order) gin.H {
+	posSide := "long"
+	if order.PositionSide == models.PositionSideShort {
+		posSide = "short"
+	}
+
+	// Liquidation orders are market orders tagged with a full_liquidation category
+	ordType := strings.ToLower(string(order.Type))
+	category := "normal"
+	if order.IsLiquidation() {
+		ordType = "market"
+		category = "full_liquidation"
+	}
+
+	// Limit orders with a time in force are their own order types; cancelSource tells why they expired
+	cancelSource, cancelSourceReason := "", ""
+	if order.Type == models.OrderTypeLimit {
+		switch order.TimeInForce {
+		case models.TimeInForceIOC:
+			ordType, cancelSource, cancelSourceReason = "ioc", "14", "IOC order was partially canceled due to incompletely filled"
+		case models.TimeInForceFOK:
+			ordType, cancelSource, cancelSourceReason = "fok", "15", "FOK order was canceled due to incompletely filled"
+		case models.TimeInForceGTX:
+			ordType, cancelSource, cancelSourceReason = "post_only", "31", "The post-only order will take liquidity in taker orders"
+		}
+	}
+	if order.Status != models.OrderStatusExpired {
+		cancelSource, cancelSourceReason = "", ""
+	}
+
+	// Limit orders placed by a triggered algo order carry its algoId
+	algoId := ""
+	if order.ParentOrderID != nil {
+		algoId = strconv.Itoa(int(*order.ParentOrderID))
+	}
+
+	return gin.H{
+		"instId":             convertToOKXSymbol(order.Symbol),
+		"instType":           "SWAP",
+		"ordId":              strconv.Itoa(int(order.ID)),
+		"clOrdId":            order.ClientOrderID,
+		"px":                 strconv.FormatFloat(order.Price, 'f', 8, 64),
+		"sz":                 h.formatSz(order.Symbol, order.Quantity),
+		"fillSz":             h.formatSz(order.Symbol, order.FilledQty),
+		"accFillSz":          h.formatSz(order.Symbol, order.FilledQty),
+		"avgPx":              strconv.FormatFloat(order.AvgPrice, 'f', 8, 64),
+		"side":               strings.ToLower(string(order.Side)),
+		"posSide":            posSide,
+		"ordType":            ordType,
+		"state":              convertOrderState(order.Status),
+		"category":           category,
+		"cancelSource":       cancelSource,
+		"cancelSourceReason": cancelSourceReason,
+		"reduceOnly":         strconv.FormatBool(order.ReduceOnly),
+		"algoId":             algoId,
+		"cTime":              strconv.FormatInt(order.CreatedAt.UnixMilli(), 10),
+		"uTime":              strconv.FormatInt(order.UpdatedAt.UnixMilli(), 10),
+	}
+}
+
+// formatAlgoOrder formats a conditional (SL/TP) order for OKX response
+func (h *Handler) formatAlgoOrder(order *models.Order) gin.H {
+	// A triggered algo order is "effective"; untriggered ones stay "live" until canceled
+	state := "live"
+	switch order.Status {
+	case models.OrderStatusFilled, models.OrderStatusPartiallyFilled, models.OrderStatusTriggered:
+		state = "effective"
+	case models.OrderStatusCanceled, models.OrderStatusExpired, models.OrderStatusRejected:
+		state = "canceled"
+	}
+
+	// ordPx is -1 for algo orders closing at market; ordId is the limit order a triggered one placed
+	ordPx, ordId := "-1", ""
+	if order.Price > 0 {
+		ordPx = strconv.FormatFloat(order.Price, 'f', 8, 64)
+	}
+	if order.TriggeredOrderID != nil {
+		ordId = strconv.Itoa(int(*order.TriggeredOrderID))
+	}
+
+	result := gin.H{
+		"algoId":      strconv.Itoa(int(order.ID)),
+		"algoClOrdId": order.ClientOrderID,
+		"instId":      convertToOKXSymbol(order.Symbol),
+		"instType":    "SWAP",
+		"ordType":     "conditional",
+		"side":        strings.ToLower(string(order.Side)),
+		"sz":          h.formatSz(order.Symbol, order.Quantity),
+		"triggerPx":   strconv.FormatFloat(order.StopPrice, 'f', 8, 64),
+		"ordPx":       ordPx,
+		"ordId":       ordId,
+		"state":       state,
+		"cTime":       strconv.FormatInt(order.CreatedAt.UnixMilli(), 10),
+	}
+	if order.TriggerPriceType != "" {
+		result["triggerPxType"] = strings.ToLower(string(order.TriggerPriceType))
+	}
+
+	// Trailing stops report their callback and current trigger; watermarkPx is a simulator extension
+	if order.Type == models.OrderTypeTrailingStop {
+		result["ordType"] = "move_order_stop"
+		result["triggerPx"] = ""
+		result["callbackRatio"] = ""
+		result["callbackSpread"] = ""
+		if order.CallbackRate > 0 {
+			result["callbackRatio"] = strconv.FormatFloat(order.CallbackRate, 'f', -1, 64)
+		} else {
+			result["callbackSpread"] = strconv.FormatFloat(order.CallbackSpread, 'f', -1, 64)
+		}
+		result["activePx"] = ""
+		if order.ActivationPrice > 0 {
+			result["activePx"] = strconv.FormatFloat(order.ActivationPrice, 'f', 8, 64)
+		}
+		result["moveTriggerPx"] = strconv.FormatFloat(order.StopPrice, 'f', 8, 64)
+		result["watermarkPx"] = strconv.FormatFloat(order.Watermark, 'f', 8, 64)
+	}
+	return result
+}
+
+// formatPosition formats a position for OKX response
+func (h *Handler) formatPosition(pos *models.Position) gin.H {
+	posSide := "long"
+	if pos.Side == models.PositionSideShort {
+		posSide = "short"
+	}
+
+	uplRatio := 0.0
+	if pos.Margin > 0 {
+		uplRatio = pos.UnrealizedPnL / pos.Margin
+	}
+
+	return gin.H{
+		"instId":   convertToOKXSymbol(pos.Symbol),
+		"instType": "SWAP",
+		"mgnMode":  string(pos.MarginMode),
+		"posId":    strconv.Itoa(int(pos.ID)),
+		"posSide":  posSide,
+		"pos":      h.formatSz(pos.Symbol, pos.Quantity),
+		"avgPx":    strconv.FormatFloat(pos.EntryPrice, 'f', 8, 64),
+		"markPx":   strconv.FormatFloat(pos.MarkPrice, 'f', 8, 64),
+		"upl":      strconv.FormatFloat(pos.UnrealizedPnL, 'f', 8, 64),
+		"uplRatio": strconv.FormatFloat(uplRatio, 'f', 8, 64),
+		"lever":    strconv.Itoa(pos.Leverage),
+		"liqPx":    strconv.FormatFloat(pos.LiquidationPrice, 'f', 8, 64),
+		"margin":   strconv.FormatFloat(pos.Margin, 'f', 8, 64),
+		"cTime":    strconv.FormatInt(pos.CreatedAt.UnixMilli(), 10),
+		"uTime":    strconv.FormatInt(pos.UpdatedAt.UnixMilli(), 10),
+	}
+}
+
+// formatBalance formats an account balance for OKX response
+func formatBalance(balance map[string]float64) gin.H {
+	return gin.H{
+		"totalEq":     strconv.FormatFloat(balance["equity"], 'f', 8, 64),
+		"isoEq":       strconv.FormatFloat(balance["isolated_equity"], 'f', 8, 64),
+		"adjEq":       strconv.FormatFloat(balance["equity"], 'f', 8, 64),
+		"ordFroz":     "0",
+		"imr":         strconv.FormatFloat(balance["margin"], 'f', 8, 64),
+		"mmr":         "0",
+		"notionalUsd": strconv.FormatFloat(balance["margin"]*10, 'f', 8, 64),
+		"mgnRatio":    "999",
+		"details": []gin.H{
+			{
+				"ccy":       "USDT",
+				"eq":        strconv.FormatFloat(balance["equity"], 'f', 8, 64),
+				"cashBal":   strconv.FormatFloat(balance["balance"], 'f', 8, 64),
+				"availBal":  strconv.FormatFloat(balance["available"], 'f', 8, 64),
+				"frozenBal": strconv.FormatFloat(balance["margin"], 'f', 8, 64),
+				"upl":       strconv.FormatFloat(balance["unrealized_pnl"], 'f', 8, 64),
+				"uplLiab":   "0",
+			},
+		},
+		"uTime": strconv.FormatInt(time.Now().UnixMilli(), 10),
+	}
+}
+
+// contractSpec returns the contract specs of a symbol; unknown symbols are sized in base units
+func (h *Handler) contractSpec(symbol string) *exchange.SymbolInfo {
+	if info, err := h.priceService.GetSymbolInfo(string(models.ExchangeOKX), symbol); err == nil {
+		return info
+	}
+	return &exchange.SymbolInfo{Symbol: symbol}
+}
+
+// formatSz formats a base-asset quantity as an OKX size in contracts
+func (h *Handler) formatSz(symbol string, quantity float64) string {
+	return strconv.FormatFloat(h.contractSpec(symbol).ToContracts(quantity), 'f', 8, 64)
+}
+
+// convertOrderState maps order status to OKX order state
+func convertOrderState(status models.OrderStatus) string {
+	switch status {
+	case models.OrderStatusPartiallyFilled:
+		return "partially_filled"
+	case models.OrderStatusFilled:
+		return "filled"
+	case models.OrderStatusCanceled, models.OrderStatusExpired, models.OrderStatusRejected:
+		return "canceled"
+	case models.OrderStatusTriggered:
+		return "effective"
+	default:
+		return "live"
+	}
+}
+
 func convertToOKXSymbol(symbol string) string {
 	if len(symbol) > 4 && symbol[len(symbol)-4:] == "USDT" {
 		base := symbol[:len(symbol)-4]
@@ -630,17 +1446,33 @@ func (h *Handler) errorResponse(c *gin.Context, code, msg string) {
 }
 
 func (h *Handler) handleError(c *gin.Context, err error) {
+	code, msg := errorCode(err)
+	h.errorResponse(c, code, msg)
+}
+
+// errorCode maps service errors to OKX error codes
+func errorCode(err error) (string, string) {
 	switch err {
 	case service.ErrInsufficientBalance:
-		h.errorResponse(c, "51008", "Order placement failed due to insufficient balance")
+		return "51008", "Order placement failed due to insufficient balance"
 	case service.ErrInvalidSymbol:
-		h.errorResponse(c, "51001", "Instrument ID does not exist")
+		return "51001", "Instrument ID does not exist"
 	case service.ErrInvalidQuantity:
-		h.errorResponse(c, "51001", "Order quantity must be greater than 0")
+		return "51001", "Order quantity must be greater than 0"
 	case service.ErrNoOpenPosition:
-		h.errorResponse(c, "51010", "No positions to close")
+		return "51010", "No positions to close"
+	case service.ErrDuplicateClientID:
+		return "51016", "Duplicated clOrdId"
+	case service.ErrInvalidCallback:
+		return "51000", "Parameter callbackRatio or callbackSpread error"
+	case service.ErrInvalidTrigger:
+		return "51000", "Parameter triggerPxType error"
+	case service.ErrInvalidTimeInForce:
+		return "51000", "Parameter ordType error"
+	case service.ErrPostOnlyWouldTake:
+		return "51000", "Parameter newPx error: the post-only order would take liquidity"
 	default:
-		h.errorResponse(c, "50000", err.Error())
+		return "50000", err.Error()
 	}
 }
 
@@ -669,18 +1501,27 @@ func (h *Handler) RegisterRoutes(router *gin.Engine, authMiddleware gin.HandlerF
 		{
 			account.GET("/balance", h.GetBalance)
 			account.GET("/positions", h.GetPositions)
+			account.GET("/bills", h.GetBills)
 			account.POST("/set-leverage", middleware.TradingLoggerMiddleware(), h.SetLeverage)
+			account.GET("/leverage-info", h.GetLeverageInfo)
+			account.POST("/position/margin-balance", middleware.TradingLoggerMiddleware(), h.AdjustMarginBalance)
 		}
 
 		trade := api.Group("/trade")
 		{
 			trade.POST("/order", middleware.TradingLoggerMiddleware(), h.CreateOrder)
 			trade.POST("/cancel-order", middleware.TradingLoggerMiddleware(), h.CancelOrder)
+			trade.POST("/batch-orders", middleware.TradingLoggerMiddleware(), h.CreateBatchOrders)
 			trade.POST("/cancel-batch-orders", middleware.TradingLoggerMiddleware(), h.CancelBatchOrders)
+			trade.POST("/amend-order", middleware.TradingLoggerMiddleware(), h.AmendOrder)
+			trade.GET("/order", h.GetOrder)
 			trade.GET("/orders-pending", h.GetOpenOrders)
+			trade.GET("/orders-history", h.GetOrdersHistory)
+			trade.GET("/fills", h.GetFills)
 			// Algo orders (SL/TP)
 			trade.POST("/order-algo", middleware.TradingLoggerMiddleware(), h.CreateAlgoOrder)
 			trade.POST("/cancel-algos", middleware.TradingLoggerMiddleware(), h.CancelAlgoOrder)
+			trade.GET("/order-algo", h.GetAlgoOrder)
 			trade.GET("/orders-algo-pending", h.GetOpenAlgoOrders)
 		}
 	}
@@ -0,0 +1,325 @@
+package okx
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ccxt-simulator/internal/middleware"
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/ccxt-simulator/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const wsWriteWait = 10 * time.Second
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// privateChannels lists the supported private channels
+var privateChannels = map[string]bool{
+	"orders":      true,
+	"orders-algo": true,
+	"positions":   true,
+	"account":     true,
+}
+
+// wsArg is an OKX WebSocket channel argument
+type wsArg struct {
+	Channel  string `json:"channel"`
+	InstType string `json:"instType,omitempty"`
+	InstId   string `json:"instId,omitempty"`
+	Ccy      string `json:"ccy,omitempty"`
+}
+
+// wsRequest is an OKX WebSocket operation request
+type wsRequest struct {
+	ID   string            `json:"id,omitempty"`
+	Op   string            `json:"op"`
+	Args []json.RawMessage `json:"args"`
+}
+
+// wsLoginArg is the argument of a login op
+type wsLoginArg struct {
+	APIKey     string `json:"apiKey"`
+	Passphrase string `json:"passphrase"`
+	Timestamp  string `json:"timestamp"`
+	Sign       string `json:"sign"`
+}
+
+// privateSession is a single private WebSocket connection
+type privateSession struct {
+	conn    *websocket.Conn
+	connID  string
+	writeMu sync.Mutex
+
+	account *models.Account
+	subs    map[string]wsArg // channel -> subscription
+	subsMu  sync.RWMutex
+}
+
+func (s *privateSession) write(v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return s.conn.WriteJSON(v)
+}
+
+func (s *privateSession) writeError(code, msg string) {
+	s.write(gin.H{"event": "error", "code": code, "msg": msg, "connId": s.connID})
+}
+
+// subscription returns the session's subscription for a channel
+func (s *privateSession) subscription(channel string) (wsArg, bool) {
+	s.subsMu.RLock()
+	defer s.subsMu.RUnlock()
+	arg, ok := s.subs[channel]
+	return arg, ok
+}
+
+// matches checks an instrument against a subscription's instType/instId filters
+func (arg wsArg) matches(symbol string) bool {
+	if arg.InstType != "" && arg.InstType != "SWAP" && arg.InstType != "ANY" {
+		return false
+	}
+	return arg.InstId == "" || arg.InstId == convertToOKXSymbol(symbol)
+}
+
+// PrivateWebSocket handles GET /ws/v5/private
+func (h *Handler) PrivateWebSocket(c *gin.Context) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	session := &privateSession{
+		conn:   conn,
+		connID: uuid.New().String()[:8],
+		subs:   make(map[string]wsArg),
+	}
+
+	var events chan service.UserEvent
+	done := make(chan struct{})
+	defer close(done)
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+
+		if string(message) == "ping" {
+			session.writeMu.Lock()
+			conn.WriteMessage(websocket.TextMessage, []byte("pong"))
+			session.writeMu.Unlock()
+			continue
+		}
+
+		var req wsRequest
+		if err := json.Unmarshal(message, &req); err != nil {
+			session.writeError("60012", "Invalid request: "+string(message))
+			continue
+		}
+
+		switch req.Op {
+		case "login":
+			if session.account != nil {
+				session.write(gin.H{"event": "login", "code": "0", "msg": "", "connId": session.connID})
+				continue
+			}
+			var arg wsLoginArg
+			if len(req.Args) == 0 || json.Unmarshal(req.Args[0], &arg) != nil {
+				session.writeError("60009", "Login failed.")
+				continue
+			}
+			account, code, msg := h.wsLogin(arg.APIKey, arg.Passphrase, arg.Timestamp, arg.Sign)
+			if account == nil {
+				session.writeError(code, msg)
+				continue
+			}
+			session.account = account
+			session.write(gin.H{"event": "login", "code": "0", "msg": "", "connId": session.connID})
+
+			events = h.tradingService.Events().Subscribe(account.ID)
+			defer h.tradingService.Events().Unsubscribe(account.ID, events)
+			go h.pumpEvents(session, events, done)
+
+		case "subscribe", "unsubscribe":
+			if session.account == nil {
+				session.writeError("60011", "Please log in")
+				continue
+			}
+			for _, raw := range req.Args {
+				var arg wsArg
+				if err := json.Unmarshal(raw, &arg); err != nil || !privateChannels[arg.Channel] {
+					session.writeError("60018", "Wrong URL or channel:"+arg.Channel+",instId or instType:"+arg.InstType+" doesn't exist.")
+					continue
+				}
+
+				session.subsMu.Lock()
+				if req.Op == "subscribe" {
+					session.subs[arg.Channel] = arg
+				} else {
+					delete(session.subs, arg.Channel)
+				}
+				session.subsMu.Unlock()
+
+				session.write(gin.H{"event": req.Op, "arg": arg, "connId": session.connID})
+				if req.Op == "subscribe" {
+					h.pushSnapshot(session, arg)
+				}
+			}
+
+		default:
+			session.writeError("60012", "Invalid request: "+string(message))
+		}
+	}
+}
+
+// RegisterWebSocketRoutes registers the OKX public and private WebSocket endpoints
+func (h *Handler) RegisterWebSocketRoutes(router *gin.Engine, login middleware.OKXLoginFunc) {
+	h.wsLogin = login
+	router.GET("/ws/v5/public", h.PublicWebSocket)
+	router.GET("/ws/v5/private", h.PrivateWebSocket)
+}
+
+// pumpEvents forwards the account's user events to subscribed channels
+func (h *Handler) pumpEvents(session *privateSession, events chan service.UserEvent, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case event := <-events:
+			if event.Type == service.UserEventAccountUpdate {
+				h.pushAccountUpdate(session, event)
+			} else {
+				h.pushOrderUpdate(session, event)
+			}
+		}
+	}
+}
+
+// pushSnapshot sends the initial snapshot OKX pushes after subscribing to positions or account
+func (h *Handler) pushSnapshot(session *privateSession, arg wsArg) {
+	switch arg.Channel {
+	case "positions":
+		positions, err := h.tradingService.GetPositions(session.account.ID, models.ExchangeOKX)
+		if err != nil {
+			return
+		}
+		data := make([]gin.H, 0)
+		for _, pos := range positions {
+			if arg.matches(pos.Symbol) {
+				data = append(data, h.formatPosition(&pos))
+			}
+		}
+		h.push(session, arg, data)
+	case "account":
+		h.pushBalance(session, arg)
+	}
+}
+
+// pushOrderUpdate pushes an order event to the orders and orders-algo channels
+func (h *Handler) pushOrderUpdate(session *privateSession, event service.UserEvent) {
+	order := event.Order
+
+	// Conditional orders report to orders-algo; once triggered the fill also shows up in orders
+	if order.IsConditional() {
+		if arg, ok := session.subscription("orders-algo"); ok && arg.matches(order.Symbol) {
+			h.push(session, arg, []gin.H{h.formatAlgoOrder(order)})
+		}
+		if event.Trade == nil {
+			return
+		}
+	}
+
+	arg, ok := session.subscription("orders")
+	if !ok || !arg.matches(order.Symbol) {
+		return
+	}
+
+	data := h.formatOrder(order)
+	data["fillSz"] = "0"
+	data["fillPx"] = ""
+	data["tradeId"] = ""
+	data["fillTime"] = ""
+	data["fee"] = "0"
+	data["feeCcy"] = "USDT"
+	data["pnl"] = "0"
+	data["execType"] = ""
+	data["uTime"] = strconv.FormatInt(event.Time.UnixMilli(), 10)
+	if trade := event.Trade; trade != nil {
+		execType := "T"
+		if trade.IsMaker {
+			execType = "M"
+		}
+		data["fillSz"] = h.formatSz(order.Symbol, trade.Quantity)
+		data["fillPx"] = strconv.FormatFloat(trade.Price, 'f', 8, 64)
+		data["tradeId"] = strconv.Itoa(int(trade.ID))
+		data["fillTime"] = strconv.FormatInt(trade.ExecutedAt.UnixMilli(), 10)
+		data["fee"] = strconv.FormatFloat(-trade.Fee, 'f', 8, 64) // OKX reports charged fees as negative
+		data["pnl"] = strconv.FormatFloat(trade.RealizedPnL, 'f', 8, 64)
+		data["execType"] = execType
+	}
+
+	h.push(session, arg, []gin.H{data})
+}
+
+// pushAccountUpdate pushes position and balance changes
+func (h *Handler) pushAccountUpdate(session *privateSession, event service.UserEvent) {
+	if arg, ok := session.subscription("positions"); ok {
+		data := make([]gin.H, 0, len(event.Positions))
+		for _, pos := range event.Positions {
+			if !arg.matches(pos.Symbol) {
+				continue
+			}
+			if pos.Quantity > 0 {
+				if markPrice, err := h.priceService.GetPrice("okx", pos.Symbol); err == nil {
+					pos.MarkPrice = markPrice
+					pos.UnrealizedPnL = pos.CalculateUnrealizedPnL(markPrice)
+				}
+			}
+			data = append(data, h.formatPosition(&pos))
+		}
+		if len(data) > 0 {
+			h.push(session, arg, data)
+		}
+	}
+
+	if arg, ok := session.subscription("account"); ok {
+		h.pushBalance(session, arg)
+	}
+}
+
+func (h *Handler) pushBalance(session *privateSession, arg wsArg) {
+	balance, err := h.tradingService.GetBalance(session.account.ID, models.ExchangeOKX)
+	if err != nil {
+		return
+	}
+	h.push(session, arg, []gin.H{formatBalance(balance)})
+}
+
+// push sends channel data in OKX push format
+func (h *Handler) push(session *privateSession, arg wsArg, data []gin.H) {
+	pushArg := gin.H{
+		"channel": arg.Channel,
+		"uid":     strconv.Itoa(int(session.account.ID)),
+	}
+	if arg.InstType != "" {
+		pushArg["instType"] = arg.InstType
+	}
+	if arg.InstId != "" {
+		pushArg["instId"] = arg.InstId
+	}
+
+	session.write(gin.H{
+		"arg":  pushArg,
+		"data": data,
+	})
+}
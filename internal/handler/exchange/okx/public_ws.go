@@ -0,0 +1,163 @@
+package okx
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ccxt-simulator/internal/exchange"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// publicChannels lists the supported public channels
+var publicChannels = map[string]bool{
+	"tickers":    true,
+	"mark-price": true,
+}
+
+// publicSession is a single public WebSocket connection
+type publicSession struct {
+	conn    *websocket.Conn
+	connID  string
+	writeMu sync.Mutex
+
+	subs   map[wsArg]bool // channel + instId subscriptions
+	subsMu sync.RWMutex
+}
+
+func (s *publicSession) write(v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return s.conn.WriteJSON(v)
+}
+
+func (s *publicSession) writeError(code, msg string) {
+	s.write(gin.H{"event": "error", "code": code, "msg": msg, "connId": s.connID})
+}
+
+// subscribed checks whether a channel is subscribed for an instrument
+func (s *publicSession) subscribed(channel, instId string) bool {
+	s.subsMu.RLock()
+	defer s.subsMu.RUnlock()
+	return s.subs[wsArg{Channel: channel, InstId: instId}]
+}
+
+// PublicWebSocket handles GET /ws/v5/public
+func (h *Handler) PublicWebSocket(c *gin.Context) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	session := &publicSession{
+		conn:   conn,
+		connID: uuid.New().String()[:8],
+		subs:   make(map[wsArg]bool),
+	}
+
+	ticks := h.priceService.MarketData().Subscribe("okx")
+	defer h.priceService.MarketData().Unsubscribe("okx", ticks)
+
+	done := make(chan struct{})
+	defer close(done)
+	go h.pumpTicks(session, ticks, done)
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+
+		if string(message) == "ping" {
+			session.writeMu.Lock()
+			conn.WriteMessage(websocket.TextMessage, []byte("pong"))
+			session.writeMu.Unlock()
+			continue
+		}
+
+		var req wsRequest
+		if err := json.Unmarshal(message, &req); err != nil || (req.Op != "subscribe" && req.Op != "unsubscribe") {
+			session.writeError("60012", "Invalid request: "+string(message))
+			continue
+		}
+
+		for _, raw := range req.Args {
+			var arg wsArg
+			if err := json.Unmarshal(raw, &arg); err != nil || !publicChannels[arg.Channel] || arg.InstId == "" {
+				session.writeError("60018", "Wrong URL or channel:"+arg.Channel+",instId or instType:"+arg.InstId+" doesn't exist.")
+				continue
+			}
+			key := wsArg{Channel: arg.Channel, InstId: arg.InstId}
+
+			session.subsMu.Lock()
+			if req.Op == "subscribe" {
+				session.subs[key] = true
+			} else {
+				delete(session.subs, key)
+			}
+			session.subsMu.Unlock()
+
+			session.write(gin.H{"event": req.Op, "arg": key, "connId": session.connID})
+		}
+	}
+}
+
+// pumpTicks forwards live ticks to subscribed public channels
+func (h *Handler) pumpTicks(session *publicSession, ticks chan exchange.PriceUpdate, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case update := <-ticks:
+			instId := convertToOKXSymbol(update.Symbol)
+			if session.subscribed("tickers", instId) {
+				session.write(gin.H{
+					"arg":  gin.H{"channel": "tickers", "instId": instId},
+					"data": []gin.H{formatTicker(instId, update)},
+				})
+			}
+			if session.subscribed("mark-price", instId) {
+				session.write(gin.H{
+					"arg": gin.H{"channel": "mark-price", "instId": instId},
+					"data": []gin.H{
+						{
+							"instType": "SWAP",
+							"instId":   instId,
+							"markPx":   strconv.FormatFloat(update.Price, 'f', 8, 64),
+							"ts":       strconv.FormatInt(update.Timestamp, 10),
+						},
+					},
+				})
+			}
+		}
+	}
+}
+
+// formatTicker formats a tick for the tickers channel
+// 24h statistics are not tracked, so they report the current price and zero volume
+func formatTicker(instId string, update exchange.PriceUpdate) gin.H {
+	last := strconv.FormatFloat(update.Price, 'f', 8, 64)
+	return gin.H{
+		"instType":  "SWAP",
+		"instId":    instId,
+		"last":      last,
+		"lastSz":    "0",
+		"askPx":     strconv.FormatFloat(update.BestAsk(), 'f', 8, 64),
+		"askSz":     "0",
+		"bidPx":     strconv.FormatFloat(update.BestBid(), 'f', 8, 64),
+		"bidSz":     "0",
+		"open24h":   last,
+		"high24h":   last,
+		"low24h":    last,
+		"sodUtc0":   last,
+		"sodUtc8":   last,
+		"volCcy24h": "0",
+		"vol24h":    "0",
+		"ts":        strconv.FormatInt(update.Timestamp, 10),
+	}
+}
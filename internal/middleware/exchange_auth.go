@@ -1,14 +1,19 @@
 package middleware
 
 import (
+	"bytes"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ccxt-simulator/internal/models"
 	"github.com/ccxt-simulator/internal/service"
@@ -21,6 +26,8 @@ const (
 	ContextKeyAccount = "exchange_account"
 	// ContextKeyAPISecret is the key for API secret in gin context
 	ContextKeyAPISecret = "api_secret"
+	// ContextKeySigner is the key for the recovered wallet signer address in gin context
+	ContextKeySigner = "wallet_signer"
 )
 
 // ExchangeAuthConfig holds configuration for exchange authentication
@@ -74,6 +81,18 @@ func BinanceAuthMiddleware(accountService *service.AccountService, aesKey string
 			return
 		}
 
+		// Verify timestamp, recvWindow and signature (USER_STREAM endpoints only need the API key)
+		if !account.SkipSignatureCheck && !binanceKeyOnlyPaths[c.Request.URL.Path] {
+			if code, msg := verifyBinanceRequest(c, apiSecret); code != 0 {
+				c.JSON(400, gin.H{
+					"code": code,
+					"msg":  msg,
+				})
+				c.Abort()
+				return
+			}
+		}
+
 		// Store account in context
 		c.Set(ContextKeyAccount, account)
 		c.Set(ContextKeyAPISecret, apiSecret)
@@ -81,6 +100,95 @@ func BinanceAuthMiddleware(accountService *service.AccountService, aesKey string
 	}
 }
 
+// binanceKeyOnlyPaths are Binance endpoints secured by API key alone (no signature)
+var binanceKeyOnlyPaths = map[string]bool{
+	"/fapi/v1/listenKey": true,
+}
+
+const (
+	// binanceDefaultRecvWindow is the recvWindow applied when the request omits it
+	binanceDefaultRecvWindow = 5000
+	// binanceMaxRecvWindow is the largest recvWindow Binance accepts
+	binanceMaxRecvWindow = 60000
+	// binanceMaxClockAhead is how far a timestamp may run ahead of the server clock
+	binanceMaxClockAhead = 1000
+)
+
+// verifyBinanceRequest verifies timestamp/recvWindow and the HMAC-SHA256 signature for Binance
+// The signed payload is the raw query string concatenated with the raw form body, minus the signature parameter
+// On failure it returns a Binance error code and message
+func verifyBinanceRequest(c *gin.Context, apiSecret string) (int, string) {
+	var body string
+	if c.Request.Body != nil {
+		bodyBytes, _ := io.ReadAll(c.Request.Body)
+		c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+		body = string(bodyBytes)
+	}
+
+	query, querySig := stripBinanceSignature(c.Request.URL.RawQuery)
+	body, bodySig := stripBinanceSignature(body)
+	signature := querySig
+	if signature == "" {
+		signature = bodySig
+	}
+
+	params, _ := url.ParseQuery(query + "&" + body)
+
+	timestamp, err := strconv.ParseInt(params.Get("timestamp"), 10, 64)
+	if err != nil {
+		return -1102, "Mandatory parameter 'timestamp' was not sent, was empty/null, or malformed."
+	}
+
+	recvWindow := int64(binanceDefaultRecvWindow)
+	if raw := params.Get("recvWindow"); raw != "" {
+		recvWindow, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || recvWindow <= 0 || recvWindow > binanceMaxRecvWindow {
+			return -1131, "recvWindow must be less than 60000"
+		}
+	}
+
+	now := time.Now().UnixMilli()
+	if timestamp > now+binanceMaxClockAhead {
+		return -1021, "Timestamp for this request was 1000ms ahead of the server's time."
+	}
+	if now-timestamp > recvWindow {
+		return -1021, "Timestamp for this request is outside of the recvWindow."
+	}
+
+	if signature == "" {
+		return -1102, "Mandatory parameter 'signature' was not sent, was empty/null, or malformed."
+	}
+
+	mac := hmac.New(sha256.New, []byte(apiSecret))
+	mac.Write([]byte(query + body))
+	expectedSig := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(strings.ToLower(signature)), []byte(expectedSig)) {
+		return -1022, "Signature for this request is not valid."
+	}
+
+	return 0, ""
+}
+
+// stripBinanceSignature removes the signature parameter from a raw urlencoded string and returns it separately
+func stripBinanceSignature(raw string) (string, string) {
+	if raw == "" {
+		return "", ""
+	}
+
+	var signature string
+	parts := strings.Split(raw, "&")
+	kept := parts[:0]
+	for _, part := range parts {
+		if strings.HasPrefix(part, "signature=") {
+			signature = strings.TrimPrefix(part, "signature=")
+			continue
+		}
+		kept = append(kept, part)
+	}
+
+	return strings.Join(kept, "&"), signature
+}
+
 // OKXAuthMiddleware creates authentication middleware for OKX API
 func OKXAuthMiddleware(accountService *service.AccountService, aesKey string) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -190,13 +298,68 @@ func verifyOKXSignature(c *gin.Context, timestamp, apiSecret string) bool {
 	}
 
 	prehash := timestamp + method + requestPath + body
+	return hmac.Equal([]byte(sign), []byte(okxSign(prehash, apiSecret)))
+}
 
-	// Calculate signature
+// okxSign calculates the OKX HMAC-SHA256 + Base64 signature of a prehash string
+func okxSign(prehash, apiSecret string) string {
 	mac := hmac.New(sha256.New, []byte(apiSecret))
 	mac.Write([]byte(prehash))
-	expectedSig := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
 
-	return hmac.Equal([]byte(sign), []byte(expectedSig))
+// okxLoginWindow is how far a WebSocket login timestamp may be from the server clock
+const okxLoginWindow = 30 * time.Second
+
+// okxLoginTimestampValid reports whether a login timestamp (Unix seconds) is within okxLoginWindow of now
+func okxLoginTimestampValid(timestamp string, now time.Time) bool {
+	seconds, err := strconv.ParseFloat(timestamp, 64)
+	if err != nil {
+		return false
+	}
+	skew := now.Sub(time.UnixMilli(int64(seconds * 1000)))
+	return skew <= okxLoginWindow && skew >= -okxLoginWindow
+}
+
+// OKXLoginFunc authenticates an OKX WebSocket login op
+// On failure it returns a nil account with an OKX WebSocket error code and message
+type OKXLoginFunc func(apiKey, passphrase, timestamp, sign string) (*models.Account, string, string)
+
+// OKXWebSocketAuth creates the login verifier for the OKX private WebSocket
+// The signature is verified like REST calls, over timestamp + "GET" + "/users/self/verify"
+func OKXWebSocketAuth(accountService *service.AccountService, aesKey string) OKXLoginFunc {
+	return func(apiKey, passphrase, timestamp, sign string) (*models.Account, string, string) {
+		if apiKey == "" || passphrase == "" || timestamp == "" || sign == "" {
+			return nil, "60009", "Login failed."
+		}
+		if !okxLoginTimestampValid(timestamp, time.Now()) {
+			return nil, "60006", "Timestamp request expired"
+		}
+
+		account, err := accountService.GetAccountByAPIKey(apiKey)
+		if err != nil || account.ExchangeType != models.ExchangeOKX {
+			return nil, "60005", "Invalid OK-ACCESS-KEY"
+		}
+
+		apiSecret, err := crypto.DecryptAES(account.APISecretEncrypted, aesKey)
+		if err != nil {
+			return nil, "60009", "Login failed."
+		}
+		storedPassphrase, err := crypto.DecryptAES(account.PassphraseEncrypted, aesKey)
+		if err != nil {
+			return nil, "60009", "Login failed."
+		}
+		if passphrase != storedPassphrase {
+			return nil, "60024", "Wrong passphrase"
+		}
+
+		prehash := timestamp + "GET" + "/users/self/verify"
+		if !hmac.Equal([]byte(sign), []byte(okxSign(prehash, apiSecret))) {
+			return nil, "60007", "Invalid sign"
+		}
+
+		return account, "", ""
+	}
 }
 
 // BybitAuthMiddleware creates authentication middleware for Bybit API
@@ -217,33 +380,15 @@ func BybitAuthMiddleware(accountService *service.AccountService, aesKey string)
 			return
 		}
 
-		// Find account by API key
-		account, err := accountService.GetAccountByAPIKey(apiKey)
-		if err != nil {
-			c.JSON(401, gin.H{
-				"retCode": 10003,
-				"retMsg":  "Invalid apiKey.",
-			})
-			c.Abort()
-			return
-		}
-
-		// Verify this is a Bybit account
-		if account.ExchangeType != models.ExchangeBybit {
-			c.JSON(401, gin.H{
-				"retCode": 10003,
-				"retMsg":  "API key is not for Bybit.",
-			})
-			c.Abort()
-			return
-		}
-
-		// Decrypt API secret
-		apiSecret, err := crypto.DecryptAES(account.APISecretEncrypted, aesKey)
-		if err != nil {
-			c.JSON(500, gin.H{
-				"retCode": 10000,
-				"retMsg":  "Internal error.",
+		account, apiSecret, retCode, retMsg := lookupBybitAccount(accountService, aesKey, apiKey)
+		if account == nil {
+			status := 401
+			if retCode == 10000 {
+				status = 500
+			}
+			c.JSON(status, gin.H{
+				"retCode": retCode,
+				"retMsg":  retMsg,
 			})
 			c.Abort()
 			return
@@ -266,6 +411,59 @@ func BybitAuthMiddleware(accountService *service.AccountService, aesKey string)
 	}
 }
 
+// lookupBybitAccount finds a Bybit account by API key and decrypts its secret
+// On failure it returns a nil account with a Bybit retCode and message
+func lookupBybitAccount(accountService *service.AccountService, aesKey, apiKey string) (*models.Account, string, int, string) {
+	// Find account by API key
+	account, err := accountService.GetAccountByAPIKey(apiKey)
+	if err != nil {
+		return nil, "", 10003, "Invalid apiKey."
+	}
+
+	// Verify this is a Bybit account
+	if account.ExchangeType != models.ExchangeBybit {
+		return nil, "", 10003, "API key is not for Bybit."
+	}
+
+	// Decrypt API secret
+	apiSecret, err := crypto.DecryptAES(account.APISecretEncrypted, aesKey)
+	if err != nil {
+		return nil, "", 10000, "Internal error."
+	}
+
+	return account, apiSecret, 0, ""
+}
+
+// BybitLoginFunc authenticates a Bybit private WebSocket auth op
+// On failure it returns a nil account with the ret_msg to report
+type BybitLoginFunc func(apiKey string, expires int64, signature string) (*models.Account, string)
+
+// BybitWebSocketAuth creates the auth verifier for the Bybit private WebSocket
+// The signature is hex(HMAC-SHA256("GET/realtime" + expires)) and expires must be in the future
+func BybitWebSocketAuth(accountService *service.AccountService, aesKey string) BybitLoginFunc {
+	return func(apiKey string, expires int64, signature string) (*models.Account, string) {
+		if apiKey == "" || signature == "" {
+			return nil, "Params Error"
+		}
+		if expires <= time.Now().UnixMilli() {
+			return nil, "Request expired"
+		}
+
+		account, apiSecret, _, _ := lookupBybitAccount(accountService, aesKey, apiKey)
+		if account == nil {
+			return nil, "Request not authorized"
+		}
+
+		mac := hmac.New(sha256.New, []byte(apiSecret))
+		mac.Write([]byte(fmt.Sprintf("GET/realtime%d", expires)))
+		if !hmac.Equal([]byte(signature), []byte(hex.EncodeToString(mac.Sum(nil)))) {
+			return nil, "Request not authorized"
+		}
+
+		return account, ""
+	}
+}
+
 // verifyBybitSignature verifies the HMAC-SHA256 signature for Bybit
 func verifyBybitSignature(c *gin.Context, apiKey, timestamp, recvWindow, apiSecret string) bool {
 	sign := c.GetHeader("X-BAPI-SIGN")
@@ -341,6 +539,15 @@ func GetAPISecret(c *gin.Context) string {
 	return secret.(string)
 }
 
+// GetSigner retrieves the recovered wallet signer address from gin context
+func GetSigner(c *gin.Context) string {
+	signer, exists := c.Get(ContextKeySigner)
+	if !exists {
+		return ""
+	}
+	return signer.(string)
+}
+
 // BitgetAuthMiddleware creates authentication middleware for Bitget API
 func BitgetAuthMiddleware(accountService *service.AccountService, aesKey string) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -452,39 +659,90 @@ func verifyBitgetSignature(c *gin.Context, timestamp, apiSecret string) bool {
 }
 
 // HyperliquidAuthMiddleware creates authentication middleware for Hyperliquid API
+// Requests are authenticated by the wallet signature over the action: L1 actions are signed as an
+// EIP-712 phantom agent over the msgpack action hash, user-signed actions (approveAgent) as EIP-712
+// typed data. The signer must be the account wallet or one of its approved agent wallets
 func HyperliquidAuthMiddleware(accountService *service.AccountService, aesKey string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Hyperliquid uses wallet signature authentication
-		// For simulation, we use a simplified API key approach
+	nonces := newHyperliquidNonceStore()
 
-		// Get API key from header or query
-		apiKey := c.GetHeader("HL-API-KEY")
-		if apiKey == "" {
-			apiKey = c.Query("apiKey")
+	return func(c *gin.Context) {
+		// Read and restore request body
+		var bodyBytes []byte
+		if c.Request.Body != nil {
+			bodyBytes, _ = io.ReadAll(c.Request.Body)
+			c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 		}
 
-		if apiKey == "" {
-			c.JSON(401, gin.H{"error": "Missing API key"})
+		var req hyperliquidExchangeRequest
+		if err := json.Unmarshal(bodyBytes, &req); err != nil {
+			c.JSON(400, gin.H{"error": "Failed to deserialize the JSON body"})
 			c.Abort()
 			return
 		}
 
-		// Find account by API key
-		account, err := accountService.GetAccountByAPIKey(apiKey)
-		if err != nil {
-			c.JSON(401, gin.H{"error": "Invalid API key"})
-			c.Abort()
-			return
+		var account *models.Account
+		var signer string
+
+		if req.Signature == nil {
+			// Unsigned requests identify the account by API key, allowed only when signing is relaxed
+			apiKey := c.GetHeader("HL-API-KEY")
+			if apiKey == "" {
+				apiKey = c.Query("apiKey")
+			}
+			if apiKey == "" {
+				c.JSON(401, gin.H{"error": "Missing signature"})
+				c.Abort()
+				return
+			}
+
+			found, err := accountService.GetAccountByAPIKey(strings.ToLower(apiKey))
+			if err != nil || found.ExchangeType != models.ExchangeHyperliquid {
+				c.JSON(401, gin.H{"error": "Invalid API key"})
+				c.Abort()
+				return
+			}
+			if !found.SkipSignatureCheck {
+				c.JSON(401, gin.H{"error": "Missing signature"})
+				c.Abort()
+				return
+			}
+			account = found
+			signer = found.APIKey
+		} else {
+			var errMsg string
+			account, signer, errMsg = verifyHyperliquidRequest(accountService, &req)
+			if account == nil {
+				c.JSON(401, gin.H{"error": errMsg})
+				c.Abort()
+				return
+			}
+
+			if req.ExpiresAfter != nil && time.Now().UnixMilli() > *req.ExpiresAfter {
+				c.JSON(400, gin.H{"error": "Action expired"})
+				c.Abort()
+				return
+			}
+
+			// Reject replays only once the signature is known to be valid
+			if errMsg := nonces.use(signer, req.Nonce, time.Now()); errMsg != "" {
+				c.JSON(400, gin.H{"error": errMsg})
+				c.Abort()
+				return
+			}
 		}
 
-		// Verify this is a Hyperliquid account
-		if account.ExchangeType != models.ExchangeHyperliquid {
-			c.JSON(401, gin.H{"error": "API key is not for Hyperliquid"})
-			c.Abort()
-			return
+		// Trading on behalf of a vault/sub-account requires both accounts to belong to the same user
+		if req.VaultAddress != nil && *req.VaultAddress != "" {
+			vault, err := accountService.GetAccountByAPIKey(strings.ToLower(*req.VaultAddress))
+			if err != nil || vault.ExchangeType != models.ExchangeHyperliquid || vault.UserID != account.UserID {
+				c.JSON(401, gin.H{"error": "Vault not registered: " + *req.VaultAddress})
+				c.Abort()
+				return
+			}
+			account = vault
 		}
 
-		// Decrypt API secret (used as wallet private key in real implementation)
+		// Decrypt API secret (the account wallet private key)
 		apiSecret, err := crypto.DecryptAES(account.APISecretEncrypted, aesKey)
 		if err != nil {
 			c.JSON(500, gin.H{"error": "Internal error"})
@@ -492,9 +750,62 @@ func HyperliquidAuthMiddleware(accountService *service.AccountService, aesKey st
 			return
 		}
 
-		// Store account in context
+		// Store account and signer in context
 		c.Set(ContextKeyAccount, account)
 		c.Set(ContextKeyAPISecret, apiSecret)
+		c.Set(ContextKeySigner, signer)
 		c.Next()
 	}
 }
+
+// verifyHyperliquidRequest recovers the signer of a signed /exchange request and resolves its account
+// On failure it returns a nil account with the error message to report
+func verifyHyperliquidRequest(accountService *service.AccountService, req *hyperliquidExchangeRequest) (*models.Account, string, string) {
+	actionType := hyperliquidActionType(req.Action)
+	if actionType == "" {
+		return nil, "", "Missing action type"
+	}
+
+	// User-signed actions are signed by the account wallet itself
+	if fields, ok := hyperliquidUserSignedActions[actionType]; ok {
+		digest, err := hyperliquidUserSignedDigest(actionType, fields, req.Action)
+		if err != nil {
+			return nil, "", "Invalid action: " + err.Error()
+		}
+		signer, err := recoverHyperliquidSigner(digest, req.Signature)
+		if err != nil {
+			return nil, "", "Invalid signature"
+		}
+		account, agent, err := accountService.GetAccountByWallet(signer)
+		if err != nil || agent != nil {
+			return nil, "", fmt.Sprintf("User %s does not exist.", signer)
+		}
+		return account, signer, ""
+	}
+
+	// L1 actions are signed as a phantom agent; the source differs between mainnet ("a") and testnet ("b") clients
+	connectionID, err := hyperliquidActionHash(req)
+	if err != nil {
+		return nil, "", "Invalid action: " + err.Error()
+	}
+
+	var firstSigner string
+	for _, source := range []string{"a", "b"} {
+		digest, err := hyperliquidL1Digest(connectionID, source)
+		if err != nil {
+			return nil, "", "Invalid action: " + err.Error()
+		}
+		signer, err := recoverHyperliquidSigner(digest, req.Signature)
+		if err != nil {
+			return nil, "", "Invalid signature"
+		}
+		if firstSigner == "" {
+			firstSigner = signer
+		}
+		if account, _, err := accountService.GetAccountByWallet(signer); err == nil {
+			return account, signer, ""
+		}
+	}
+
+	return nil, "", fmt.Sprintf("User or API Wallet %s does not exist.", firstSigner)
+}
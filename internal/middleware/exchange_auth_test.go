@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func binanceTestContext(method, query, body string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(method, "/fapi/v1/order?"+query, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return c
+}
+
+func binanceTestSign(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyBinanceRequest(t *testing.T) {
+	const secret = "test-secret"
+	now := time.Now().UnixMilli()
+
+	// Query string concatenated with the body, signature appended to the body
+	query := "symbol=BTCUSDT&side=BUY"
+	body := fmt.Sprintf("quantity=1&timestamp=%d", now)
+	c := binanceTestContext(http.MethodPost, query, body+"&signature="+binanceTestSign(secret, query+body))
+	code, _ := verifyBinanceRequest(c, secret)
+	assert.Equal(t, 0, code)
+
+	// The body stays readable for the handler
+	rest, _ := io.ReadAll(c.Request.Body)
+	assert.Contains(t, string(rest), "quantity=1")
+
+	// Tampered parameters
+	c = binanceTestContext(http.MethodPost, query, "quantity=2&timestamp="+fmt.Sprint(now)+"&signature="+binanceTestSign(secret, query+body))
+	code, _ = verifyBinanceRequest(c, secret)
+	assert.Equal(t, -1022, code)
+
+	// Stale timestamp
+	stale := fmt.Sprintf("symbol=BTCUSDT&timestamp=%d", now-10000)
+	c = binanceTestContext(http.MethodGet, stale+"&signature="+binanceTestSign(secret, stale), "")
+	code, _ = verifyBinanceRequest(c, secret)
+	assert.Equal(t, -1021, code)
+
+	// A wider recvWindow accepts the same timestamp
+	widened := stale + "&recvWindow=20000"
+	c = binanceTestContext(http.MethodGet, widened+"&signature="+binanceTestSign(secret, widened), "")
+	code, _ = verifyBinanceRequest(c, secret)
+	assert.Equal(t, 0, code)
+
+	// Missing signature
+	unsigned := fmt.Sprintf("symbol=BTCUSDT&timestamp=%d", now)
+	c = binanceTestContext(http.MethodGet, unsigned, "")
+	code, _ = verifyBinanceRequest(c, secret)
+	assert.Equal(t, -1102, code)
+}
+
+func TestOKXLoginTimestamp(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	assert.True(t, okxLoginTimestampValid("1700000000", now))
+	assert.True(t, okxLoginTimestampValid("1699999975.5", now))
+	assert.True(t, okxLoginTimestampValid("1700000029", now))
+
+	// Outside the 30s window on either side
+	assert.False(t, okxLoginTimestampValid("1699999960", now))
+	assert.False(t, okxLoginTimestampValid("1700000031", now))
+
+	// Malformed
+	assert.False(t, okxLoginTimestampValid("not-a-time", now))
+}
+
+func TestHyperliquidL1Signature(t *testing.T) {
+	// Vector from the Hyperliquid Python SDK signing tests
+	const wallet = "0x14791697260e4c9a71f18484c9f997b308e59325"
+	req := &hyperliquidExchangeRequest{
+		Action: []byte(`{"type":"dummy","num":100000000000}`),
+		Nonce:  0,
+	}
+	connectionID, err := hyperliquidActionHash(req)
+	assert.NoError(t, err)
+
+	cases := []struct {
+		source string
+		sig    hyperliquidSignature
+	}{
+		{"a", hyperliquidSignature{R: "0x53749d5b30552aeb2fca34b530185976545bb22d0b3ce6f62e31be961a59298", S: "0x755c40ba9bf05223521753995abb2f73ab3229be8ec921f350cb447e384d8ed8", V: 27}},
+		{"b", hyperliquidSignature{R: "0x542af61ef1f429707e3c76c5293c80d01f74ef853e34b76efffcb57e574f9510", S: "0x17b8b32f086e8cdede991f1e2c529f5dd5297cbe8128500e00cbaf766204a613", V: 28}},
+	}
+	for _, tc := range cases {
+		digest, err := hyperliquidL1Digest(connectionID, tc.source)
+		assert.NoError(t, err)
+		signer, err := recoverHyperliquidSigner(digest, &tc.sig)
+		assert.NoError(t, err)
+		assert.Equal(t, wallet, signer, tc.source)
+	}
+}
+
+func TestHyperliquidNonceStore(t *testing.T) {
+	store := newHyperliquidNonceStore()
+	now := time.Now()
+	base := now.UnixMilli()
+
+	assert.Empty(t, store.use("0xabc", base, now))
+	assert.NotEmpty(t, store.use("0xabc", base, now), "replayed nonce")
+	assert.Empty(t, store.use("0xdef", base, now), "nonces are per signer")
+	assert.NotEmpty(t, store.use("0xabc", now.Add(-3*24*time.Hour).UnixMilli(), now), "stale nonce")
+
+	for i := int64(1); i < hyperliquidNonceHistory; i++ {
+		assert.Empty(t, store.use("0xabc", base+i, now))
+	}
+	// History is full: anything at or below the smallest remembered nonce is rejected
+	assert.NotEmpty(t, store.use("0xabc", base-1, now))
+	assert.Empty(t, store.use("0xabc", base+hyperliquidNonceHistory, now))
+}
@@ -0,0 +1,213 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ccxt-simulator/pkg/ethsig"
+	"github.com/ccxt-simulator/pkg/msgpack"
+)
+
+const (
+	// hyperliquidNonceHistory is how many of the highest nonces are remembered per signer
+	hyperliquidNonceHistory = 100
+	// hyperliquidNonceMaxAge and hyperliquidNonceMaxAhead bound nonces (ms timestamps) around server time
+	hyperliquidNonceMaxAge   = 2 * 24 * time.Hour
+	hyperliquidNonceMaxAhead = 24 * time.Hour
+)
+
+// hyperliquidL1Domain is the EIP-712 domain of L1 (trading) actions, signed via a phantom agent
+var hyperliquidL1Domain = ethsig.Domain{
+	Name:              "Exchange",
+	Version:           "1",
+	ChainID:           1337,
+	VerifyingContract: "0x0000000000000000000000000000000000000000",
+}
+
+var hyperliquidAgentFields = []ethsig.Field{
+	{Name: "source", Type: "string"},
+	{Name: "connectionId", Type: "bytes32"},
+}
+
+// hyperliquidUserSignedActions lists actions signed directly by the account wallet with their EIP-712 fields
+// Agent wallets may not sign these
+var hyperliquidUserSignedActions = map[string][]ethsig.Field{
+	"approveAgent": {
+		{Name: "hyperliquidChain", Type: "string"},
+		{Name: "agentAddress", Type: "address"},
+		{Name: "agentName", Type: "string"},
+		{Name: "nonce", Type: "uint64"},
+	},
+}
+
+// hyperliquidSignature is the {r, s, v} signature object of an /exchange request
+type hyperliquidSignature struct {
+	R string `json:"r"`
+	S string `json:"s"`
+	V int    `json:"v"`
+}
+
+// hyperliquidExchangeRequest is the signed envelope of an /exchange request
+type hyperliquidExchangeRequest struct {
+	Action       json.RawMessage       `json:"action"`
+	Nonce        int64                 `json:"nonce"`
+	Signature    *hyperliquidSignature `json:"signature"`
+	VaultAddress *string               `json:"vaultAddress"`
+	ExpiresAfter *int64                `json:"expiresAfter"`
+}
+
+// hyperliquidActionType returns the "type" of an action object
+func hyperliquidActionType(action json.RawMessage) string {
+	var header struct {
+		Type string `json:"type"`
+	}
+	_ = json.Unmarshal(action, &header)
+	return header.Type
+}
+
+// hyperliquidActionHash returns keccak(msgpack(action) || nonce || vault || expiresAfter), the phantom agent connectionId
+func hyperliquidActionHash(req *hyperliquidExchangeRequest) ([]byte, error) {
+	packed, err := msgpack.FromJSON(req.Action)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(packed)
+	binary.Write(&buf, binary.BigEndian, uint64(req.Nonce))
+
+	if req.VaultAddress == nil || *req.VaultAddress == "" {
+		buf.WriteByte(0x00)
+	} else {
+		vault, err := ethsig.AddressBytes(*req.VaultAddress)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteByte(0x01)
+		buf.Write(vault)
+	}
+
+	if req.ExpiresAfter != nil {
+		buf.WriteByte(0x00)
+		binary.Write(&buf, binary.BigEndian, uint64(*req.ExpiresAfter))
+	}
+
+	return ethsig.Keccak256(buf.Bytes()), nil
+}
+
+// hyperliquidL1Digest returns the EIP-712 digest of the phantom agent for an L1 action
+// source is "a" for mainnet and "b" for testnet clients
+func hyperliquidL1Digest(connectionID []byte, source string) ([]byte, error) {
+	structHash, err := ethsig.HashStruct("Agent", hyperliquidAgentFields, []interface{}{source, connectionID})
+	if err != nil {
+		return nil, err
+	}
+	return ethsig.TypedDataHash(hyperliquidL1Domain, structHash)
+}
+
+// hyperliquidUserSignedDigest returns the EIP-712 digest of a user-signed action
+// The domain chain comes from the action's signatureChainId; missing string fields sign as ""
+func hyperliquidUserSignedDigest(actionType string, fields []ethsig.Field, action json.RawMessage) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(action))
+	dec.UseNumber()
+	var values map[string]interface{}
+	if err := dec.Decode(&values); err != nil {
+		return nil, err
+	}
+
+	chainIDHex, _ := values["signatureChainId"].(string)
+	chainID, err := strconv.ParseInt(strings.TrimPrefix(chainIDHex, "0x"), 16, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signatureChainId")
+	}
+
+	ordered := make([]interface{}, len(fields))
+	for i, f := range fields {
+		switch f.Type {
+		case "uint64":
+			n, _ := values[f.Name].(json.Number)
+			u, err := strconv.ParseUint(n.String(), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s", f.Name)
+			}
+			ordered[i] = u
+		default:
+			s, _ := values[f.Name].(string)
+			ordered[i] = s
+		}
+	}
+
+	primaryType := "HyperliquidTransaction:" + strings.ToUpper(actionType[:1]) + actionType[1:]
+	structHash, err := ethsig.HashStruct(primaryType, fields, ordered)
+	if err != nil {
+		return nil, err
+	}
+
+	return ethsig.TypedDataHash(ethsig.Domain{
+		Name:              "HyperliquidSignTransaction",
+		Version:           "1",
+		ChainID:           chainID,
+		VerifyingContract: "0x0000000000000000000000000000000000000000",
+	}, structHash)
+}
+
+// recoverHyperliquidSigner recovers the signer address of a digest from an {r, s, v} signature
+func recoverHyperliquidSigner(digest []byte, sig *hyperliquidSignature) (string, error) {
+	r, ok := new(big.Int).SetString(strings.TrimPrefix(sig.R, "0x"), 16)
+	if !ok {
+		return "", ethsig.ErrInvalidSignature
+	}
+	s, ok := new(big.Int).SetString(strings.TrimPrefix(sig.S, "0x"), 16)
+	if !ok {
+		return "", ethsig.ErrInvalidSignature
+	}
+	return ethsig.RecoverAddress(digest, r, s, sig.V)
+}
+
+// hyperliquidNonceStore rejects replayed nonces, keeping the highest nonces seen per signer
+// Like Hyperliquid, a nonce must be unused, larger than the smallest remembered nonce once
+// the history is full, and within the allowed window around server time
+type hyperliquidNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string][]int64 // signer -> ascending nonces
+}
+
+func newHyperliquidNonceStore() *hyperliquidNonceStore {
+	return &hyperliquidNonceStore{nonces: make(map[string][]int64)}
+}
+
+// use records the nonce for the signer, returning an error message if it is not acceptable
+func (s *hyperliquidNonceStore) use(signer string, nonce int64, now time.Time) string {
+	if nonce <= now.Add(-hyperliquidNonceMaxAge).UnixMilli() || nonce >= now.Add(hyperliquidNonceMaxAhead).UnixMilli() {
+		return fmt.Sprintf("Invalid nonce: %d is outside the allowed time window", nonce)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := s.nonces[signer]
+	i := sort.Search(len(seen), func(i int) bool { return seen[i] >= nonce })
+	if i < len(seen) && seen[i] == nonce {
+		return fmt.Sprintf("Invalid nonce: %d was already used", nonce)
+	}
+	if len(seen) >= hyperliquidNonceHistory && i == 0 {
+		return fmt.Sprintf("Invalid nonce: %d is too low", nonce)
+	}
+
+	seen = append(seen, 0)
+	copy(seen[i+1:], seen[i:])
+	seen[i] = nonce
+	if len(seen) > hyperliquidNonceHistory {
+		seen = seen[1:]
+	}
+	s.nonces[signer] = seen
+	return ""
+}
@@ -25,24 +25,38 @@ const (
 	MarginModeIsolated MarginMode = "isolated"
 )
 
+// SlippageModel represents how taker fills are priced beyond the best quote
+type SlippageModel string
+
+const (
+	SlippageNone   SlippageModel = "none"   // Fill at the best bid/ask
+	SlippageFixed  SlippageModel = "fixed"  // Move the fill SlippageBps against the taker
+	SlippageSpread SlippageModel = "spread" // Move the fill half the quoted spread against the taker
+	SlippageImpact SlippageModel = "impact" // Move the fill SlippageBps per SlippageDepth of order notional
+)
+
 // Account represents a simulated exchange account
 type Account struct {
-	ID                   uint           `gorm:"primaryKey" json:"id"`
-	UserID               uint           `gorm:"index;not null" json:"user_id"`
-	ExchangeType         ExchangeType   `gorm:"size:20;not null" json:"exchange_type"`
-	APIKey               string         `gorm:"uniqueIndex;size:100;not null" json:"api_key"`
-	APISecretEncrypted   string         `gorm:"size:255;not null" json:"-"`
-	PassphraseEncrypted  string         `gorm:"size:255" json:"-"` // Only for OKX
-	BalanceUSDT          float64        `gorm:"type:decimal(20,8);default:0" json:"balance_usdt"`
-	InitialBalance       float64        `gorm:"type:decimal(20,8);default:0" json:"initial_balance"`
-	MarginMode           MarginMode     `gorm:"size:20;default:'cross'" json:"margin_mode"`
-	HedgeMode            bool           `gorm:"default:false" json:"hedge_mode"`
-	DefaultLeverage      int            `gorm:"default:20" json:"default_leverage"`
-	MakerFeeRate         float64        `gorm:"type:decimal(10,6);default:0.0002" json:"maker_fee_rate"`
-	TakerFeeRate         float64        `gorm:"type:decimal(10,6);default:0.0004" json:"taker_fee_rate"`
-	CreatedAt            time.Time      `json:"created_at"`
-	UpdatedAt            time.Time      `json:"updated_at"`
-	DeletedAt            gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                  uint           `gorm:"primaryKey" json:"id"`
+	UserID              uint           `gorm:"index;not null" json:"user_id"`
+	ExchangeType        ExchangeType   `gorm:"size:20;not null" json:"exchange_type"`
+	APIKey              string         `gorm:"uniqueIndex;size:100;not null" json:"api_key"`
+	APISecretEncrypted  string         `gorm:"size:255;not null" json:"-"`
+	PassphraseEncrypted string         `gorm:"size:255" json:"-"` // Only for OKX
+	BalanceUSDT         float64        `gorm:"type:decimal(20,8);default:0" json:"balance_usdt"`
+	InitialBalance      float64        `gorm:"type:decimal(20,8);default:0" json:"initial_balance"`
+	MarginMode          MarginMode     `gorm:"size:20;default:'cross'" json:"margin_mode"`
+	HedgeMode           bool           `gorm:"default:false" json:"hedge_mode"`
+	DefaultLeverage     int            `gorm:"default:20" json:"default_leverage"`
+	MakerFeeRate        float64        `gorm:"type:decimal(10,6);default:0.0002" json:"maker_fee_rate"`
+	TakerFeeRate        float64        `gorm:"type:decimal(10,6);default:0.0004" json:"taker_fee_rate"`
+	SlippageModel       SlippageModel  `gorm:"size:20;default:'fixed'" json:"slippage_model"`
+	SlippageBps         float64        `gorm:"type:decimal(10,4);default:1" json:"slippage_bps"`
+	SlippageDepth       float64        `gorm:"type:decimal(20,2);default:1000000" json:"slippage_depth"` // USDT notional, impact model only
+	SkipSignatureCheck  bool           `gorm:"default:false" json:"skip_signature_check"`                // Debug only: accept unsigned Binance/Hyperliquid requests
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relations
 	User      User       `gorm:"foreignKey:UserID" json:"-"`
@@ -57,18 +71,22 @@ func (Account) TableName() string {
 
 // AccountResponse is the response structure for account (with decrypted secret)
 type AccountResponse struct {
-	ID              uint         `json:"id"`
-	ExchangeType    ExchangeType `json:"exchange_type"`
-	APIKey          string       `json:"api_key"`
-	APISecret       string       `json:"api_secret,omitempty"`
-	Passphrase      string       `json:"passphrase,omitempty"`
-	BalanceUSDT     float64      `json:"balance_usdt"`
-	InitialBalance  float64      `json:"initial_balance"`
-	MarginMode      MarginMode   `json:"margin_mode"`
-	HedgeMode       bool         `json:"hedge_mode"`
-	DefaultLeverage int          `json:"default_leverage"`
-	MakerFeeRate    float64      `json:"maker_fee_rate"`
-	TakerFeeRate    float64      `json:"taker_fee_rate"`
-	EndpointURL     string       `json:"endpoint_url"`
-	CreatedAt       time.Time    `json:"created_at"`
+	ID                 uint          `json:"id"`
+	ExchangeType       ExchangeType  `json:"exchange_type"`
+	APIKey             string        `json:"api_key"`
+	APISecret          string        `json:"api_secret,omitempty"`
+	Passphrase         string        `json:"passphrase,omitempty"`
+	BalanceUSDT        float64       `json:"balance_usdt"`
+	InitialBalance     float64       `json:"initial_balance"`
+	MarginMode         MarginMode    `json:"margin_mode"`
+	HedgeMode          bool          `json:"hedge_mode"`
+	DefaultLeverage    int           `json:"default_leverage"`
+	MakerFeeRate       float64       `json:"maker_fee_rate"`
+	TakerFeeRate       float64       `json:"taker_fee_rate"`
+	SlippageModel      SlippageModel `json:"slippage_model"`
+	SlippageBps        float64       `json:"slippage_bps"`
+	SlippageDepth      float64       `json:"slippage_depth"`
+	SkipSignatureCheck bool          `json:"skip_signature_check"`
+	EndpointURL        string        `json:"endpoint_url"`
+	CreatedAt          time.Time     `json:"created_at"`
 }
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// AgentWallet is an API (agent) wallet approved to sign Hyperliquid actions on behalf of an account
+// An account has at most one agent per name; the unnamed agent uses an empty name
+type AgentWallet struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	AccountID uint      `gorm:"not null;uniqueIndex:idx_agent_wallets_account_name" json:"account_id"`
+	Name      string    `gorm:"size:50;not null;default:'';uniqueIndex:idx_agent_wallets_account_name" json:"name"`
+	Address   string    `gorm:"size:42;not null;uniqueIndex" json:"address"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relations
+	Account Account `gorm:"foreignKey:AccountID" json:"-"`
+}
+
+// TableName specifies the table name for AgentWallet model
+func (AgentWallet) TableName() string {
+	return "agent_wallets"
+}
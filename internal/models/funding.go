@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+)
+
+// DefaultFundingRate is the baseline funding rate (0.01% per interval) used when no live rate is available
+const DefaultFundingRate = 0.0001
+
+// FundingRate represents a funding rate snapshot for a perpetual contract
+type FundingRate struct {
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	Exchange    ExchangeType `gorm:"size:20;not null;uniqueIndex:idx_funding_rates_exchange_symbol_time" json:"exchange"`
+	Symbol      string       `gorm:"size:20;not null;uniqueIndex:idx_funding_rates_exchange_symbol_time" json:"symbol"`
+	Rate        float64      `gorm:"type:decimal(20,10);not null" json:"rate"`
+	MarkPrice   float64      `gorm:"type:decimal(20,8)" json:"mark_price"`
+	FundingTime time.Time    `gorm:"not null;uniqueIndex:idx_funding_rates_exchange_symbol_time" json:"funding_time"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// TableName specifies the table name for FundingRate model
+func (FundingRate) TableName() string {
+	return "funding_rates"
+}
+
+// FundingFee represents a funding payment settled against a position (ledger entry)
+type FundingFee struct {
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	AccountID    uint         `gorm:"index;not null" json:"account_id"`
+	Exchange     ExchangeType `gorm:"size:20;not null" json:"exchange"`
+	Symbol       string       `gorm:"size:20;not null;index" json:"symbol"`
+	Side         PositionSide `gorm:"size:10;not null" json:"side"`
+	Quantity     float64      `gorm:"type:decimal(20,8);not null" json:"quantity"`
+	MarkPrice    float64      `gorm:"type:decimal(20,8);not null" json:"mark_price"`
+	FundingRate  float64      `gorm:"type:decimal(20,10);not null" json:"funding_rate"`
+	Amount       float64      `gorm:"type:decimal(20,8);not null" json:"amount"` // Positive = received, negative = paid
+	BalanceAfter float64      `gorm:"type:decimal(20,8)" json:"balance_after"`
+	FundingTime  time.Time    `gorm:"index" json:"funding_time"`
+
+	MarginMode MarginMode `gorm:"size:20;default:'cross'" json:"margin_mode"` // isolated fees move the position margin
+
+	// Relations
+	Account Account `gorm:"foreignKey:AccountID" json:"-"`
+}
+
+// TableName specifies the table name for FundingFee model
+func (FundingFee) TableName() string {
+	return "funding_fees"
+}
+
+// FundingInterval returns how often an exchange settles funding
+func FundingInterval(exchangeType ExchangeType) time.Duration {
+	if exchangeType == ExchangeHyperliquid {
+		return time.Hour
+	}
+	return 8 * time.Hour
+}
+
+// CalculateFundingFee returns the signed funding payment for a position
+// Longs pay shorts when the rate is positive; shorts pay longs when it is negative
+func CalculateFundingFee(side PositionSide, quantity, markPrice, rate float64) float64 {
+	amount := quantity * markPrice * rate
+	if side == PositionSideLong {
+		return -amount
+	}
+	return amount
+}
@@ -16,6 +16,7 @@ const (
 	OrderTypeTakeProfit   OrderType = "TAKE_PROFIT"
 	OrderTypeStopMarket   OrderType = "STOP_MARKET"
 	OrderTypeTrailingStop OrderType = "TRAILING_STOP_MARKET"
+	OrderTypeLiquidation  OrderType = "LIQUIDATION" // Forced close by the liquidation engine
 )
 
 // OrderSide represents the order side
@@ -36,6 +37,25 @@ const (
 	OrderStatusCanceled        OrderStatus = "CANCELED"
 	OrderStatusExpired         OrderStatus = "EXPIRED"
 	OrderStatusRejected        OrderStatus = "REJECTED"
+	OrderStatusTriggered       OrderStatus = "TRIGGERED" // Conditional order that placed its limit order
+)
+
+// Time in force values stored in Order.TimeInForce
+const (
+	TimeInForceGTC = "GTC" // Good till canceled
+	TimeInForceIOC = "IOC" // Immediate or cancel
+	TimeInForceFOK = "FOK" // Fill or kill
+	TimeInForceGTX = "GTX" // Post-only, expired instead of taking liquidity
+	TimeInForceGTD = "GTD" // Good till GoodTillDate
+)
+
+// TriggerPriceType is the price stream a conditional order's trigger is checked against
+type TriggerPriceType string
+
+const (
+	TriggerPriceLast  TriggerPriceType = "LAST"  // Last traded price (default)
+	TriggerPriceMark  TriggerPriceType = "MARK"  // Mark price
+	TriggerPriceIndex TriggerPriceType = "INDEX" // Index price
 )
 
 // Order represents a trading order
@@ -56,10 +76,23 @@ type Order struct {
 	ReduceOnly    bool           `gorm:"default:false" json:"reduce_only"`
 	ClosePosition bool           `gorm:"default:false" json:"close_position"`
 	TimeInForce   string         `gorm:"size:10;default:'GTC'" json:"time_in_force"`
+	GoodTillDate  *time.Time     `json:"good_till_date,omitempty"` // GTD orders only
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// Trailing stops trigger when price retraces from the watermark by the callback rate or spread
+	CallbackRate    float64 `gorm:"type:decimal(10,6)" json:"callback_rate,omitempty"`    // fraction of the watermark
+	CallbackSpread  float64 `gorm:"type:decimal(20,8)" json:"callback_spread,omitempty"`  // price distance
+	ActivationPrice float64 `gorm:"type:decimal(20,8)" json:"activation_price,omitempty"` // 0 activates at placement
+	Watermark       float64 `gorm:"type:decimal(20,8)" json:"watermark,omitempty"`        // best price since activation, 0 until active
+
+	// Conditional orders with a Price rest a reduce-only limit order at that price once triggered
+	TriggeredOrderID *uint `json:"triggered_order_id,omitempty"` // on the conditional order
+	ParentOrderID    *uint `json:"parent_order_id,omitempty"`    // on the limit order it placed
+
+	TriggerPriceType TriggerPriceType `gorm:"size:10" json:"trigger_price_type,omitempty"` // conditional orders only
+
 	// Relations
 	Account Account `gorm:"foreignKey:AccountID" json:"-"`
 	Trades  []Trade `gorm:"foreignKey:OrderID" json:"trades,omitempty"`
@@ -70,13 +103,27 @@ func (Order) TableName() string {
 	return "orders"
 }
 
+// IsLiquidation returns true if the order is a forced close by the liquidation engine
+func (o *Order) IsLiquidation() bool {
+	return o.Type == OrderTypeLiquidation
+}
+
+// IsConditional returns true if the order waits for a trigger price (SL/TP algo order)
+func (o *Order) IsConditional() bool {
+	switch o.Type {
+	case OrderTypeStopLoss, OrderTypeTakeProfit, OrderTypeStopMarket, OrderTypeTrailingStop:
+		return true
+	}
+	return false
+}
+
 // IsPending returns true if the order is still pending
 func (o *Order) IsPending() bool {
 	return o.Status == OrderStatusNew || o.Status == OrderStatusPartiallyFilled
 }
 
-// IsCompleted returns true if the order is completed (filled or canceled)
+// IsCompleted returns true if the order is completed (filled, canceled or triggered)
 func (o *Order) IsCompleted() bool {
 	return o.Status == OrderStatusFilled || o.Status == OrderStatusCanceled ||
-		o.Status == OrderStatusExpired || o.Status == OrderStatusRejected
+		o.Status == OrderStatusExpired || o.Status == OrderStatusRejected || o.Status == OrderStatusTriggered
 }
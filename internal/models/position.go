@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"time"
 
 	"gorm.io/gorm"
@@ -59,3 +60,68 @@ func (p *Position) CalculateLiquidationPrice(maintenanceMarginRate float64) floa
 	}
 	return p.EntryPrice * (1 + 1/float64(p.Leverage) - maintenanceMarginRate)
 }
+
+// IsIsolated returns true if the position locks its own margin
+func (p *Position) IsIsolated() bool {
+	return p.MarginMode == MarginModeIsolated
+}
+
+// CalculateIsolatedLiquidationPrice calculates the liquidation price from the position's own margin,
+// i.e. the price at which margin plus unrealized PnL equals maintenance margin
+func (p *Position) CalculateIsolatedLiquidationPrice(maintenanceMarginRate float64) float64 {
+	return p.CalculateCrossLiquidationPrice(p.Margin, maintenanceMarginRate)
+}
+
+// CalculateCrossLiquidationPrice calculates the liquidation price given the collateral backing the position
+// For cross positions the collateral is the account's cross equity excluding this position's PnL and
+// net of other positions' maintenance margin
+func (p *Position) CalculateCrossLiquidationPrice(collateral, maintenanceMarginRate float64) float64 {
+	if p.Quantity <= 0 {
+		return 0
+	}
+
+	var price float64
+	if p.Side == PositionSideLong {
+		price = (p.EntryPrice*p.Quantity - collateral) / (p.Quantity * (1 - maintenanceMarginRate))
+	} else {
+		price = (p.EntryPrice*p.Quantity + collateral) / (p.Quantity * (1 + maintenanceMarginRate))
+	}
+	return math.Max(price, 0)
+}
+
+// IsLiquidatable returns true if the mark price breaches the liquidation price
+// or the position's margin plus unrealized PnL no longer covers maintenance margin
+func (p *Position) IsLiquidatable(markPrice, maintenanceMarginRate float64) bool {
+	if markPrice <= 0 {
+		return false
+	}
+
+	if p.LiquidationPrice > 0 {
+		if p.Side == PositionSideLong && markPrice <= p.LiquidationPrice {
+			return true
+		}
+		if p.Side == PositionSideShort && markPrice >= p.LiquidationPrice {
+			return true
+		}
+	}
+
+	maintenanceMargin := markPrice * p.Quantity * maintenanceMarginRate
+	return p.Margin+p.CalculateUnrealizedPnL(markPrice) <= maintenanceMargin
+}
+
+// CrossMarginState summarises the positions of an account that share its cross-margin collateral
+type CrossMarginState struct {
+	Wallet            float64 // Wallet balance not locked in isolated positions
+	UnrealizedPnL     float64 // Unrealized PnL of cross positions
+	MaintenanceMargin float64 // Maintenance margin of cross positions
+}
+
+// Equity returns the cross margin balance
+func (c CrossMarginState) Equity() float64 {
+	return c.Wallet + c.UnrealizedPnL
+}
+
+// IsLiquidatable returns true if cross equity no longer covers the cross maintenance margin
+func (c CrossMarginState) IsLiquidatable() bool {
+	return c.MaintenanceMargin > 0 && c.Equity() <= c.MaintenanceMargin
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// SymbolSetting stores an account's per-symbol leverage, margin mode and position mode
+// Symbols without a row inherit the account defaults
+type SymbolSetting struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	AccountID  uint       `gorm:"not null;uniqueIndex:idx_symbol_settings_account_symbol" json:"account_id"`
+	Symbol     string     `gorm:"size:20;not null;uniqueIndex:idx_symbol_settings_account_symbol" json:"symbol"`
+	Leverage   int        `gorm:"not null" json:"leverage"`
+	MarginMode MarginMode `gorm:"size:20;not null;default:'cross'" json:"margin_mode"`
+	HedgeMode  bool       `gorm:"default:false" json:"hedge_mode"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// Relations
+	Account Account `gorm:"foreignKey:AccountID" json:"-"`
+}
+
+// TableName specifies the table name for SymbolSetting model
+func (SymbolSetting) TableName() string {
+	return "symbol_settings"
+}
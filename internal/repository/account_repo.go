@@ -5,6 +5,7 @@ import (
 
 	"github.com/ccxt-simulator/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
@@ -100,9 +101,10 @@ func (r *AccountRepository) GetByUserIDPaginated(userID uint, page, pageSize int
 	return accounts, total, nil
 }
 
-// Update updates an account
+// Update updates an account, except for its balance which only changes through UpdateBalance and AddBalance
+// so that a save of a stale account cannot undo a concurrent fill
 func (r *AccountRepository) Update(account *models.Account) error {
-	return r.db.Save(account).Error
+	return r.db.Omit("balance_usdt").Save(account).Error
 }
 
 // UpdateBalance updates the account balance
@@ -110,6 +112,23 @@ func (r *AccountRepository) UpdateBalance(id uint, balance float64) error {
 	return r.db.Model(&models.Account{}).Where("id = ?", id).Update("balance_usdt", balance).Error
 }
 
+// AddBalance atomically adds delta to the account balance and reloads the new balance into account
+func (r *AccountRepository) AddBalance(account *models.Account, delta float64) error {
+	return r.addBalance(account, gorm.Expr("balance_usdt + ?", delta))
+}
+
+// AddBalanceFloored is AddBalance with the new balance floored at zero
+func (r *AccountRepository) AddBalanceFloored(account *models.Account, delta float64) error {
+	return r.addBalance(account, gorm.Expr("CASE WHEN balance_usdt + ? < 0 THEN 0 ELSE balance_usdt + ? END", delta, delta))
+}
+
+func (r *AccountRepository) addBalance(account *models.Account, expr clause.Expr) error {
+	if err := r.db.Model(&models.Account{}).Where("id = ?", account.ID).Update("balance_usdt", expr).Error; err != nil {
+		return err
+	}
+	return r.db.Model(&models.Account{}).Select("balance_usdt").Where("id = ?", account.ID).Scan(&account.BalanceUSDT).Error
+}
+
 // Delete soft deletes an account
 func (r *AccountRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Account{}, id).Error
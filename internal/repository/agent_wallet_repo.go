@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/ccxt-simulator/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AgentWalletRepository handles agent wallet data access
+type AgentWalletRepository struct {
+	db *gorm.DB
+}
+
+// NewAgentWalletRepository creates a new AgentWalletRepository
+func NewAgentWalletRepository(db *gorm.DB) *AgentWalletRepository {
+	return &AgentWalletRepository{db: db}
+}
+
+// GetByAddress retrieves an agent wallet by its address, returning nil when none is stored
+func (r *AgentWalletRepository) GetByAddress(address string) (*models.AgentWallet, error) {
+	var agent models.AgentWallet
+	result := r.db.Where("address = ?", address).First(&agent)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &agent, nil
+}
+
+// GetByAccountID retrieves all agent wallets of an account
+func (r *AgentWalletRepository) GetByAccountID(accountID uint) ([]models.AgentWallet, error) {
+	var agents []models.AgentWallet
+	result := r.db.Where("account_id = ?", accountID).Order("name").Find(&agents)
+	return agents, result.Error
+}
+
+// Save inserts the agent wallet or replaces the account's agent with the same name
+func (r *AgentWalletRepository) Save(agent *models.AgentWallet) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account_id"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"address", "updated_at"}),
+	}).Create(agent).Error
+}
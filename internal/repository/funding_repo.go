@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ccxt-simulator/internal/models"
+	"gorm.io/gorm"
+)
+
+// FundingRepository handles funding rate and funding fee data access
+type FundingRepository struct {
+	db *gorm.DB
+}
+
+// NewFundingRepository creates a new FundingRepository
+func NewFundingRepository(db *gorm.DB) *FundingRepository {
+	return &FundingRepository{db: db}
+}
+
+// CreateRate records a funding rate snapshot
+func (r *FundingRepository) CreateRate(rate *models.FundingRate) error {
+	return r.db.Create(rate).Error
+}
+
+// HasRate checks whether funding was already recorded for an exchange/symbol at a funding time
+func (r *FundingRepository) HasRate(exchangeType models.ExchangeType, symbol string, fundingTime time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.FundingRate{}).
+		Where("exchange = ? AND symbol = ? AND funding_time = ?", exchangeType, symbol, fundingTime).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetLatestRate retrieves the most recent funding rate for an exchange/symbol
+func (r *FundingRepository) GetLatestRate(exchangeType models.ExchangeType, symbol string) (*models.FundingRate, error) {
+	var rate models.FundingRate
+	result := r.db.Where("exchange = ? AND symbol = ?", exchangeType, symbol).
+		Order("funding_time DESC").
+		First(&rate)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &rate, nil
+}
+
+// CreateFee creates a funding fee ledger entry
+func (r *FundingRepository) CreateFee(fee *models.FundingFee) error {
+	return r.db.Create(fee).Error
+}
+
+// GetFees retrieves funding fees for an account, newest first
+// Zero start/end times and an empty symbol are not applied as filters
+func (r *FundingRepository) GetFees(accountID uint, symbol string, start, end time.Time, limit int) ([]models.FundingFee, error) {
+	var fees []models.FundingFee
+	query := r.db.Where("account_id = ?", accountID)
+	if symbol != "" {
+		query = query.Where("symbol = ?", symbol)
+	}
+	if !start.IsZero() {
+		query = query.Where("funding_time >= ?", start)
+	}
+	if !end.IsZero() {
+		query = query.Where("funding_time <= ?", end)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	result := query.Order("funding_time DESC").Find(&fees)
+	return fees, result.Error
+}
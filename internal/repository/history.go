@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ccxt-simulator/internal/models"
+	"gorm.io/gorm"
+)
+
+// HistoryQuery filters and pages account history (orders, trades, closed PnL)
+// Zero values are not applied as filters. AfterID/BeforeID are exclusive record ID bounds
+// used as pagination cursors; results are ordered by ID, newest first unless Ascending is set
+type HistoryQuery struct {
+	Symbol    string
+	OrderID   uint
+	Statuses  []models.OrderStatus // orders only
+	Start     time.Time
+	End       time.Time
+	AfterID   uint
+	BeforeID  uint
+	Ascending bool
+	Limit     int
+}
+
+// apply adds the query's filters, ordering and limit; timeColumn is the record's event time
+func (q HistoryQuery) apply(db *gorm.DB, accountID uint, timeColumn string) *gorm.DB {
+	db = db.Where("account_id = ?", accountID)
+	if q.Symbol != "" {
+		db = db.Where("symbol = ?", q.Symbol)
+	}
+	if !q.Start.IsZero() {
+		db = db.Where(timeColumn+" >= ?", q.Start)
+	}
+	if !q.End.IsZero() {
+		db = db.Where(timeColumn+" <= ?", q.End)
+	}
+	if q.AfterID > 0 {
+		db = db.Where("id > ?", q.AfterID)
+	}
+	if q.BeforeID > 0 {
+		db = db.Where("id < ?", q.BeforeID)
+	}
+	if q.Limit > 0 {
+		db = db.Limit(q.Limit)
+	}
+	if q.Ascending {
+		return db.Order("id ASC")
+	}
+	return db.Order("id DESC")
+}
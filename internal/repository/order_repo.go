@@ -43,7 +43,7 @@ func (r *OrderRepository) GetByID(id uint) (*models.Order, error) {
 // GetByClientOrderID retrieves an order by client order ID
 func (r *OrderRepository) GetByClientOrderID(accountID uint, clientOrderID string) (*models.Order, error) {
 	var order models.Order
-	result := r.db.Where("account_id = ? AND client_order_id = ?", accountID, clientOrderID).First(&order)
+	result := r.db.Where("account_id = ? AND client_order_id = ?", accountID, clientOrderID).Order("id DESC").First(&order)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrOrderNotFound
@@ -53,6 +53,16 @@ func (r *OrderRepository) GetByClientOrderID(accountID uint, clientOrderID strin
 	return &order, nil
 }
 
+// HasOpenClientOrderID reports whether an account has a pending order with the client order ID
+func (r *OrderRepository) HasOpenClientOrderID(accountID uint, clientOrderID string) (bool, error) {
+	var count int64
+	result := r.db.Model(&models.Order{}).Where("account_id = ? AND client_order_id = ? AND status IN ?", accountID, clientOrderID, []models.OrderStatus{
+		models.OrderStatusNew,
+		models.OrderStatusPartiallyFilled,
+	}).Count(&count)
+	return count > 0, result.Error
+}
+
 // GetByAccountID retrieves all orders for an account
 func (r *OrderRepository) GetByAccountID(accountID uint) ([]models.Order, error) {
 	var orders []models.Order
@@ -115,6 +125,29 @@ func (r *OrderRepository) Update(order *models.Order) error {
 	return r.db.Save(order).Error
 }
 
+// UpdateIfOpen updates an order only while it is still open in the database
+// Returns false when a concurrent fill or cancel closed the order first
+func (r *OrderRepository) UpdateIfOpen(order *models.Order) (bool, error) {
+	result := r.db.Model(order).
+		Where("status IN ?", []models.OrderStatus{models.OrderStatusNew, models.OrderStatusPartiallyFilled}).
+		Select("*").
+		Updates(order)
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdateIfUnchanged updates an order only while it is still open at the price and quantity it was read with
+// Returns false when a concurrent fill, cancel or amend changed the order first
+func (r *OrderRepository) UpdateIfUnchanged(order *models.Order, price, quantity float64) (bool, error) {
+	result := r.db.Model(order).
+		Where("status IN ? AND price = ? AND quantity = ?", []models.OrderStatus{
+			models.OrderStatusNew,
+			models.OrderStatusPartiallyFilled,
+		}, price, quantity).
+		Select("*").
+		Updates(order)
+	return result.RowsAffected > 0, result.Error
+}
+
 // UpdateStatus updates order status
 func (r *OrderRepository) UpdateStatus(id uint, status models.OrderStatus) error {
 	return r.db.Model(&models.Order{}).Where("id = ?", id).Updates(map[string]interface{}{
@@ -212,6 +245,53 @@ func (r *OrderRepository) GetAllPendingStopOrders() ([]models.Order, error) {
 		models.OrderTypeStopLoss,
 		models.OrderTypeTakeProfit,
 		models.OrderTypeStopMarket,
+		models.OrderTypeTrailingStop,
 	}).Find(&orders)
 	return orders, result.Error
 }
+
+// GetAllOpenOrdersByType retrieves open orders of a given type across all accounts
+func (r *OrderRepository) GetAllOpenOrdersByType(orderType models.OrderType) ([]models.Order, error) {
+	var orders []models.Order
+	result := r.db.Where("status IN ? AND type = ?", []models.OrderStatus{
+		models.OrderStatusNew,
+		models.OrderStatusPartiallyFilled,
+	}, orderType).Find(&orders)
+	return orders, result.Error
+}
+
+// GetByTypes retrieves orders of specific types regardless of status, newest first
+func (r *OrderRepository) GetByTypes(accountID uint, symbol string, orderTypes []models.OrderType, limit int) ([]models.Order, error) {
+	var orders []models.Order
+	query := r.db.Where("account_id = ? AND type IN ?", accountID, orderTypes)
+	if symbol != "" {
+		query = query.Where("symbol = ?", symbol)
+	}
+	result := query.Order("created_at DESC").Limit(limit).Find(&orders)
+	return orders, result.Error
+}
+
+// GetHistory retrieves orders for an account (optionally by symbol), newest first
+func (r *OrderRepository) GetHistory(accountID uint, symbol string, limit int) ([]models.Order, error) {
+	var orders []models.Order
+	query := r.db.Where("account_id = ?", accountID)
+	if symbol != "" {
+		query = query.Where("symbol = ?", symbol)
+	}
+	result := query.Order("created_at DESC").Limit(limit).Find(&orders)
+	return orders, result.Error
+}
+
+// FindHistory retrieves orders of any status matching a history query
+func (r *OrderRepository) FindHistory(accountID uint, q HistoryQuery) ([]models.Order, error) {
+	var orders []models.Order
+	query := q.apply(r.db, accountID, "created_at")
+	if q.OrderID > 0 {
+		query = query.Where("id = ?", q.OrderID)
+	}
+	if len(q.Statuses) > 0 {
+		query = query.Where("status IN ?", q.Statuses)
+	}
+	result := query.Find(&orders)
+	return orders, result.Error
+}
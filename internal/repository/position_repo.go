@@ -47,6 +47,13 @@ func (r *PositionRepository) GetByAccountID(accountID uint) ([]models.Position,
 	return positions, result.Error
 }
 
+// GetAll retrieves all open positions across all accounts
+func (r *PositionRepository) GetAll() ([]models.Position, error) {
+	var positions []models.Position
+	result := r.db.Find(&positions)
+	return positions, result.Error
+}
+
 // GetByAccountIDAndSymbol retrieves positions by account ID and symbol
 func (r *PositionRepository) GetByAccountIDAndSymbol(accountID uint, symbol string) ([]models.Position, error) {
 	var positions []models.Position
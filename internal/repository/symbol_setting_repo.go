@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/ccxt-simulator/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SymbolSettingRepository handles per-symbol account settings data access
+type SymbolSettingRepository struct {
+	db *gorm.DB
+}
+
+// NewSymbolSettingRepository creates a new SymbolSettingRepository
+func NewSymbolSettingRepository(db *gorm.DB) *SymbolSettingRepository {
+	return &SymbolSettingRepository{db: db}
+}
+
+// Get retrieves the setting for an account/symbol, returning nil when none is stored
+func (r *SymbolSettingRepository) Get(accountID uint, symbol string) (*models.SymbolSetting, error) {
+	var setting models.SymbolSetting
+	result := r.db.Where("account_id = ? AND symbol = ?", accountID, symbol).First(&setting)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &setting, nil
+}
+
+// GetByAccountID retrieves all stored settings for an account
+func (r *SymbolSettingRepository) GetByAccountID(accountID uint) ([]models.SymbolSetting, error) {
+	var settings []models.SymbolSetting
+	result := r.db.Where("account_id = ?", accountID).Order("symbol").Find(&settings)
+	return settings, result.Error
+}
+
+// Save inserts or updates the setting for its account/symbol
+func (r *SymbolSettingRepository) Save(setting *models.SymbolSetting) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account_id"}, {Name: "symbol"}},
+		DoUpdates: clause.AssignmentColumns([]string{"leverage", "margin_mode", "hedge_mode", "updated_at"}),
+	}).Create(setting).Error
+}
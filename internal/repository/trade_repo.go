@@ -72,6 +72,17 @@ func (r *TradeRepository) GetTradesAfter(accountID uint, after time.Time, limit
 	return trades, result.Error
 }
 
+// FindHistory retrieves trades matching a history query, with their orders
+func (r *TradeRepository) FindHistory(accountID uint, q HistoryQuery) ([]models.Trade, error) {
+	var trades []models.Trade
+	query := q.apply(r.db.Preload("Order"), accountID, "executed_at")
+	if q.OrderID > 0 {
+		query = query.Where("order_id = ?", q.OrderID)
+	}
+	result := query.Find(&trades)
+	return trades, result.Error
+}
+
 // GetTotalFees calculates total fees paid
 func (r *TradeRepository) GetTotalFees(accountID uint) (float64, error) {
 	var total struct {
@@ -144,6 +155,13 @@ func (r *ClosedPnLRepository) GetBySymbol(accountID uint, symbol string) ([]mode
 	return records, result.Error
 }
 
+// FindHistory retrieves closed PnL records matching a history query
+func (r *ClosedPnLRepository) FindHistory(accountID uint, q HistoryQuery) ([]models.ClosedPnLRecord, error) {
+	var records []models.ClosedPnLRecord
+	result := q.apply(r.db, accountID, "closed_at").Find(&records)
+	return records, result.Error
+}
+
 // GetTotalClosedPnL calculates total closed PnL
 func (r *ClosedPnLRepository) GetTotalClosedPnL(accountID uint) (float64, error) {
 	var total struct {
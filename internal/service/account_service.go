@@ -1,7 +1,9 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/ccxt-simulator/internal/config"
 	"github.com/ccxt-simulator/internal/models"
@@ -10,9 +12,13 @@ import (
 	"github.com/ccxt-simulator/pkg/keygen"
 )
 
+// ErrWalletNotFound is returned when an address is neither an account wallet nor an approved agent
+var ErrWalletNotFound = errors.New("wallet not found")
+
 // AccountService handles account operations
 type AccountService struct {
 	accountRepo      *repository.AccountRepository
+	agentWalletRepo  *repository.AgentWalletRepository
 	encryptionConfig config.EncryptionConfig
 	baseURL          string
 }
@@ -20,11 +26,13 @@ type AccountService struct {
 // NewAccountService creates a new AccountService
 func NewAccountService(
 	accountRepo *repository.AccountRepository,
+	agentWalletRepo *repository.AgentWalletRepository,
 	encryptionConfig config.EncryptionConfig,
 	baseURL string,
 ) *AccountService {
 	return &AccountService{
 		accountRepo:      accountRepo,
+		agentWalletRepo:  agentWalletRepo,
 		encryptionConfig: encryptionConfig,
 		baseURL:          baseURL,
 	}
@@ -84,6 +92,9 @@ func (s *AccountService) CreateAccount(userID uint, req *CreateAccountRequest) (
 		DefaultLeverage:     req.DefaultLeverage,
 		MakerFeeRate:        0.0002,
 		TakerFeeRate:        0.0004,
+		SlippageModel:       models.SlippageFixed,
+		SlippageBps:         1,
+		SlippageDepth:       1000000,
 	}
 
 	if err := s.accountRepo.Create(account); err != nil {
@@ -139,11 +150,63 @@ func (s *AccountService) GetAccountByAPIKey(apiKey string) (*models.Account, err
 	return s.accountRepo.GetByAPIKey(apiKey)
 }
 
+// GetAccountByWallet resolves a Hyperliquid signer address to its account
+// The address may be the account wallet itself or one of its approved agent wallets (returned as agent)
+func (s *AccountService) GetAccountByWallet(address string) (*models.Account, *models.AgentWallet, error) {
+	address = strings.ToLower(address)
+
+	account, err := s.accountRepo.GetByAPIKey(address)
+	if err == nil && account.ExchangeType == models.ExchangeHyperliquid {
+		return account, nil, nil
+	}
+	if err != nil && err != repository.ErrAccountNotFound {
+		return nil, nil, err
+	}
+
+	agent, err := s.agentWalletRepo.GetByAddress(address)
+	if err != nil {
+		return nil, nil, err
+	}
+	if agent == nil {
+		return nil, nil, ErrWalletNotFound
+	}
+
+	account, err = s.accountRepo.GetByID(agent.AccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return account, agent, nil
+}
+
+// ApproveAgent binds an agent wallet to an account, replacing any agent with the same name
+func (s *AccountService) ApproveAgent(accountID uint, address, name string) (*models.AgentWallet, error) {
+	agent := &models.AgentWallet{
+		AccountID: accountID,
+		Name:      name,
+		Address:   strings.ToLower(address),
+	}
+	if err := s.agentWalletRepo.Save(agent); err != nil {
+		return nil, err
+	}
+	return agent, nil
+}
+
+// GetAgentWallets retrieves the agent wallets approved for an account
+func (s *AccountService) GetAgentWallets(accountID uint) ([]models.AgentWallet, error) {
+	return s.agentWalletRepo.GetByAccountID(accountID)
+}
+
 // UpdateAccountRequest represents the update account request
 type UpdateAccountRequest struct {
 	MarginMode      *models.MarginMode `json:"margin_mode" binding:"omitempty,oneof=cross isolated"`
 	HedgeMode       *bool              `json:"hedge_mode"`
 	DefaultLeverage *int               `json:"default_leverage" binding:"omitempty,min=1,max=125"`
+	// SkipSignatureCheck relaxes request signing for debugging (Binance and Hyperliquid)
+	SkipSignatureCheck *bool `json:"skip_signature_check"`
+	// Slippage settings price taker fills beyond the best bid/ask
+	SlippageModel *models.SlippageModel `json:"slippage_model" binding:"omitempty,oneof=none fixed spread impact"`
+	SlippageBps   *float64              `json:"slippage_bps" binding:"omitempty,min=0,max=1000"`
+	SlippageDepth *float64              `json:"slippage_depth" binding:"omitempty,gt=0"`
 }
 
 // UpdateAccount updates an account
@@ -162,6 +225,18 @@ func (s *AccountService) UpdateAccount(userID, accountID uint, req *UpdateAccoun
 	if req.DefaultLeverage != nil {
 		account.DefaultLeverage = *req.DefaultLeverage
 	}
+	if req.SkipSignatureCheck != nil {
+		account.SkipSignatureCheck = *req.SkipSignatureCheck
+	}
+	if req.SlippageModel != nil {
+		account.SlippageModel = *req.SlippageModel
+	}
+	if req.SlippageBps != nil {
+		account.SlippageBps = *req.SlippageBps
+	}
+	if req.SlippageDepth != nil {
+		account.SlippageDepth = *req.SlippageDepth
+	}
 
 	if err := s.accountRepo.Update(account); err != nil {
 		return nil, err
@@ -228,8 +303,7 @@ func (s *AccountService) AddBalance(userID, accountID uint, amount float64) (*mo
 		return nil, err
 	}
 
-	account.BalanceUSDT += amount
-	if err := s.accountRepo.Update(account); err != nil {
+	if err := s.accountRepo.AddBalance(account, amount); err != nil {
 		return nil, err
 	}
 
@@ -239,20 +313,24 @@ func (s *AccountService) AddBalance(userID, accountID uint, amount float64) (*mo
 // buildAccountResponse builds an AccountResponse from an Account
 func (s *AccountService) buildAccountResponse(account *models.Account, apiSecret, passphrase string) *models.AccountResponse {
 	return &models.AccountResponse{
-		ID:              account.ID,
-		ExchangeType:    account.ExchangeType,
-		APIKey:          account.APIKey,
-		APISecret:       apiSecret,
-		Passphrase:      passphrase,
-		BalanceUSDT:     account.BalanceUSDT,
-		InitialBalance:  account.InitialBalance,
-		MarginMode:      account.MarginMode,
-		HedgeMode:       account.HedgeMode,
-		DefaultLeverage: account.DefaultLeverage,
-		MakerFeeRate:    account.MakerFeeRate,
-		TakerFeeRate:    account.TakerFeeRate,
-		EndpointURL:     s.getEndpointURL(account.ExchangeType),
-		CreatedAt:       account.CreatedAt,
+		ID:                 account.ID,
+		ExchangeType:       account.ExchangeType,
+		APIKey:             account.APIKey,
+		APISecret:          apiSecret,
+		Passphrase:         passphrase,
+		BalanceUSDT:        account.BalanceUSDT,
+		InitialBalance:     account.InitialBalance,
+		MarginMode:         account.MarginMode,
+		HedgeMode:          account.HedgeMode,
+		DefaultLeverage:    account.DefaultLeverage,
+		MakerFeeRate:       account.MakerFeeRate,
+		TakerFeeRate:       account.TakerFeeRate,
+		SlippageModel:      account.SlippageModel,
+		SlippageBps:        account.SlippageBps,
+		SlippageDepth:      account.SlippageDepth,
+		SkipSignatureCheck: account.SkipSignatureCheck,
+		EndpointURL:        s.getEndpointURL(account.ExchangeType),
+		CreatedAt:          account.CreatedAt,
 	}
 }
 
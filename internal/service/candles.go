@@ -0,0 +1,114 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ccxt-simulator/internal/exchange"
+)
+
+// candleHistory is how many one-minute candles are kept per exchange/symbol
+const candleHistory = 3 * 24 * 60
+
+// Candle is an OHLC bar built from live price ticks
+// Ticks counts the price updates that went into the bar; traded volume is not simulated
+type Candle struct {
+	OpenTime time.Time
+	Open     float64
+	High     float64
+	Low      float64
+	Close    float64
+	Ticks    int
+}
+
+// CandleStore aggregates price ticks into one-minute candles per exchange/symbol
+// It implements exchange.PriceSubscriber and is fed by PriceService
+type CandleStore struct {
+	candles map[string]map[string][]Candle // exchange -> symbol -> ascending one-minute candles
+	mu      sync.RWMutex
+}
+
+// NewCandleStore creates a new CandleStore
+func NewCandleStore() *CandleStore {
+	return &CandleStore{
+		candles: make(map[string]map[string][]Candle),
+	}
+}
+
+// OnPriceUpdate folds a tick into the current one-minute candle of its symbol
+func (s *CandleStore) OnPriceUpdate(update exchange.PriceUpdate) {
+	if update.Price <= 0 {
+		return
+	}
+
+	at := time.Now()
+	if update.Timestamp > 0 {
+		at = time.UnixMilli(update.Timestamp)
+	}
+	minute := at.Truncate(time.Minute)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.candles[update.Exchange] == nil {
+		s.candles[update.Exchange] = make(map[string][]Candle)
+	}
+	bars := s.candles[update.Exchange][update.Symbol]
+
+	if n := len(bars); n > 0 && !bars[n-1].OpenTime.Before(minute) {
+		// Late ticks are folded into the newest bar
+		last := &bars[n-1]
+		last.High = max(last.High, update.Price)
+		last.Low = min(last.Low, update.Price)
+		last.Close = update.Price
+		last.Ticks++
+		return
+	}
+
+	bars = append(bars, Candle{
+		OpenTime: minute,
+		Open:     update.Price,
+		High:     update.Price,
+		Low:      update.Price,
+		Close:    update.Price,
+		Ticks:    1,
+	})
+	if len(bars) > candleHistory {
+		bars = bars[len(bars)-candleHistory:]
+	}
+	s.candles[update.Exchange][update.Symbol] = bars
+}
+
+// GetCandles returns candles of the given interval whose open time falls in [start, end], oldest first
+// Intervals are multiples of a minute; bars are aligned to the interval from the Unix epoch
+func (s *CandleStore) GetCandles(exchangeName, symbol string, interval time.Duration, start, end time.Time) []Candle {
+	symbol = normalizeSymbol(exchangeName, symbol)
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]Candle, 0)
+	for _, bar := range s.candles[exchangeName][symbol] {
+		openTime := bar.OpenTime.Truncate(interval)
+		if openTime.Before(start.Truncate(interval)) || (!end.IsZero() && openTime.After(end)) {
+			continue
+		}
+
+		if n := len(result); n > 0 && result[n-1].OpenTime.Equal(openTime) {
+			merged := &result[n-1]
+			merged.High = max(merged.High, bar.High)
+			merged.Low = min(merged.Low, bar.Low)
+			merged.Close = bar.Close
+			merged.Ticks += bar.Ticks
+			continue
+		}
+
+		bar.OpenTime = openTime
+		result = append(result, bar)
+	}
+
+	return result
+}
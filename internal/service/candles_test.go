@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ccxt-simulator/internal/exchange"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCandleStoreAggregatesTicks tests one-minute bars and their aggregation into wider intervals
+func TestCandleStoreAggregatesTicks(t *testing.T) {
+	store := NewCandleStore()
+	base := time.UnixMilli(1704067200000) // 2024-01-01T00:00:00Z
+
+	ticks := []struct {
+		offset time.Duration
+		price  float64
+	}{
+		{10 * time.Second, 100},
+		{20 * time.Second, 105},
+		{50 * time.Second, 98},
+		{70 * time.Second, 101},
+		{6 * time.Minute, 110},
+	}
+	for _, tick := range ticks {
+		store.OnPriceUpdate(exchange.PriceUpdate{
+			Exchange:  "hyperliquid",
+			Symbol:    "BTCUSDT",
+			Price:     tick.price,
+			Timestamp: base.Add(tick.offset).UnixMilli(),
+		})
+	}
+
+	minutes := store.GetCandles("hyperliquid", "BTC", time.Minute, base, time.Time{})
+	assert.Len(t, minutes, 3)
+	assert.Equal(t, Candle{OpenTime: base, Open: 100, High: 105, Low: 98, Close: 98, Ticks: 3}, minutes[0])
+
+	fives := store.GetCandles("hyperliquid", "BTCUSDT", 5*time.Minute, base, time.Time{})
+	assert.Len(t, fives, 2)
+	assert.Equal(t, Candle{OpenTime: base, Open: 100, High: 105, Low: 98, Close: 101, Ticks: 4}, fives[0])
+	assert.Equal(t, 110.0, fives[1].Close)
+
+	// The end bound excludes later bars
+	assert.Len(t, store.GetCandles("hyperliquid", "BTCUSDT", 5*time.Minute, base, base.Add(time.Minute)), 1)
+	assert.Empty(t, store.GetCandles("okx", "BTCUSDT", time.Minute, base, time.Time{}))
+}
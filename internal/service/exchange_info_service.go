@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -13,6 +14,32 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// ErrUnknownAsset is returned when a Hyperliquid asset index is not in the meta universe
+var ErrUnknownAsset = errors.New("unknown asset")
+
+// HyperliquidAsset is one perpetual of the Hyperliquid meta universe
+// Its position in the universe is the asset index used by /exchange actions
+type HyperliquidAsset struct {
+	Index       int
+	Name        string
+	SzDecimals  int
+	MaxLeverage int
+}
+
+// Symbol returns the simulator symbol of the asset (BTC -> BTCUSDT)
+func (a *HyperliquidAsset) Symbol() string {
+	return a.Name + "USDT"
+}
+
+// DefaultHyperliquidUniverse is served when the live meta cannot be loaded
+var DefaultHyperliquidUniverse = []HyperliquidAsset{
+	{Index: 0, Name: "BTC", SzDecimals: 5, MaxLeverage: 40},
+	{Index: 1, Name: "ETH", SzDecimals: 4, MaxLeverage: 25},
+	{Index: 2, Name: "SOL", SzDecimals: 2, MaxLeverage: 20},
+	{Index: 3, Name: "DOGE", SzDecimals: 0, MaxLeverage: 10},
+	{Index: 4, Name: "XRP", SzDecimals: 1, MaxLeverage: 20},
+}
+
 // ExchangeInfoService caches exchange info from real exchanges
 type ExchangeInfoService struct {
 	redis          *redis.Client
@@ -184,6 +211,45 @@ func (s *ExchangeInfoService) GetExchangeInfo(exchange string) (interface{}, err
 	return data, nil
 }
 
+// GetHyperliquidUniverse returns the perpetuals of the cached Hyperliquid meta, in asset index order
+// It falls back to DefaultHyperliquidUniverse when the meta is unavailable
+func (s *ExchangeInfoService) GetHyperliquidUniverse() []HyperliquidAsset {
+	data, err := s.GetExchangeInfo("hyperliquid")
+	if err != nil {
+		return DefaultHyperliquidUniverse
+	}
+
+	meta, _ := data.(map[string]interface{})
+	entries, _ := meta["universe"].([]interface{})
+	if len(entries) == 0 {
+		return DefaultHyperliquidUniverse
+	}
+
+	universe := make([]HyperliquidAsset, 0, len(entries))
+	for i, entry := range entries {
+		fields, _ := entry.(map[string]interface{})
+		name, _ := fields["name"].(string)
+		szDecimals, _ := fields["szDecimals"].(float64)
+		maxLeverage, _ := fields["maxLeverage"].(float64)
+		universe = append(universe, HyperliquidAsset{
+			Index:       i,
+			Name:        name,
+			SzDecimals:  int(szDecimals),
+			MaxLeverage: int(maxLeverage),
+		})
+	}
+	return universe
+}
+
+// GetHyperliquidAsset resolves a Hyperliquid asset index against the meta universe
+func (s *ExchangeInfoService) GetHyperliquidAsset(index int) (*HyperliquidAsset, error) {
+	universe := s.GetHyperliquidUniverse()
+	if index < 0 || index >= len(universe) || universe[index].Name == "" {
+		return nil, ErrUnknownAsset
+	}
+	return &universe[index], nil
+}
+
 // Helper for json.NewReader
 type jsonReader struct {
 	data []byte
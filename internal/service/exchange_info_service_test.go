@@ -0,0 +1,29 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetHyperliquidAsset tests that asset indices resolve against the cached meta universe
+func TestGetHyperliquidAsset(t *testing.T) {
+	s := NewExchangeInfoService(nil)
+	s.cache["hyperliquid"] = map[string]interface{}{
+		"universe": []interface{}{
+			map[string]interface{}{"name": "BTC", "szDecimals": float64(5), "maxLeverage": float64(40)},
+			map[string]interface{}{"name": "ETH", "szDecimals": float64(4), "maxLeverage": float64(25)},
+			map[string]interface{}{"name": "kPEPE", "szDecimals": float64(0), "maxLeverage": float64(10)},
+		},
+	}
+
+	asset, err := s.GetHyperliquidAsset(2)
+	assert.NoError(t, err)
+	assert.Equal(t, "kPEPEUSDT", asset.Symbol())
+	assert.Equal(t, 0, asset.SzDecimals)
+
+	_, err = s.GetHyperliquidAsset(10)
+	assert.ErrorIs(t, err, ErrUnknownAsset)
+	_, err = s.GetHyperliquidAsset(-1)
+	assert.ErrorIs(t, err, ErrUnknownAsset)
+}
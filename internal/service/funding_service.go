@@ -0,0 +1,165 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/ccxt-simulator/internal/repository"
+)
+
+// FundingService settles periodic funding payments against open perpetual positions
+type FundingService struct {
+	accountRepo  *repository.AccountRepository
+	positionRepo *repository.PositionRepository
+	fundingRepo  *repository.FundingRepository
+	priceService *PriceService
+	events       *UserEventHub
+}
+
+// NewFundingService creates a new FundingService
+func NewFundingService(
+	accountRepo *repository.AccountRepository,
+	positionRepo *repository.PositionRepository,
+	fundingRepo *repository.FundingRepository,
+	priceService *PriceService,
+	events *UserEventHub,
+) *FundingService {
+	return &FundingService{
+		accountRepo:  accountRepo,
+		positionRepo: positionRepo,
+		fundingRepo:  fundingRepo,
+		priceService: priceService,
+		events:       events,
+	}
+}
+
+// SettleFunding settles one funding interval for all positions on an exchange
+// Symbols that already have a rate recorded for fundingTime are skipped, so settlement is idempotent
+func (s *FundingService) SettleFunding(exchangeType models.ExchangeType, fundingTime time.Time) (int, error) {
+	positions, err := s.positionRepo.GetAll()
+	if err != nil {
+		return 0, err
+	}
+
+	accounts := make(map[uint]*models.Account)
+	rates := make(map[string]*models.FundingRate) // symbol -> rate, nil if already settled
+	settled := 0
+
+	for _, position := range positions {
+		account, ok := accounts[position.AccountID]
+		if !ok {
+			account, err = s.accountRepo.GetByID(position.AccountID)
+			if err != nil {
+				continue
+			}
+			accounts[position.AccountID] = account
+		}
+		if account.ExchangeType != exchangeType {
+			continue
+		}
+
+		rate, ok := rates[position.Symbol]
+		if !ok {
+			rate, err = s.recordRate(exchangeType, position, fundingTime)
+			if err != nil {
+				log.Printf("[FundingService] Failed to record %s %s funding rate: %v", exchangeType, position.Symbol, err)
+			}
+			rates[position.Symbol] = rate
+		}
+		if rate == nil {
+			continue
+		}
+
+		amount := models.CalculateFundingFee(position.Side, position.Quantity, rate.MarkPrice, rate.Rate)
+		if err := s.accountRepo.AddBalance(account, amount); err != nil {
+			log.Printf("[FundingService] Failed to update account %d balance: %v", account.ID, err)
+			continue
+		}
+
+		// Isolated positions settle funding against their own margin, which moves the liquidation price
+		if position.IsIsolated() {
+			err := s.positionRepo.UpdateWithLock(position.ID, func(p *models.Position) error {
+				p.Margin += amount
+				mmr := s.priceService.GetMaintenanceMarginRate(string(exchangeType), p.EntryPrice*p.Quantity)
+				p.LiquidationPrice = p.CalculateIsolatedLiquidationPrice(mmr)
+				position = *p
+				return nil
+			})
+			if err != nil {
+				log.Printf("[FundingService] Failed to update position %d margin: %v", position.ID, err)
+			}
+		}
+
+		fee := &models.FundingFee{
+			AccountID:    account.ID,
+			Exchange:     exchangeType,
+			Symbol:       position.Symbol,
+			Side:         position.Side,
+			Quantity:     position.Quantity,
+			MarkPrice:    rate.MarkPrice,
+			FundingRate:  rate.Rate,
+			Amount:       amount,
+			BalanceAfter: account.BalanceUSDT,
+			FundingTime:  fundingTime,
+			MarginMode:   position.MarginMode,
+		}
+		if err := s.fundingRepo.CreateFee(fee); err != nil {
+			log.Printf("[FundingService] Failed to record funding fee for account %d: %v", account.ID, err)
+			continue
+		}
+		s.events.PublishAccount(account, UserEventReasonFundingFee, position)
+		settled++
+	}
+
+	return settled, nil
+}
+
+// recordRate resolves and persists the funding rate for a symbol at fundingTime
+// Returns nil if the interval was already settled
+func (s *FundingService) recordRate(exchangeType models.ExchangeType, position models.Position, fundingTime time.Time) (*models.FundingRate, error) {
+	exists, err := s.fundingRepo.HasRate(exchangeType, position.Symbol, fundingTime)
+	if err != nil || exists {
+		return nil, err
+	}
+
+	var markPrice float64
+	if quote, err := s.priceService.GetQuote(string(exchangeType), position.Symbol); err == nil {
+		markPrice = quote.Mark()
+	}
+	if markPrice <= 0 {
+		markPrice = position.MarkPrice
+	}
+	if markPrice <= 0 {
+		markPrice = position.EntryPrice
+	}
+
+	rate := &models.FundingRate{
+		Exchange:    exchangeType,
+		Symbol:      position.Symbol,
+		Rate:        s.resolveRate(exchangeType, position.Symbol),
+		MarkPrice:   markPrice,
+		FundingTime: fundingTime,
+	}
+	if err := s.fundingRepo.CreateRate(rate); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+// resolveRate returns the live funding rate, falling back to the last recorded rate
+// and then to the default baseline rate
+func (s *FundingService) resolveRate(exchangeType models.ExchangeType, symbol string) float64 {
+	if rate, err := s.priceService.GetFundingRate(string(exchangeType), symbol); err == nil {
+		return rate
+	}
+	if last, err := s.fundingRepo.GetLatestRate(exchangeType, symbol); err == nil && last != nil {
+		return last.Rate
+	}
+	return models.DefaultFundingRate
+}
+
+// GetFundingFees returns the funding ledger for an account, newest first
+func (s *FundingService) GetFundingFees(accountID uint, symbol string, start, end time.Time, limit int) ([]models.FundingFee, error) {
+	return s.fundingRepo.GetFees(accountID, symbol, start, end, limit)
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ccxt-simulator/internal/exchange"
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/ccxt-simulator/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSettleFundingUsesMarkPrice tests that funding is charged on the mark price and added to the stored balance
+func TestSettleFundingUsesMarkPrice(t *testing.T) {
+	h := newTestHarness(t)
+	funding := NewFundingService(h.accounts, h.position, repository.NewFundingRepository(h.db), h.prices, NewUserEventHub())
+
+	h.prices.prices["binance"] = map[string]exchange.PriceUpdate{
+		"BTCUSDT": {Exchange: "binance", Symbol: "BTCUSDT", Price: 100, MarkPrice: 101, Timestamp: time.Now().UnixMilli()},
+	}
+	account := h.newAccount(t, models.ExchangeBinance, 1000)
+	for _, side := range []models.PositionSide{models.PositionSideLong, models.PositionSideShort} {
+		require.NoError(t, h.position.Create(&models.Position{
+			AccountID:  account.ID,
+			Symbol:     "BTCUSDT",
+			Side:       side,
+			Quantity:   1,
+			EntryPrice: 100,
+			Leverage:   10,
+			MarginMode: models.MarginModeCross,
+		}))
+	}
+
+	settled, err := funding.SettleFunding(models.ExchangeBinance, time.Now().Truncate(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, settled)
+
+	fees, err := funding.GetFundingFees(account.ID, "BTCUSDT", time.Time{}, time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, fees, 2)
+	for _, fee := range fees {
+		assert.Equal(t, 101.0, fee.MarkPrice)
+		assert.InDelta(t, 101*models.DefaultFundingRate, math.Abs(fee.Amount), 1e-9)
+	}
+
+	stored, err := h.accounts.GetByID(account.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 1000, stored.BalanceUSDT, 1e-9, "Long and short funding should cancel out")
+}
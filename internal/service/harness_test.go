@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ccxt-simulator/internal/config"
+	"github.com/ccxt-simulator/internal/exchange"
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/ccxt-simulator/internal/repository"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// stubProvider serves fixed symbol info for one exchange
+type stubProvider struct {
+	name    string
+	symbols map[string]*exchange.SymbolInfo
+}
+
+func (p *stubProvider) Connect(ctx context.Context) error                 { return nil }
+func (p *stubProvider) Subscribe(symbols []string) error                  { return nil }
+func (p *stubProvider) Unsubscribe(symbols []string) error                { return nil }
+func (p *stubProvider) SetSubscriber(subscriber exchange.PriceSubscriber) {}
+func (p *stubProvider) GetAllSymbols() ([]string, error)                  { return nil, nil }
+func (p *stubProvider) Close() error                                      { return nil }
+func (p *stubProvider) ExchangeName() string                              { return p.name }
+func (p *stubProvider) IsConnected() bool                                 { return true }
+
+func (p *stubProvider) GetSymbolInfo(symbol string) (*exchange.SymbolInfo, error) {
+	if info, ok := p.symbols[symbol]; ok {
+		return info, nil
+	}
+	return nil, fmt.Errorf("symbol not found: %s", symbol)
+}
+
+// testHarness is a TradingService over an in-memory database and hand-fed quotes
+type testHarness struct {
+	db       *gorm.DB
+	prices   *PriceService
+	trading  *TradingService
+	accounts *repository.AccountRepository
+	orders   *repository.OrderRepository
+	position *repository.PositionRepository
+}
+
+func newTestHarness(t *testing.T) *testHarness {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&models.Account{}, &models.Position{}, &models.Order{}, &models.Trade{},
+		&models.ClosedPnLRecord{}, &models.SymbolSetting{}, &models.FundingRate{}, &models.FundingFee{},
+	))
+	sqlDB, _ := db.DB()
+	t.Cleanup(func() { sqlDB.Close() })
+
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { rdb.Close() })
+	prices := NewPriceService(rdb, config.PriceConfig{})
+	prices.ctx = context.Background()
+
+	h := &testHarness{
+		db:       db,
+		prices:   prices,
+		accounts: repository.NewAccountRepository(db),
+		orders:   repository.NewOrderRepository(db),
+		position: repository.NewPositionRepository(db),
+	}
+	h.trading = NewTradingService(h.accounts, h.position, h.orders,
+		repository.NewTradeRepository(db), repository.NewClosedPnLRepository(db),
+		repository.NewSymbolSettingRepository(db), prices)
+	return h
+}
+
+// addSymbol registers symbol info for an exchange
+func (h *testHarness) addSymbol(exchangeName, symbol string, info *exchange.SymbolInfo) {
+	provider, ok := h.prices.providers[exchangeName].(*stubProvider)
+	if !ok {
+		provider = &stubProvider{name: exchangeName, symbols: make(map[string]*exchange.SymbolInfo)}
+		h.prices.providers[exchangeName] = provider
+	}
+	provider.symbols[symbol] = info
+}
+
+// setQuote sets the live quote of a symbol
+func (h *testHarness) setQuote(exchangeName, symbol string, bid, ask float64) {
+	h.prices.pricesMux.Lock()
+	defer h.prices.pricesMux.Unlock()
+	if h.prices.prices[exchangeName] == nil {
+		h.prices.prices[exchangeName] = make(map[string]exchange.PriceUpdate)
+	}
+	h.prices.prices[exchangeName][symbol] = exchange.PriceUpdate{
+		Exchange:  exchangeName,
+		Symbol:    symbol,
+		Price:     (bid + ask) / 2,
+		BidPrice:  bid,
+		AskPrice:  ask,
+		Timestamp: time.Now().UnixMilli(),
+	}
+}
+
+// newAccount creates a funded account without slippage
+func (h *testHarness) newAccount(t *testing.T, exchangeType models.ExchangeType, balance float64) *models.Account {
+	t.Helper()
+	account := &models.Account{
+		UserID:          1,
+		ExchangeType:    exchangeType,
+		APIKey:          fmt.Sprintf("%s-%d", exchangeType, time.Now().UnixNano()),
+		BalanceUSDT:     balance,
+		InitialBalance:  balance,
+		MarginMode:      models.MarginModeCross,
+		DefaultLeverage: 10,
+		MakerFeeRate:    0.0002,
+		TakerFeeRate:    0.0004,
+		SlippageModel:   models.SlippageNone,
+	}
+	require.NoError(t, h.accounts.Create(account))
+	return account
+}
+
+// drainOrderEvents returns the order updates queued on a subscription
+func drainOrderEvents(ch chan UserEvent) []models.Order {
+	var orders []models.Order
+	for {
+		select {
+		case event := <-ch:
+			if event.Type == UserEventOrderUpdate && event.Order != nil {
+				orders = append(orders, *event.Order)
+			}
+		default:
+			return orders
+		}
+	}
+}
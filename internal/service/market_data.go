@@ -0,0 +1,63 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/ccxt-simulator/internal/exchange"
+)
+
+// marketDataBufferSize is the per-subscriber tick buffer; slow subscribers drop ticks
+const marketDataBufferSize = 1024
+
+// MarketDataHub fans out live price ticks to public market-data streams
+// It implements exchange.PriceSubscriber and is fed by PriceService
+type MarketDataHub struct {
+	subscribers map[string]map[chan exchange.PriceUpdate]struct{} // exchange -> subscribers
+	mu          sync.RWMutex
+}
+
+// NewMarketDataHub creates a new MarketDataHub
+func NewMarketDataHub() *MarketDataHub {
+	return &MarketDataHub{
+		subscribers: make(map[string]map[chan exchange.PriceUpdate]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber for an exchange's price ticks
+func (h *MarketDataHub) Subscribe(exchangeName string) chan exchange.PriceUpdate {
+	ch := make(chan exchange.PriceUpdate, marketDataBufferSize)
+
+	h.mu.Lock()
+	if h.subscribers[exchangeName] == nil {
+		h.subscribers[exchangeName] = make(map[chan exchange.PriceUpdate]struct{})
+	}
+	h.subscribers[exchangeName][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch
+}
+
+// Unsubscribe removes a subscriber
+func (h *MarketDataHub) Unsubscribe(exchangeName string, ch chan exchange.PriceUpdate) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subscribers[exchangeName], ch)
+	if len(h.subscribers[exchangeName]) == 0 {
+		delete(h.subscribers, exchangeName)
+	}
+}
+
+// OnPriceUpdate implements exchange.PriceSubscriber
+func (h *MarketDataHub) OnPriceUpdate(update exchange.PriceUpdate) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers[update.Exchange] {
+		select {
+		case ch <- update:
+		default:
+			// Subscriber is not keeping up, drop the tick
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/ccxt-simulator/internal/exchange"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMarketDataHubRoutesByExchange tests that ticks only reach subscribers of the same exchange
+func TestMarketDataHubRoutesByExchange(t *testing.T) {
+	hub := NewMarketDataHub()
+	okxTicks := hub.Subscribe("okx")
+	bybitTicks := hub.Subscribe("bybit")
+
+	hub.OnPriceUpdate(exchange.PriceUpdate{Exchange: "okx", Symbol: "BTCUSDT", Price: 100})
+
+	assert.Len(t, okxTicks, 1)
+	assert.Len(t, bybitTicks, 0)
+	assert.Equal(t, 100.0, (<-okxTicks).Price)
+
+	hub.Unsubscribe("okx", okxTicks)
+	hub.OnPriceUpdate(exchange.PriceUpdate{Exchange: "okx", Symbol: "BTCUSDT", Price: 101})
+	assert.Len(t, okxTicks, 0)
+}
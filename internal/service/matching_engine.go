@@ -0,0 +1,218 @@
+package service
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ccxt-simulator/internal/exchange"
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/ccxt-simulator/internal/repository"
+)
+
+const (
+	matchingFillQueueSize  = 1024
+	matchingResyncInterval = 5 * time.Second
+)
+
+// restingOrder is the in-memory view of a resting limit order
+type restingOrder struct {
+	orderID uint
+	side    models.OrderSide
+	price   float64
+}
+
+// MatchingEngine fills resting limit orders when live prices cross them
+// It implements exchange.PriceSubscriber and is fed by PriceService
+type MatchingEngine struct {
+	tradingService *TradingService
+	orderRepo      *repository.OrderRepository
+	accountRepo    *repository.AccountRepository
+
+	books    map[string]map[string]map[uint]restingOrder // exchange -> symbol -> orderID -> order
+	booksMux sync.Mutex
+
+	fills    chan uint
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewMatchingEngine creates a new MatchingEngine
+func NewMatchingEngine(
+	tradingService *TradingService,
+	orderRepo *repository.OrderRepository,
+	accountRepo *repository.AccountRepository,
+) *MatchingEngine {
+	return &MatchingEngine{
+		tradingService: tradingService,
+		orderRepo:      orderRepo,
+		accountRepo:    accountRepo,
+		books:          make(map[string]map[string]map[uint]restingOrder),
+		fills:          make(chan uint, matchingFillQueueSize),
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Start loads resting orders from the database and starts the fill loop
+func (e *MatchingEngine) Start() {
+	e.resync()
+
+	e.wg.Add(2)
+	go e.fillLoop()
+	go e.resyncLoop()
+
+	log.Printf("[MatchingEngine] Started")
+}
+
+// Stop stops the fill loop
+func (e *MatchingEngine) Stop() {
+	close(e.stopChan)
+	e.wg.Wait()
+	log.Printf("[MatchingEngine] Stopped")
+}
+
+// Track adds a resting limit order to the book
+func (e *MatchingEngine) Track(order *models.Order, exchangeType models.ExchangeType) {
+	e.booksMux.Lock()
+	defer e.booksMux.Unlock()
+	e.addLocked(string(exchangeType), order)
+}
+
+// OnPriceUpdate implements exchange.PriceSubscriber
+// Orders whose price is crossed by the quote are removed from the book and queued for fill
+func (e *MatchingEngine) OnPriceUpdate(update exchange.PriceUpdate) {
+	bid := update.BidPrice
+	if bid <= 0 {
+		bid = update.Price
+	}
+	ask := update.AskPrice
+	if ask <= 0 {
+		ask = update.Price
+	}
+	if bid <= 0 || ask <= 0 {
+		return
+	}
+
+	e.booksMux.Lock()
+	defer e.booksMux.Unlock()
+
+	book := e.books[update.Exchange][update.Symbol]
+	for id, order := range book {
+		if !isCrossed(order, bid, ask) {
+			continue
+		}
+
+		select {
+		case e.fills <- id:
+			delete(book, id)
+		default:
+			// Queue is full, keep the order and retry on the next tick
+			return
+		}
+	}
+}
+
+// isCrossed reports whether the best quote reaches a resting order's limit price
+// A buy rests below the market and fills once the ask drops to it; a sell fills once the bid rises to it
+func isCrossed(order restingOrder, bid, ask float64) bool {
+	if order.side == models.OrderSideBuy {
+		return ask <= order.price
+	}
+	return bid >= order.price
+}
+
+func (e *MatchingEngine) addLocked(exchangeName string, order *models.Order) {
+	if e.books[exchangeName] == nil {
+		e.books[exchangeName] = make(map[string]map[uint]restingOrder)
+	}
+	if e.books[exchangeName][order.Symbol] == nil {
+		e.books[exchangeName][order.Symbol] = make(map[uint]restingOrder)
+	}
+	e.books[exchangeName][order.Symbol][order.ID] = restingOrder{
+		orderID: order.ID,
+		side:    order.Side,
+		price:   order.Price,
+	}
+}
+
+// fillLoop executes queued fills one at a time so an order is never filled twice
+func (e *MatchingEngine) fillLoop() {
+	defer e.wg.Done()
+
+	for {
+		select {
+		case <-e.stopChan:
+			return
+		case orderID := <-e.fills:
+			order, position, err := e.tradingService.FillLimitOrder(orderID)
+			if err != nil {
+				if err != ErrOrderNotOpen && err != ErrOrderNotFound {
+					log.Printf("[MatchingEngine] Failed to fill order %d: %v", orderID, err)
+				}
+				continue
+			}
+			log.Printf("[MatchingEngine] Filled order %d (symbol=%s, side=%s, qty=%.8f, price=%.8f, position=%d)",
+				order.ID, order.Symbol, order.Side, order.FilledQty, order.AvgPrice, position.ID)
+		}
+	}
+}
+
+// resyncLoop periodically rebuilds the book from the database
+// This drops orders canceled through the repository, picks up orders created elsewhere and expires GTD orders
+func (e *MatchingEngine) resyncLoop() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(matchingResyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.stopChan:
+			return
+		case <-ticker.C:
+			e.resync()
+		}
+	}
+}
+
+func (e *MatchingEngine) resync() {
+	orders, err := e.orderRepo.GetAllOpenOrdersByType(models.OrderTypeLimit)
+	if err != nil {
+		log.Printf("[MatchingEngine] Failed to load resting orders: %v", err)
+		return
+	}
+
+	now := time.Now()
+	exchangeByAccount := make(map[uint]models.ExchangeType)
+	grouped := make(map[string][]*models.Order)
+	for i := range orders {
+		order := &orders[i]
+
+		// GTD orders past their good till date are expired instead of resting again
+		if order.GoodTillDate != nil && now.After(*order.GoodTillDate) {
+			e.tradingService.ExpireOrder(order.ID)
+			continue
+		}
+
+		exchangeType, ok := exchangeByAccount[order.AccountID]
+		if !ok {
+			account, err := e.accountRepo.GetByID(order.AccountID)
+			if err != nil {
+				continue
+			}
+			exchangeType = account.ExchangeType
+			exchangeByAccount[order.AccountID] = exchangeType
+		}
+		grouped[string(exchangeType)] = append(grouped[string(exchangeType)], order)
+	}
+
+	e.booksMux.Lock()
+	defer e.booksMux.Unlock()
+
+	e.books = make(map[string]map[string]map[uint]restingOrder)
+	for exchangeName, exchangeOrders := range grouped {
+		for _, order := range exchangeOrders {
+			e.addLocked(exchangeName, order)
+		}
+	}
+}
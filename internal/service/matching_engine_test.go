@@ -0,0 +1,36 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/ccxt-simulator/internal/exchange"
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIsCrossed tests limit order crossing against bid/ask
+func TestIsCrossed(t *testing.T) {
+	buy := restingOrder{orderID: 1, side: models.OrderSideBuy, price: 100}
+	sell := restingOrder{orderID: 2, side: models.OrderSideSell, price: 100}
+
+	assert.False(t, isCrossed(buy, 100.5, 101), "Buy should rest while ask is above limit")
+	assert.True(t, isCrossed(buy, 99.5, 100), "Buy should fill when ask reaches limit")
+	assert.False(t, isCrossed(sell, 99, 99.5), "Sell should rest while bid is below limit")
+	assert.True(t, isCrossed(sell, 100, 100.5), "Sell should fill when bid reaches limit")
+}
+
+// TestMatchingEngineQueuesCrossedOrders tests that crossed orders leave the book and are queued
+func TestMatchingEngineQueuesCrossedOrders(t *testing.T) {
+	engine := NewMatchingEngine(nil, nil, nil)
+	engine.Track(&models.Order{ID: 1, Symbol: "BTCUSDT", Side: models.OrderSideBuy, Price: 100}, models.ExchangeBinance)
+	engine.Track(&models.Order{ID: 2, Symbol: "BTCUSDT", Side: models.OrderSideBuy, Price: 90}, models.ExchangeBinance)
+
+	// Same symbol on another exchange must not match
+	engine.OnPriceUpdate(exchange.PriceUpdate{Exchange: "okx", Symbol: "BTCUSDT", Price: 95})
+	assert.Len(t, engine.fills, 0)
+
+	engine.OnPriceUpdate(exchange.PriceUpdate{Exchange: "binance", Symbol: "BTCUSDT", Price: 95})
+	assert.Len(t, engine.fills, 1)
+	assert.Equal(t, uint(1), <-engine.fills)
+	assert.Len(t, engine.books["binance"]["BTCUSDT"], 1, "Uncrossed order should keep resting")
+}
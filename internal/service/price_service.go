@@ -4,12 +4,16 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/ccxt-simulator/internal/config"
 	"github.com/ccxt-simulator/internal/exchange"
 	"github.com/ccxt-simulator/internal/exchange/binance"
+	"github.com/ccxt-simulator/internal/exchange/bitget"
 	"github.com/ccxt-simulator/internal/exchange/bybit"
+	"github.com/ccxt-simulator/internal/exchange/hyperliquid"
 	"github.com/ccxt-simulator/internal/exchange/okx"
 	"github.com/redis/go-redis/v9"
 )
@@ -22,24 +26,37 @@ var DefaultSymbols = []string{
 	"XLMUSDT", "FILUSDT", "TRXUSDT", "NEARUSDT", "AAVEUSDT",
 }
 
+// defaultMaintenanceMarginRate is used when an exchange has no tiered MMR table
+const defaultMaintenanceMarginRate = 0.004
+
 // PriceService manages real-time price data from multiple exchanges
 type PriceService struct {
 	redis     *redis.Client
+	fallback  map[string]string // exchange -> fallback price source
 	providers map[string]exchange.PriceProvider
 	prices    map[string]map[string]exchange.PriceUpdate // exchange -> symbol -> price
 	pricesMux sync.RWMutex
 
+	subscribers    []exchange.PriceSubscriber
+	subscribersMux sync.RWMutex
+
+	marketData *MarketDataHub
+	candles    *CandleStore
+
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
 }
 
 // NewPriceService creates a new PriceService
-func NewPriceService(redisClient *redis.Client) *PriceService {
+func NewPriceService(redisClient *redis.Client, cfg config.PriceConfig) *PriceService {
 	return &PriceService{
-		redis:     redisClient,
-		providers: make(map[string]exchange.PriceProvider),
-		prices:    make(map[string]map[string]exchange.PriceUpdate),
+		redis:      redisClient,
+		fallback:   cfg.Fallback,
+		providers:  make(map[string]exchange.PriceProvider),
+		prices:     make(map[string]map[string]exchange.PriceUpdate),
+		marketData: NewMarketDataHub(),
+		candles:    NewCandleStore(),
 	}
 }
 
@@ -51,21 +68,29 @@ func (s *PriceService) Start(ctx context.Context) error {
 	binanceClient := binance.NewClient()
 	okxClient := okx.NewClient()
 	bybitClient := bybit.NewClient()
+	bitgetClient := bitget.NewClient()
+	hyperliquidClient := hyperliquid.NewClient()
 
 	// Set this service as the subscriber for all exchanges
 	binanceClient.SetSubscriber(s)
 	okxClient.SetSubscriber(s)
 	bybitClient.SetSubscriber(s)
+	bitgetClient.SetSubscriber(s)
+	hyperliquidClient.SetSubscriber(s)
 
 	// Store providers
 	s.providers["binance"] = binanceClient
 	s.providers["okx"] = okxClient
 	s.providers["bybit"] = bybitClient
+	s.providers["bitget"] = bitgetClient
+	s.providers["hyperliquid"] = hyperliquidClient
 
 	// Initialize price maps
 	s.prices["binance"] = make(map[string]exchange.PriceUpdate)
 	s.prices["okx"] = make(map[string]exchange.PriceUpdate)
 	s.prices["bybit"] = make(map[string]exchange.PriceUpdate)
+	s.prices["bitget"] = make(map[string]exchange.PriceUpdate)
+	s.prices["hyperliquid"] = make(map[string]exchange.PriceUpdate)
 
 	// Connect to each exchange
 	for name, provider := range s.providers {
@@ -86,14 +111,19 @@ func (s *PriceService) Start(ctx context.Context) error {
 
 // OnPriceUpdate implements exchange.PriceSubscriber
 func (s *PriceService) OnPriceUpdate(update exchange.PriceUpdate) {
-	// Store in memory
+	// Store in memory; prices a partial update leaves out keep their last values
 	s.pricesMux.Lock()
 	if s.prices[update.Exchange] == nil {
 		s.prices[update.Exchange] = make(map[string]exchange.PriceUpdate)
 	}
+	update = update.Merge(s.prices[update.Exchange][update.Symbol])
 	s.prices[update.Exchange][update.Symbol] = update
 	s.pricesMux.Unlock()
 
+	if update.Price <= 0 {
+		return
+	}
+
 	// Store in Redis for persistence
 	key := fmt.Sprintf("price:%s:%s", update.Exchange, update.Symbol)
 
@@ -101,6 +131,9 @@ func (s *PriceService) OnPriceUpdate(update exchange.PriceUpdate) {
 		"price":     update.Price,
 		"bid":       update.BidPrice,
 		"ask":       update.AskPrice,
+		"last":      update.LastPrice,
+		"mark":      update.MarkPrice,
+		"index":     update.IndexPrice,
 		"timestamp": update.Timestamp,
 	})
 
@@ -109,23 +142,71 @@ func (s *PriceService) OnPriceUpdate(update exchange.PriceUpdate) {
 
 	// Publish price update for subscribers (e.g., trading engine)
 	s.redis.Publish(s.ctx, "price_updates", fmt.Sprintf("%s:%s:%.8f", update.Exchange, update.Symbol, update.Price))
+
+	// Fan out to in-process subscribers (e.g., matching engine)
+	s.subscribersMux.RLock()
+	subscribers := s.subscribers
+	s.subscribersMux.RUnlock()
+
+	for _, subscriber := range subscribers {
+		subscriber.OnPriceUpdate(update)
+	}
+
+	// Aggregate into candles for kline/candle snapshot endpoints
+	s.candles.OnPriceUpdate(update)
+
+	// Re-broadcast to public market-data WebSocket streams
+	s.marketData.OnPriceUpdate(update)
+}
+
+// MarketData returns the hub that carries live ticks to public WebSocket streams
+func (s *PriceService) MarketData() *MarketDataHub {
+	return s.marketData
+}
+
+// Candles returns the store of candles built from live ticks
+func (s *PriceService) Candles() *CandleStore {
+	return s.candles
+}
+
+// AddSubscriber registers an in-process subscriber for every price update
+func (s *PriceService) AddSubscriber(subscriber exchange.PriceSubscriber) {
+	s.subscribersMux.Lock()
+	defer s.subscribersMux.Unlock()
+	s.subscribers = append(s.subscribers, subscriber)
 }
 
 // GetPrice returns the current price for a symbol from a specific exchange
+// When the exchange has no price, the configured fallback source is used
 func (s *PriceService) GetPrice(exchangeName, symbol string) (float64, error) {
-	// Try memory cache first
-	s.pricesMux.RLock()
-	if prices, ok := s.prices[exchangeName]; ok {
-		if update, ok := prices[symbol]; ok {
-			s.pricesMux.RUnlock()
-			// Check if price is stale (> 5 seconds old)
-			if time.Now().UnixMilli()-update.Timestamp < 5000 {
-				return update.Price, nil
-			}
+	symbol = normalizeSymbol(exchangeName, symbol)
+
+	price, err := s.getVenuePrice(exchangeName, symbol)
+	if err == nil {
+		return price, nil
+	}
+
+	if source, ok := s.fallback[exchangeName]; ok && source != exchangeName {
+		if price, fallbackErr := s.getVenuePrice(source, symbol); fallbackErr == nil {
+			return price, nil
 		}
 	}
+
+	return 0, err
+}
+
+// getVenuePrice returns an exchange's own price from memory, Redis or its REST API
+func (s *PriceService) getVenuePrice(exchangeName, symbol string) (float64, error) {
+	// Try memory cache first
+	s.pricesMux.RLock()
+	update, ok := s.prices[exchangeName][symbol]
 	s.pricesMux.RUnlock()
 
+	// Skip stale prices (> 5 seconds old)
+	if ok && time.Now().UnixMilli()-update.Timestamp < 5000 {
+		return update.Price, nil
+	}
+
 	// Try Redis
 	key := fmt.Sprintf("price:%s:%s", exchangeName, symbol)
 	result, err := s.redis.HGet(s.ctx, key, "price").Float64()
@@ -148,6 +229,8 @@ func (s *PriceService) GetPrice(exchangeName, symbol string) (float64, error) {
 
 // GetPriceUpdate returns the full price update for a symbol
 func (s *PriceService) GetPriceUpdate(exchangeName, symbol string) (*exchange.PriceUpdate, error) {
+	symbol = normalizeSymbol(exchangeName, symbol)
+
 	s.pricesMux.RLock()
 	defer s.pricesMux.RUnlock()
 
@@ -160,6 +243,25 @@ func (s *PriceService) GetPriceUpdate(exchangeName, symbol string) (*exchange.Pr
 	return nil, fmt.Errorf("price not found for %s on %s", symbol, exchangeName)
 }
 
+// GetQuote returns the live quote for a symbol, with bid/ask when the feed carries them
+// A stale or missing tick falls back to a last-price-only quote from GetPrice
+func (s *PriceService) GetQuote(exchangeName, symbol string) (*exchange.PriceUpdate, error) {
+	if update, err := s.GetPriceUpdate(exchangeName, symbol); err == nil && time.Now().UnixMilli()-update.Timestamp < 5000 {
+		return update, nil
+	}
+
+	price, err := s.GetPrice(exchangeName, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return &exchange.PriceUpdate{
+		Exchange:  exchangeName,
+		Symbol:    normalizeSymbol(exchangeName, symbol),
+		Price:     price,
+		Timestamp: time.Now().UnixMilli(),
+	}, nil
+}
+
 // GetAllPrices returns all current prices for an exchange
 func (s *PriceService) GetAllPrices(exchangeName string) map[string]float64 {
 	s.pricesMux.RLock()
@@ -175,6 +277,25 @@ func (s *PriceService) GetAllPrices(exchangeName string) map[string]float64 {
 	return result
 }
 
+// normalizeSymbol maps venue-native symbols to the standard BTCUSDT form
+// used as the price key, e.g. Hyperliquid coin names (BTC) and Bitget v1 ids (BTCUSDT_UMCBL)
+func normalizeSymbol(exchangeName, symbol string) string {
+	switch exchangeName {
+	case "hyperliquid":
+		// Coin names are case-sensitive (e.g. kPEPE)
+		if !strings.HasSuffix(symbol, "USDT") && !strings.HasSuffix(symbol, "USD") {
+			return symbol + "USDT"
+		}
+	case "bitget":
+		symbol = strings.ToUpper(symbol)
+		if i := strings.Index(symbol, "_"); i > 0 {
+			return symbol[:i]
+		}
+	}
+
+	return symbol
+}
+
 // Subscribe subscribes to price updates for additional symbols
 func (s *PriceService) Subscribe(exchangeName string, symbols []string) error {
 	provider, ok := s.providers[exchangeName]
@@ -205,6 +326,27 @@ func (s *PriceService) GetSymbolInfo(exchangeName, symbol string) (*exchange.Sym
 	return provider.GetSymbolInfo(symbol)
 }
 
+// GetMaintenanceMarginRate returns the exchange's maintenance margin rate for a position value
+// Falls back to the default 0.4% tier when the provider does not expose tiered rates
+func (s *PriceService) GetMaintenanceMarginRate(exchangeName string, positionValue float64) float64 {
+	if provider, ok := s.providers[exchangeName]; ok {
+		if adapter, ok := provider.(exchange.ExchangeAdapter); ok {
+			return adapter.GetMaintenanceMarginRate(positionValue)
+		}
+	}
+	return defaultMaintenanceMarginRate
+}
+
+// GetFundingRate returns the exchange's current funding rate for a symbol
+func (s *PriceService) GetFundingRate(exchangeName, symbol string) (float64, error) {
+	if provider, ok := s.providers[exchangeName]; ok {
+		if adapter, ok := provider.(exchange.ExchangeAdapter); ok {
+			return adapter.GetFundingRate(symbol)
+		}
+	}
+	return 0, fmt.Errorf("funding rate not available for exchange: %s", exchangeName)
+}
+
 // GetProvider returns the exchange provider
 func (s *PriceService) GetProvider(exchangeName string) (exchange.PriceProvider, bool) {
 	provider, ok := s.providers[exchangeName]
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ccxt-simulator/internal/config"
+	"github.com/ccxt-simulator/internal/exchange"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPriceServiceFallbackSource tests that an exchange without its own price uses the configured fallback venue
+func TestPriceServiceFallbackSource(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	defer rdb.Close()
+
+	s := NewPriceService(rdb, config.PriceConfig{Fallback: map[string]string{"hyperliquid": "binance"}})
+	s.ctx = context.Background()
+
+	now := time.Now().UnixMilli()
+	s.prices["binance"] = map[string]exchange.PriceUpdate{
+		"BTCUSDT": {Exchange: "binance", Symbol: "BTCUSDT", Price: 100, Timestamp: now},
+	}
+	s.prices["hyperliquid"] = map[string]exchange.PriceUpdate{}
+
+	// Hyperliquid coin names map to the standard symbol
+	price, err := s.GetPrice("hyperliquid", "BTC")
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, price)
+
+	// The venue's own price wins once it is available
+	s.prices["hyperliquid"]["BTCUSDT"] = exchange.PriceUpdate{Exchange: "hyperliquid", Symbol: "BTCUSDT", Price: 101, Timestamp: now}
+	price, err = s.GetPrice("hyperliquid", "BTCUSDT")
+	assert.NoError(t, err)
+	assert.Equal(t, 101.0, price)
+
+	// No fallback is configured for bitget
+	_, err = s.GetPrice("bitget", "BTCUSDT")
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"github.com/ccxt-simulator/internal/exchange"
+	"github.com/ccxt-simulator/internal/models"
+)
+
+// quotePrice returns the side of the quote a taker order trades against: buys take the ask, sells the bid
+func quotePrice(quote exchange.PriceUpdate, side models.OrderSide) float64 {
+	if side == models.OrderSideBuy {
+		return quote.BestAsk()
+	}
+	return quote.BestBid()
+}
+
+// fillPrice returns the price a taker order of the given side and size fills at against the quote
+// The account's slippage model moves the best bid/ask against the taker
+func fillPrice(account *models.Account, quote exchange.PriceUpdate, side models.OrderSide, quantity float64) float64 {
+	price := quotePrice(quote, side)
+
+	var slippage float64 // fraction of the quote price
+	switch account.SlippageModel {
+	case models.SlippageNone:
+	case models.SlippageSpread:
+		if spread := quote.BestAsk() - quote.BestBid(); spread > 0 && price > 0 {
+			slippage = spread / 2 / price
+		}
+	case models.SlippageImpact:
+		if account.SlippageDepth > 0 {
+			slippage = account.SlippageBps / 10000 * price * quantity / account.SlippageDepth
+		}
+	default:
+		// Fixed is also the fallback for accounts created before slippage models existed
+		slippage = account.SlippageBps / 10000
+	}
+
+	if side == models.OrderSideBuy {
+		return price * (1 + slippage)
+	}
+	return price * (1 - slippage)
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/ccxt-simulator/internal/exchange"
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFillPriceSlippageModels tests taker fill prices against the quote for each slippage model
+func TestFillPriceSlippageModels(t *testing.T) {
+	quote := exchange.PriceUpdate{Price: 100, BidPrice: 99, AskPrice: 101}
+
+	none := &models.Account{SlippageModel: models.SlippageNone}
+	assert.Equal(t, 101.0, fillPrice(none, quote, models.OrderSideBuy, 1), "Buys should take the ask")
+	assert.Equal(t, 99.0, fillPrice(none, quote, models.OrderSideSell, 1), "Sells should take the bid")
+
+	fixed := &models.Account{SlippageModel: models.SlippageFixed, SlippageBps: 100}
+	assert.InDelta(t, 102.01, fillPrice(fixed, quote, models.OrderSideBuy, 1), 1e-9)
+	assert.InDelta(t, 98.01, fillPrice(fixed, quote, models.OrderSideSell, 1), 1e-9)
+
+	spread := &models.Account{SlippageModel: models.SlippageSpread}
+	assert.InDelta(t, 102.0, fillPrice(spread, quote, models.OrderSideBuy, 1), 1e-9)
+	assert.InDelta(t, 98.0, fillPrice(spread, quote, models.OrderSideSell, 1), 1e-9)
+
+	// 10 bps per 10,000 USDT of notional: 50 units at the 101 ask is 5,050 USDT
+	impact := &models.Account{SlippageModel: models.SlippageImpact, SlippageBps: 10, SlippageDepth: 10000}
+	assert.InDelta(t, 101*(1+0.001*0.505), fillPrice(impact, quote, models.OrderSideBuy, 50), 1e-9)
+
+	// Without book data the last price stands in for both sides
+	assert.Equal(t, 100.0, fillPrice(none, exchange.PriceUpdate{Price: 100}, models.OrderSideBuy, 1))
+}
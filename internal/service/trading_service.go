@@ -3,8 +3,8 @@ package service
 import (
 	"errors"
 	"fmt"
+	"log"
 	"math"
-	"sync"
 	"time"
 
 	"github.com/ccxt-simulator/internal/exchange"
@@ -22,23 +22,39 @@ var (
 	ErrOrderNotFound       = errors.New("order not found")
 	ErrNoOpenPosition      = errors.New("no open position to close")
 	ErrInvalidOrderType    = errors.New("invalid order type")
-)
-
-const (
-	defaultSlippage = 0.0001 // 0.01% slippage for market orders
+	ErrInvalidPrice        = errors.New("invalid price")
+	ErrOrderNotOpen        = errors.New("order is not open")
+	ErrInvalidMarginMode   = errors.New("invalid margin mode")
+	ErrSettingNotChanged   = errors.New("no need to change setting")
+	ErrPositionExists      = errors.New("setting cannot be changed while a position or open order exists")
+	ErrNotIsolated         = errors.New("position is not in isolated margin mode")
+	ErrInvalidAmount       = errors.New("invalid amount")
+	ErrInsufficientMargin  = errors.New("insufficient isolated margin")
+	ErrOrderNotModified    = errors.New("no need to modify the order")
+	ErrInvalidTimeInForce  = errors.New("invalid time in force")
+	ErrInvalidGoodTillDate = errors.New("good till date must be in the future")
+	ErrDuplicateClientID   = errors.New("client order ID is already used by an open order")
+	ErrInvalidCallback     = errors.New("trailing stop needs a callback rate or spread")
+	ErrInvalidTrigger      = errors.New("invalid trigger price type")
+
+	// Limit orders stored as EXPIRED at placement because their time in force could not be met
+	ErrIOCNotFilled      = errors.New("IOC order could not be filled immediately")
+	ErrFOKNotFilled      = errors.New("FOK order could not be filled completely")
+	ErrPostOnlyWouldTake = errors.New("post-only order would immediately match")
 )
 
 // TradingService handles trading operations
 type TradingService struct {
-	accountRepo   *repository.AccountRepository
-	positionRepo  *repository.PositionRepository
-	orderRepo     *repository.OrderRepository
-	tradeRepo     *repository.TradeRepository
-	closedPnLRepo *repository.ClosedPnLRepository
-	priceService  *PriceService
-
-	leverageCache map[uint]map[string]int // accountID -> symbol -> leverage
-	cacheMux      sync.RWMutex
+	accountRepo       *repository.AccountRepository
+	positionRepo      *repository.PositionRepository
+	orderRepo         *repository.OrderRepository
+	tradeRepo         *repository.TradeRepository
+	closedPnLRepo     *repository.ClosedPnLRepository
+	symbolSettingRepo *repository.SymbolSettingRepository
+	priceService      *PriceService
+
+	matchingEngine *MatchingEngine
+	events         *UserEventHub
 }
 
 // NewTradingService creates a new TradingService
@@ -48,31 +64,46 @@ func NewTradingService(
 	orderRepo *repository.OrderRepository,
 	tradeRepo *repository.TradeRepository,
 	closedPnLRepo *repository.ClosedPnLRepository,
+	symbolSettingRepo *repository.SymbolSettingRepository,
 	priceService *PriceService,
 ) *TradingService {
 	return &TradingService{
-		accountRepo:   accountRepo,
-		positionRepo:  positionRepo,
-		orderRepo:     orderRepo,
-		tradeRepo:     tradeRepo,
-		closedPnLRepo: closedPnLRepo,
-		priceService:  priceService,
-		leverageCache: make(map[uint]map[string]int),
+		accountRepo:       accountRepo,
+		positionRepo:      positionRepo,
+		orderRepo:         orderRepo,
+		tradeRepo:         tradeRepo,
+		closedPnLRepo:     closedPnLRepo,
+		symbolSettingRepo: symbolSettingRepo,
+		priceService:      priceService,
+		events:            NewUserEventHub(),
 	}
 }
 
+// SetMatchingEngine attaches the engine that fills resting limit orders
+func (s *TradingService) SetMatchingEngine(engine *MatchingEngine) {
+	s.matchingEngine = engine
+}
+
+// Events returns the hub that carries order, balance and position events
+func (s *TradingService) Events() *UserEventHub {
+	return s.events
+}
+
 // OpenPositionRequest represents a request to open a position
 type OpenPositionRequest struct {
-	AccountID  uint                `json:"account_id"`
-	Symbol     string              `json:"symbol" binding:"required"`
-	Side       models.PositionSide `json:"side" binding:"required"`
-	Quantity   float64             `json:"quantity" binding:"required,gt=0"`
-	Leverage   int                 `json:"leverage" binding:"omitempty,min=1,max=125"`
-	OrderType  models.OrderType    `json:"order_type"`
-	Price      float64             `json:"price"` // For limit orders
-	StopLoss   *float64            `json:"stop_loss"`
-	TakeProfit *float64            `json:"take_profit"`
-	ReduceOnly bool                `json:"reduce_only"`
+	AccountID     uint                `json:"account_id"`
+	Symbol        string              `json:"symbol" binding:"required"`
+	Side          models.PositionSide `json:"side" binding:"required"`
+	Quantity      float64             `json:"quantity" binding:"required,gt=0"`
+	Leverage      int                 `json:"leverage" binding:"omitempty,min=1,max=125"`
+	OrderType     models.OrderType    `json:"order_type"`
+	Price         float64             `json:"price"` // For limit orders
+	StopLoss      *float64            `json:"stop_loss"`
+	TakeProfit    *float64            `json:"take_profit"`
+	ReduceOnly    bool                `json:"reduce_only"`
+	ClientOrderID string              `json:"client_order_id"` // Generated when empty
+	TimeInForce   string              `json:"time_in_force"`   // Limit orders only, GTC when empty
+	GoodTillDate  time.Time           `json:"good_till_date"`  // Required for GTD
 }
 
 // ClosePositionRequest represents a request to close a position
@@ -86,6 +117,16 @@ type ClosePositionRequest struct {
 	StopPrice     float64             `json:"stop_price"` // For SL/TP orders
 	ClosePosition bool                `json:"close_position"`
 	ReduceOnly    bool                `json:"reduce_only"`
+	ClientOrderID string              `json:"client_order_id"` // Generated when empty
+	TimeInForce   string              `json:"time_in_force"`   // Limit orders only, GTC when empty
+	GoodTillDate  time.Time           `json:"good_till_date"`  // Required for GTD
+}
+
+// AmendOrderRequest represents an in-place change to a resting order; nil fields are left unchanged
+type AmendOrderRequest struct {
+	Price     *float64 `json:"price"`
+	Quantity  *float64 `json:"quantity"`
+	StopPrice *float64 `json:"stop_price"` // Conditional orders only
 }
 
 // ConditionalOrderRequest represents a request to create a conditional order (SL/TP)
@@ -95,11 +136,20 @@ type ConditionalOrderRequest struct {
 	Symbol        string              `json:"symbol" binding:"required"`
 	Side          models.PositionSide `json:"side"`
 	Quantity      float64             `json:"quantity"`
-	OrderType     models.OrderType    `json:"order_type"` // STOP_MARKET or TAKE_PROFIT
-	StopPrice     float64             `json:"stop_price"` // Trigger price
+	OrderType     models.OrderType    `json:"order_type"` // STOP_MARKET, TAKE_PROFIT or TRAILING_STOP_MARKET
+	StopPrice     float64             `json:"stop_price"` // Trigger price, not used by trailing stops
 	Price         float64             `json:"price"`      // Execution price (for limit type)
 	ClosePosition bool                `json:"close_position"`
 	ReduceOnly    bool                `json:"reduce_only"`
+	ClientOrderID string              `json:"client_order_id"` // Generated when empty
+
+	// Trailing stops only: one of CallbackRate (fraction) or CallbackSpread (price distance)
+	CallbackRate    float64 `json:"callback_rate"`
+	CallbackSpread  float64 `json:"callback_spread"`
+	ActivationPrice float64 `json:"activation_price"`
+
+	// Price stream the trigger is checked against; empty means LAST
+	TriggerPriceType models.TriggerPriceType `json:"trigger_price_type"`
 }
 
 // OpenPosition opens a new position or adds to an existing one
@@ -110,42 +160,50 @@ func (s *TradingService) OpenPosition(req *OpenPositionRequest, exchangeType mod
 		return nil, nil, err
 	}
 
+	if err := s.checkClientOrderID(req.AccountID, req.ClientOrderID); err != nil {
+		return nil, nil, err
+	}
+
 	// Validate symbol
 	symbolInfo, err := s.priceService.GetSymbolInfo(string(exchangeType), req.Symbol)
 	if err != nil {
 		return nil, nil, ErrInvalidSymbol
 	}
 
-	// Get current price
-	currentPrice, err := s.priceService.GetPrice(string(exchangeType), req.Symbol)
+	// Get current quote
+	quote, err := s.priceService.GetQuote(string(exchangeType), req.Symbol)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get price: %w", err)
 	}
 
-	// Apply slippage for market orders
-	executionPrice := currentPrice
+	// Market orders take the best quote, moved by the account's slippage model
+	executionPrice := quote.Price
 	if req.OrderType == "" || req.OrderType == models.OrderTypeMarket {
 		req.OrderType = models.OrderTypeMarket
-		if req.Side == models.PositionSideLong {
-			executionPrice = currentPrice * (1 + defaultSlippage)
-		} else {
-			executionPrice = currentPrice * (1 - defaultSlippage)
-		}
+		executionPrice = fillPrice(account, *quote, s.getSide(req.Side, true), req.Quantity)
 	} else if req.OrderType == models.OrderTypeLimit {
+		if req.Price <= 0 {
+			return nil, nil, ErrInvalidPrice
+		}
 		executionPrice = req.Price
 	}
 
+	timeInForce, goodTillDate, err := validateTimeInForce(req.OrderType, req.TimeInForce, req.GoodTillDate)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Round price to precision
 	executionPrice = s.roundPrice(executionPrice, symbolInfo)
 
 	// Get or set leverage
 	leverage := req.Leverage
 	if leverage == 0 {
-		leverage = s.getLeverage(account.ID, req.Symbol, account.DefaultLeverage)
+		leverage = s.getLeverage(account, req.Symbol)
 	}
 
 	// Validate quantity
-	if req.Quantity < symbolInfo.MinQty || req.Quantity > symbolInfo.MaxQty {
+	if !symbolInfo.ValidQuantity(req.Quantity) {
 		return nil, nil, ErrInvalidQuantity
 	}
 
@@ -162,7 +220,7 @@ func (s *TradingService) OpenPosition(req *OpenPositionRequest, exchangeType mod
 	// Create order
 	order := &models.Order{
 		AccountID:     req.AccountID,
-		ClientOrderID: uuid.New().String(),
+		ClientOrderID: clientOrderID(req.ClientOrderID),
 		Symbol:        req.Symbol,
 		Side:          s.getSide(req.Side, true),
 		PositionSide:  req.Side,
@@ -171,22 +229,154 @@ func (s *TradingService) OpenPosition(req *OpenPositionRequest, exchangeType mod
 		Price:         req.Price,
 		Status:        models.OrderStatusNew,
 		ReduceOnly:    req.ReduceOnly,
+		TimeInForce:   timeInForce,
+		GoodTillDate:  goodTillDate,
+	}
+
+	// Limit orders whose time in force cannot be met against the quote are stored as EXPIRED
+	var crosses bool
+	var takerPrice float64
+	if req.OrderType == models.OrderTypeLimit {
+		crosses, takerPrice = s.crossesQuote(string(exchangeType), req.Symbol, order.Side, executionPrice)
+		if err := timeInForceError(timeInForce, crosses); err != nil {
+			order.Status = models.OrderStatusExpired
+			if createErr := s.orderRepo.Create(order); createErr != nil {
+				return nil, nil, fmt.Errorf("failed to create order: %w", createErr)
+			}
+			s.events.PublishOrder(order, nil)
+			return order, nil, err
+		}
 	}
 
 	if err := s.orderRepo.Create(order); err != nil {
 		return nil, nil, fmt.Errorf("failed to create order: %w", err)
 	}
+	s.events.PublishOrder(order, nil)
 
 	// For market orders, execute immediately
 	if req.OrderType == models.OrderTypeMarket {
-		return s.executeOpenOrder(order, account, symbolInfo, executionPrice, leverage, fee, req.StopLoss, req.TakeProfit)
+		return s.executeOpenOrder(order, account, symbolInfo, executionPrice, leverage, fee, req.StopLoss, req.TakeProfit, false)
+	}
+
+	// Limits that cross take the best quote right away as taker; no depth is simulated, so they fill in full
+	if crosses {
+		takerPrice = s.roundPrice(takerPrice, symbolInfo)
+		fee = takerPrice * req.Quantity * account.TakerFeeRate
+		return s.executeOpenOrder(order, account, symbolInfo, takerPrice, leverage, fee, req.StopLoss, req.TakeProfit, false)
 	}
 
-	// For limit orders, return pending order
+	// For limit orders, hand the order to the matching engine and return it pending
+	if s.matchingEngine != nil {
+		s.matchingEngine.Track(order, exchangeType)
+	}
 	return order, nil, nil
 }
 
-// executeOpenOrder executes a market open order
+// FillLimitOrder fills a resting limit order at its limit price as maker
+// This is called by the matching engine when the market crosses the order price
+func (s *TradingService) FillLimitOrder(orderID uint) (*models.Order, *models.Position, error) {
+	order, err := s.orderRepo.GetByID(orderID)
+	if err != nil {
+		return nil, nil, ErrOrderNotFound
+	}
+	if !order.IsPending() || order.Type != models.OrderTypeLimit {
+		return nil, nil, ErrOrderNotOpen
+	}
+	if order.GoodTillDate != nil && time.Now().After(*order.GoodTillDate) {
+		s.ExpireOrder(order.ID)
+		return nil, nil, ErrOrderNotOpen
+	}
+
+	account, err := s.accountRepo.GetByID(order.AccountID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	symbolInfo, err := s.priceService.GetSymbolInfo(string(account.ExchangeType), order.Symbol)
+	if err != nil {
+		return nil, nil, ErrInvalidSymbol
+	}
+
+	executionPrice := s.roundPrice(order.Price, symbolInfo)
+	if order.ReduceOnly {
+		if err := s.claimOrder(order, models.OrderStatusFilled); err != nil {
+			return nil, nil, err
+		}
+		return s.fillLimitClose(order, account, executionPrice, true)
+	}
+	leverage := s.getLeverage(account, order.Symbol)
+
+	positionValue := executionPrice * order.Quantity
+	requiredMargin := positionValue / float64(leverage)
+	fee := positionValue * account.MakerFeeRate
+
+	// Balance may have changed since the order was placed
+	if account.BalanceUSDT < requiredMargin+fee {
+		order.Status = models.OrderStatusCanceled
+		if canceled, _ := s.orderRepo.UpdateIfOpen(order); canceled {
+			s.events.PublishOrder(order, nil)
+		}
+		return nil, nil, ErrInsufficientBalance
+	}
+
+	if err := s.claimOrder(order, models.OrderStatusFilled); err != nil {
+		return nil, nil, err
+	}
+	return s.executeOpenOrder(order, account, symbolInfo, executionPrice, leverage, fee, nil, nil, true)
+}
+
+// claimOrder moves an order read as open to status, unless a concurrent cancel, amend or fill changed it since
+// Fills of resting and triggered orders claim the order first, so they never land on an order that was
+// canceled or re-priced after it was read
+func (s *TradingService) claimOrder(order *models.Order, status models.OrderStatus) error {
+	previous := order.Status
+	order.Status = status
+	claimed, err := s.orderRepo.UpdateIfUnchanged(order, order.Price, order.Quantity)
+	if err != nil || !claimed {
+		order.Status = previous
+		if err != nil {
+			return err
+		}
+		return ErrOrderNotOpen
+	}
+	return nil
+}
+
+// fillLimitClose fills a reduce-only limit order, closing no more than what is left of the position
+// The order is canceled when its position is already closed
+func (s *TradingService) fillLimitClose(order *models.Order, account *models.Account, executionPrice float64, isMaker bool) (*models.Order, *models.Position, error) {
+	position, err := s.positionRepo.GetByAccountIDSymbolAndSide(order.AccountID, order.Symbol, order.PositionSide)
+	if err != nil {
+		order.Status = models.OrderStatusCanceled
+		s.orderRepo.Update(order)
+		s.events.PublishOrder(order, nil)
+		return nil, nil, ErrNoOpenPosition
+	}
+
+	closeQty := math.Min(order.Quantity, position.Quantity)
+	if order.ClosePosition {
+		closeQty = position.Quantity
+	}
+
+	// Limit orders placed by a triggered stop or take profit close with their parent's reason
+	closeReason := "manual"
+	if order.ParentOrderID != nil {
+		if parent, err := s.orderRepo.GetByID(*order.ParentOrderID); err == nil {
+			closeReason = triggerCloseReason(parent.Type)
+		}
+	}
+
+	feeRate := account.TakerFeeRate
+	if isMaker {
+		feeRate = account.MakerFeeRate
+	}
+	if _, err := s.executeCloseOrder(order, account, position, closeQty, executionPrice, feeRate, isMaker, closeReason); err != nil {
+		return nil, nil, err
+	}
+	return order, position, nil
+}
+
+// executeOpenOrder executes an open order and opens or extends the position
 func (s *TradingService) executeOpenOrder(
 	order *models.Order,
 	account *models.Account,
@@ -195,6 +385,7 @@ func (s *TradingService) executeOpenOrder(
 	leverage int,
 	fee float64,
 	stopLoss, takeProfit *float64,
+	isMaker bool,
 ) (*models.Order, *models.Position, error) {
 	positionValue := executionPrice * order.Quantity
 	margin := positionValue / float64(leverage)
@@ -217,7 +408,7 @@ func (s *TradingService) executeOpenOrder(
 		Price:       executionPrice,
 		Fee:         fee,
 		FeeCurrency: "USDT",
-		IsMaker:     false,
+		IsMaker:     isMaker,
 		ExecutedAt:  time.Now(),
 	}
 	if err := s.tradeRepo.Create(trade); err != nil {
@@ -236,7 +427,7 @@ func (s *TradingService) executeOpenOrder(
 		existingPosition.Quantity = totalQty
 		existingPosition.EntryPrice = avgPrice
 		existingPosition.Margin += margin
-		existingPosition.LiquidationPrice = s.calculateLiquidationPrice(avgPrice, leverage, order.PositionSide)
+		s.refreshLiquidationPrice(existingPosition, account.ExchangeType)
 
 		if stopLoss != nil {
 			existingPosition.StopLoss = stopLoss
@@ -252,19 +443,19 @@ func (s *TradingService) executeOpenOrder(
 	} else {
 		// Create new position
 		position = &models.Position{
-			AccountID:        order.AccountID,
-			Symbol:           order.Symbol,
-			Side:             order.PositionSide,
-			Quantity:         order.Quantity,
-			EntryPrice:       executionPrice,
-			MarkPrice:        executionPrice,
-			Leverage:         leverage,
-			MarginMode:       account.MarginMode,
-			Margin:           margin,
-			LiquidationPrice: s.calculateLiquidationPrice(executionPrice, leverage, order.PositionSide),
-			StopLoss:         stopLoss,
-			TakeProfit:       takeProfit,
+			AccountID:  order.AccountID,
+			Symbol:     order.Symbol,
+			Side:       order.PositionSide,
+			Quantity:   order.Quantity,
+			EntryPrice: executionPrice,
+			MarkPrice:  executionPrice,
+			Leverage:   leverage,
+			MarginMode: s.symbolSetting(account, order.Symbol).MarginMode,
+			Margin:     margin,
+			StopLoss:   stopLoss,
+			TakeProfit: takeProfit,
 		}
+		s.refreshLiquidationPrice(position, account.ExchangeType)
 
 		if err := s.positionRepo.Create(position); err != nil {
 			return nil, nil, err
@@ -273,11 +464,13 @@ func (s *TradingService) executeOpenOrder(
 
 	// Only deduct fee from balance (Binance-style: margin is tracked in position, not deducted from walletBalance)
 	// walletBalance = initial balance - fees +/- realized PnL
-	account.BalanceUSDT -= fee
-	if err := s.accountRepo.Update(account); err != nil {
+	if err := s.accountRepo.AddBalance(account, -fee); err != nil {
 		return nil, nil, err
 	}
 
+	s.events.PublishOrder(order, trade)
+	s.events.PublishAccount(account, UserEventReasonOrder, *position)
+
 	return order, position, nil
 }
 
@@ -289,6 +482,10 @@ func (s *TradingService) ClosePosition(req *ClosePositionRequest, exchangeType m
 		return nil, nil, err
 	}
 
+	if err := s.checkClientOrderID(req.AccountID, req.ClientOrderID); err != nil {
+		return nil, nil, err
+	}
+
 	// Find position
 	position, err := s.positionRepo.GetByAccountIDSymbolAndSide(req.AccountID, req.Symbol, req.Side)
 	if err != nil {
@@ -304,23 +501,80 @@ func (s *TradingService) ClosePosition(req *ClosePositionRequest, exchangeType m
 		closeQty = *req.Quantity
 	}
 
-	// Get current price
-	currentPrice, err := s.priceService.GetPrice(string(exchangeType), req.Symbol)
+	timeInForce, goodTillDate, err := validateTimeInForce(req.OrderType, req.TimeInForce, req.GoodTillDate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get current quote
+	quote, err := s.priceService.GetQuote(string(exchangeType), req.Symbol)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get price: %w", err)
 	}
 
-	// Apply slippage
-	executionPrice := currentPrice
+	// Limit closes whose time in force cannot be met against the quote are stored as EXPIRED
+	var crosses bool
+	if req.OrderType == models.OrderTypeLimit {
+		if req.Price <= 0 {
+			return nil, nil, ErrInvalidPrice
+		}
+		crosses, _ = s.crossesQuote(string(exchangeType), req.Symbol, s.getSide(req.Side, false), req.Price)
+		if err := timeInForceError(timeInForce, crosses); err != nil {
+			order := &models.Order{
+				AccountID:     req.AccountID,
+				ClientOrderID: clientOrderID(req.ClientOrderID),
+				Symbol:        req.Symbol,
+				Side:          s.getSide(req.Side, false),
+				PositionSide:  req.Side,
+				Type:          req.OrderType,
+				Quantity:      closeQty,
+				Price:         req.Price,
+				Status:        models.OrderStatusExpired,
+				ReduceOnly:    true,
+				TimeInForce:   timeInForce,
+				GoodTillDate:  goodTillDate,
+			}
+			if createErr := s.orderRepo.Create(order); createErr != nil {
+				return nil, nil, createErr
+			}
+			s.events.PublishOrder(order, nil)
+			return order, nil, err
+		}
+	}
+
+	// Limit closes that do not cross rest in the matching engine and fill at their price as maker
+	if req.OrderType == models.OrderTypeLimit && !crosses {
+		order := &models.Order{
+			AccountID:     req.AccountID,
+			ClientOrderID: clientOrderID(req.ClientOrderID),
+			Symbol:        req.Symbol,
+			Side:          s.getSide(req.Side, false),
+			PositionSide:  req.Side,
+			Type:          req.OrderType,
+			Quantity:      closeQty,
+			Price:         req.Price,
+			Status:        models.OrderStatusNew,
+			ReduceOnly:    true,
+			ClosePosition: req.Quantity == nil || *req.Quantity <= 0,
+			TimeInForce:   timeInForce,
+			GoodTillDate:  goodTillDate,
+		}
+		if err := s.orderRepo.Create(order); err != nil {
+			return nil, nil, err
+		}
+		s.events.PublishOrder(order, nil)
+		if s.matchingEngine != nil {
+			s.matchingEngine.Track(order, exchangeType)
+		}
+		return order, nil, nil
+	}
+
+	// Closes take the best quote (closing a long sells into the bid); market closes also pay slippage
+	closeSide := s.getSide(req.Side, false)
+	executionPrice := quotePrice(*quote, closeSide)
 	if req.OrderType == "" || req.OrderType == models.OrderTypeMarket {
 		req.OrderType = models.OrderTypeMarket
-		if req.Side == models.PositionSideLong {
-			// Closing long = selling, price goes down
-			executionPrice = currentPrice * (1 - defaultSlippage)
-		} else {
-			// Closing short = buying, price goes up
-			executionPrice = currentPrice * (1 + defaultSlippage)
-		}
+		executionPrice = fillPrice(account, *quote, closeSide, closeQty)
 	}
 
 	// Calculate PnL
@@ -338,7 +592,7 @@ func (s *TradingService) ClosePosition(req *ClosePositionRequest, exchangeType m
 	// Create close order
 	order := &models.Order{
 		AccountID:     req.AccountID,
-		ClientOrderID: uuid.New().String(),
+		ClientOrderID: clientOrderID(req.ClientOrderID),
 		Symbol:        req.Symbol,
 		Side:          s.getSide(req.Side, false),
 		PositionSide:  req.Side,
@@ -350,6 +604,8 @@ func (s *TradingService) ClosePosition(req *ClosePositionRequest, exchangeType m
 		AvgPrice:      executionPrice,
 		ReduceOnly:    true,
 		ClosePosition: closeQty == position.Quantity,
+		TimeInForce:   timeInForce,
+		GoodTillDate:  goodTillDate,
 	}
 
 	if err := s.orderRepo.Create(order); err != nil {
@@ -402,9 +658,10 @@ func (s *TradingService) ClosePosition(req *ClosePositionRequest, exchangeType m
 		if err := s.positionRepo.Delete(position.ID); err != nil {
 			return nil, nil, err
 		}
+		position.Quantity = 0
 
-		// Cancel all remaining SL/TP orders for this symbol to prevent affecting next trade
-		s.CancelAllAlgoOrders(req.AccountID, req.Symbol)
+		// Cancel the side's remaining SL/TP and reduce-only orders to prevent affecting next trade
+		s.cancelPositionOrders(position)
 	} else {
 		// Partial close
 		position.Quantity -= closeQty
@@ -416,11 +673,13 @@ func (s *TradingService) ClosePosition(req *ClosePositionRequest, exchangeType m
 
 	// Update account balance (Binance-style: only add realized PnL minus fee, margin was never deducted)
 	// walletBalance = initial balance - fees +/- realized PnL
-	account.BalanceUSDT += realizedPnL - fee
-	if err := s.accountRepo.Update(account); err != nil {
+	if err := s.accountRepo.AddBalance(account, realizedPnL-fee); err != nil {
 		return nil, nil, err
 	}
 
+	s.events.PublishOrder(order, trade)
+	s.events.PublishAccount(account, UserEventReasonOrder, *position)
+
 	return order, closedPnL, nil
 }
 
@@ -429,7 +688,8 @@ func (s *TradingService) ClosePosition(req *ClosePositionRequest, exchangeType m
 // This does NOT affect positions, orders are tracked separately and trigger on price condition
 func (s *TradingService) CreateConditionalOrder(req *ConditionalOrderRequest, exchangeType models.ExchangeType) (*models.Order, error) {
 	// Validate order type
-	if req.OrderType != models.OrderTypeStopMarket && req.OrderType != models.OrderTypeTakeProfit {
+	isTrailing := req.OrderType == models.OrderTypeTrailingStop
+	if req.OrderType != models.OrderTypeStopMarket && req.OrderType != models.OrderTypeTakeProfit && !isTrailing {
 		return nil, ErrInvalidOrderType
 	}
 
@@ -439,11 +699,25 @@ func (s *TradingService) CreateConditionalOrder(req *ConditionalOrderRequest, ex
 		return nil, ErrInvalidSymbol
 	}
 
-	// Validate stop price
-	if req.StopPrice <= 0 {
+	// Validate stop price; trailing stops derive theirs from the callback
+	if isTrailing {
+		if err := validateTrailingStop(req); err != nil {
+			return nil, err
+		}
+		req.StopPrice, req.Price = 0, 0
+	} else if req.StopPrice <= 0 {
 		return nil, ErrInvalidQuantity
 	}
 
+	triggerPriceType, err := validateTriggerPriceType(req.TriggerPriceType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkClientOrderID(req.AccountID, req.ClientOrderID); err != nil {
+		return nil, err
+	}
+
 	// Determine order side based on position side (SL/TP close opposite side)
 	var orderSide models.OrderSide
 	if req.Side == models.PositionSideLong {
@@ -455,7 +729,7 @@ func (s *TradingService) CreateConditionalOrder(req *ConditionalOrderRequest, ex
 	// Create conditional order with status NEW
 	order := &models.Order{
 		AccountID:     req.AccountID,
-		ClientOrderID: uuid.New().String(),
+		ClientOrderID: clientOrderID(req.ClientOrderID),
 		Symbol:        req.Symbol,
 		Side:          orderSide,
 		PositionSide:  req.Side,
@@ -466,11 +740,24 @@ func (s *TradingService) CreateConditionalOrder(req *ConditionalOrderRequest, ex
 		Status:        models.OrderStatusNew,  // IMPORTANT: Not executed, waiting for trigger
 		ReduceOnly:    req.ReduceOnly || true, // SL/TP is always reduce-only
 		ClosePosition: req.ClosePosition,
+
+		TriggerPriceType: triggerPriceType,
+	}
+
+	// Trailing stops without an activation price (or already past it) start tracking at the current price
+	if isTrailing {
+		order.CallbackRate = req.CallbackRate
+		order.CallbackSpread = req.CallbackSpread
+		order.ActivationPrice = req.ActivationPrice
+		if quote, err := s.priceService.GetQuote(string(exchangeType), req.Symbol); err == nil {
+			advanceTrailingStop(order, TriggerSourcePrice(*quote, triggerPriceType))
+		}
 	}
 
 	if err := s.orderRepo.Create(order); err != nil {
 		return nil, fmt.Errorf("failed to create conditional order: %w", err)
 	}
+	s.events.PublishOrder(order, nil)
 
 	return order, nil
 }
@@ -491,9 +778,45 @@ func (s *TradingService) GetPositions(accountID uint, exchangeType models.Exchan
 		}
 	}
 
+	// Cross positions share the account's collateral, so their liquidation price depends on the whole book
+	if account, err := s.accountRepo.GetByID(accountID); err == nil {
+		s.estimateCrossLiquidationPrices(account, positions, exchangeType)
+	}
+
 	return positions, nil
 }
 
+// CrossMarginState returns the cross-margin book of an account for the given positions
+// Positions must carry current mark prices and unrealized PnL
+func (s *TradingService) CrossMarginState(account *models.Account, positions []models.Position, exchangeType models.ExchangeType) models.CrossMarginState {
+	state := models.CrossMarginState{Wallet: account.BalanceUSDT}
+	for _, pos := range positions {
+		if pos.IsIsolated() {
+			state.Wallet -= pos.Margin
+			continue
+		}
+		notional := pos.MarkPrice * pos.Quantity
+		state.UnrealizedPnL += pos.UnrealizedPnL
+		state.MaintenanceMargin += notional * s.priceService.GetMaintenanceMarginRate(string(exchangeType), notional)
+	}
+	return state
+}
+
+// estimateCrossLiquidationPrices sets each cross position's liquidation price assuming other positions' prices stay fixed
+func (s *TradingService) estimateCrossLiquidationPrices(account *models.Account, positions []models.Position, exchangeType models.ExchangeType) {
+	state := s.CrossMarginState(account, positions, exchangeType)
+	for i := range positions {
+		pos := &positions[i]
+		if pos.IsIsolated() {
+			continue
+		}
+		notional := pos.MarkPrice * pos.Quantity
+		mmr := s.priceService.GetMaintenanceMarginRate(string(exchangeType), notional)
+		collateral := state.Equity() - pos.UnrealizedPnL - (state.MaintenanceMargin - notional*mmr)
+		pos.LiquidationPrice = pos.CalculateCrossLiquidationPrice(collateral, mmr)
+	}
+}
+
 // GetBalance returns account balance with unrealized PnL
 func (s *TradingService) GetBalance(accountID uint, exchangeType models.ExchangeType) (map[string]float64, error) {
 	account, err := s.accountRepo.GetByID(accountID)
@@ -508,17 +831,25 @@ func (s *TradingService) GetBalance(accountID uint, exchangeType models.Exchange
 
 	var totalUnrealizedPnL float64
 	var totalMargin float64
+	var crossUnrealizedPnL float64
+	var isolatedEquity float64
 	for _, pos := range positions {
 		totalUnrealizedPnL += pos.UnrealizedPnL
 		totalMargin += pos.Margin
+		if pos.IsIsolated() {
+			isolatedEquity += pos.Margin + pos.UnrealizedPnL
+		} else {
+			crossUnrealizedPnL += pos.UnrealizedPnL
+		}
 	}
 
 	// Binance-style balance calculation:
 	// walletBalance = initial balance - fees +/- realized PnL (stored in account.BalanceUSDT)
 	// marginBalance (equity) = walletBalance + unrealizedPnL
-	// availableBalance = marginBalance - totalMargin (can be used for new positions)
+	// availableBalance = walletBalance + cross unrealizedPnL - totalMargin (can be used for new positions)
+	// Isolated PnL stays inside the isolated margin and is not available to other positions
 	equity := account.BalanceUSDT + totalUnrealizedPnL
-	available := equity - totalMargin
+	available := account.BalanceUSDT + crossUnrealizedPnL - totalMargin
 
 	return map[string]float64{
 		"balance":         account.BalanceUSDT, // walletBalance
@@ -526,6 +857,7 @@ func (s *TradingService) GetBalance(accountID uint, exchangeType models.Exchange
 		"margin":          totalMargin,         // totalInitialMargin
 		"unrealized_pnl":  totalUnrealizedPnL,  // totalUnrealizedProfit
 		"equity":          equity,              // marginBalance
+		"isolated_equity": isolatedEquity,      // isolated margin + isolated unrealized PnL
 		"initial_balance": account.InitialBalance,
 	}, nil
 }
@@ -536,15 +868,133 @@ func (s *TradingService) SetLeverage(accountID uint, symbol string, leverage int
 		return ErrInvalidLeverage
 	}
 
-	s.cacheMux.Lock()
-	defer s.cacheMux.Unlock()
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return err
+	}
+
+	setting := s.symbolSetting(account, symbol)
+	setting.Leverage = leverage
+	return s.symbolSettingRepo.Save(setting)
+}
 
-	if s.leverageCache[accountID] == nil {
-		s.leverageCache[accountID] = make(map[string]int)
+// SetMarginMode sets the margin mode for a symbol
+// The mode cannot be changed while the symbol has an open position or order
+func (s *TradingService) SetMarginMode(accountID uint, symbol string, marginMode models.MarginMode) error {
+	if marginMode != models.MarginModeCross && marginMode != models.MarginModeIsolated {
+		return ErrInvalidMarginMode
 	}
-	s.leverageCache[accountID][symbol] = leverage
 
-	return nil
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return err
+	}
+
+	setting := s.symbolSetting(account, symbol)
+	if setting.MarginMode == marginMode {
+		return ErrSettingNotChanged
+	}
+	if s.hasOpenExposure(accountID, symbol) {
+		return ErrPositionExists
+	}
+
+	setting.MarginMode = marginMode
+	return s.symbolSettingRepo.Save(setting)
+}
+
+// SetPositionMode switches a symbol between one-way and hedge mode
+// The mode cannot be changed while the symbol has an open position or order
+func (s *TradingService) SetPositionMode(accountID uint, symbol string, hedgeMode bool) error {
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return err
+	}
+
+	setting := s.symbolSetting(account, symbol)
+	if setting.HedgeMode == hedgeMode {
+		return ErrSettingNotChanged
+	}
+	if s.hasOpenExposure(accountID, symbol) {
+		return ErrPositionExists
+	}
+
+	setting.HedgeMode = hedgeMode
+	return s.symbolSettingRepo.Save(setting)
+}
+
+// GetSymbolSetting returns the effective settings for a symbol
+func (s *TradingService) GetSymbolSetting(accountID uint, symbol string) (*models.SymbolSetting, error) {
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return nil, err
+	}
+	return s.symbolSetting(account, symbol), nil
+}
+
+// GetSymbolSettings returns all symbols with stored settings for an account
+func (s *TradingService) GetSymbolSettings(accountID uint) ([]models.SymbolSetting, error) {
+	return s.symbolSettingRepo.GetByAccountID(accountID)
+}
+
+// AdjustIsolatedMargin adds (amount > 0) or removes (amount < 0) margin from an isolated position
+// An empty or BOTH side selects the symbol's only position (one-way mode)
+func (s *TradingService) AdjustIsolatedMargin(accountID uint, symbol string, side models.PositionSide, amount float64) (*models.Position, error) {
+	if amount == 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := s.GetPositions(accountID, account.ExchangeType)
+	if err != nil {
+		return nil, err
+	}
+
+	var position *models.Position
+	for i := range positions {
+		if positions[i].Symbol != symbol {
+			continue
+		}
+		if side == "" || side == models.PositionSideBoth || positions[i].Side == side {
+			position = &positions[i]
+			break
+		}
+	}
+	if position == nil {
+		return nil, ErrPositionNotFound
+	}
+	if !position.IsIsolated() {
+		return nil, ErrNotIsolated
+	}
+
+	if amount > 0 {
+		balance, err := s.GetBalance(accountID, account.ExchangeType)
+		if err != nil {
+			return nil, err
+		}
+		if amount > balance["available"] {
+			return nil, ErrInsufficientBalance
+		}
+	} else {
+		// Removal must leave the initial margin at the current mark price, net of unrealized losses
+		removable := position.Margin + math.Min(position.UnrealizedPnL, 0) - position.MarkPrice*position.Quantity/float64(position.Leverage)
+		if -amount > removable {
+			return nil, ErrInsufficientMargin
+		}
+	}
+
+	position.Margin += amount
+	s.refreshLiquidationPrice(position, account.ExchangeType)
+	if err := s.positionRepo.Update(position); err != nil {
+		return nil, err
+	}
+
+	s.events.PublishAccount(account, UserEventReasonMarginTransfer, *position)
+
+	return position, nil
 }
 
 // SetStopLoss sets stop loss for a position
@@ -571,10 +1021,11 @@ func (s *TradingService) SetTakeProfit(accountID uint, symbol string, side model
 
 // CancelAllOrders cancels all open orders
 func (s *TradingService) CancelAllOrders(accountID uint, symbol string) (int64, error) {
-	if symbol != "" {
-		return s.orderRepo.CancelAllOpenOrdersBySymbol(accountID, symbol)
+	orders, err := s.GetOpenOrders(accountID, symbol)
+	if err != nil {
+		return 0, err
 	}
-	return s.orderRepo.CancelAllOpenOrders(accountID)
+	return s.cancelOrders(orders), nil
 }
 
 // GetOrderStatus returns order status
@@ -589,6 +1040,276 @@ func (s *TradingService) GetOrderStatus(accountID uint, orderID uint) (*models.O
 	return order, nil
 }
 
+// GetOrderByClientID returns an account's order by client order ID
+func (s *TradingService) GetOrderByClientID(accountID uint, clientOrderID string) (*models.Order, error) {
+	order, err := s.orderRepo.GetByClientOrderID(accountID, clientOrderID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// FindOrder returns an account's order by order ID, or by client order ID when no order ID is given
+// A client order ID reused after its order completed resolves to the newest order
+func (s *TradingService) FindOrder(accountID, orderID uint, clientOrderID string) (*models.Order, error) {
+	if orderID == 0 && clientOrderID != "" {
+		return s.GetOrderByClientID(accountID, clientOrderID)
+	}
+	return s.GetOrderStatus(accountID, orderID)
+}
+
+// CancelOrder cancels a single open order of an account
+func (s *TradingService) CancelOrder(accountID uint, orderID uint) (*models.Order, error) {
+	order, err := s.GetOrderStatus(accountID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.IsPending() {
+		return nil, ErrOrderNotOpen
+	}
+
+	order.Status = models.OrderStatusCanceled
+	canceled, err := s.orderRepo.UpdateIfOpen(order)
+	if err != nil {
+		return nil, err
+	}
+	if !canceled {
+		return nil, ErrOrderNotOpen
+	}
+	s.events.PublishOrder(order, nil)
+
+	return order, nil
+}
+
+// ReplaceOrder cancels an open order and runs place to put its replacement on the book
+// Canceling first frees the client order ID for a replacement that keeps it;
+// the original order is reopened when place fails
+func (s *TradingService) ReplaceOrder(accountID, orderID uint, place func() error) error {
+	order, err := s.GetOrderStatus(accountID, orderID)
+	if err != nil {
+		return err
+	}
+	if !order.IsPending() {
+		return ErrOrderNotOpen
+	}
+
+	status := order.Status
+	order.Status = models.OrderStatusCanceled
+	canceled, err := s.orderRepo.UpdateIfOpen(order)
+	if err != nil {
+		return err
+	}
+	if !canceled {
+		return ErrOrderNotOpen
+	}
+	s.events.PublishOrder(order, nil)
+
+	placeErr := place()
+	if placeErr == nil {
+		return nil
+	}
+
+	order.Status = status
+	if err := s.orderRepo.Update(order); err != nil {
+		log.Printf("[TradingService] Failed to reopen order %d: %v", order.ID, err)
+		return placeErr
+	}
+	s.events.PublishOrder(order, nil)
+	if order.Type == models.OrderTypeLimit && s.matchingEngine != nil {
+		if account, err := s.accountRepo.GetByID(accountID); err == nil {
+			s.matchingEngine.Track(order, account.ExchangeType)
+		}
+	}
+	return placeErr
+}
+
+// ExpireOrder expires a pending order, as done for GTD orders past their good till date
+func (s *TradingService) ExpireOrder(orderID uint) (*models.Order, error) {
+	order, err := s.orderRepo.GetByID(orderID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if !order.IsPending() {
+		return nil, ErrOrderNotOpen
+	}
+
+	order.Status = models.OrderStatusExpired
+	expired, err := s.orderRepo.UpdateIfOpen(order)
+	if err != nil {
+		return nil, err
+	}
+	if !expired {
+		return nil, ErrOrderNotOpen
+	}
+	s.events.PublishOrder(order, nil)
+
+	return order, nil
+}
+
+// AmendOrder changes the price, quantity or trigger price of a NEW order, keeping its order ID
+// Opening limit orders are re-checked against the balance and re-priced in the matching engine;
+// limit orders amended across the quote fill at once as taker
+func (s *TradingService) AmendOrder(accountID uint, orderID uint, req *AmendOrderRequest) (*models.Order, error) {
+	order, err := s.GetOrderStatus(accountID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderStatusNew || order.Type == models.OrderTypeMarket || order.IsLiquidation() {
+		return nil, ErrOrderNotOpen
+	}
+
+	account, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return nil, err
+	}
+	symbolInfo, err := s.priceService.GetSymbolInfo(string(account.ExchangeType), order.Symbol)
+	if err != nil {
+		return nil, ErrInvalidSymbol
+	}
+
+	price, quantity, stopPrice := order.Price, order.Quantity, order.StopPrice
+	if req.Price != nil {
+		if *req.Price <= 0 || (!order.IsConditional() && order.Type != models.OrderTypeLimit) {
+			return nil, ErrInvalidPrice
+		}
+		price = s.roundPrice(*req.Price, symbolInfo)
+	}
+	if req.Quantity != nil {
+		if order.ClosePosition || !symbolInfo.ValidQuantity(*req.Quantity) {
+			return nil, ErrInvalidQuantity
+		}
+		quantity = *req.Quantity
+	}
+	if req.StopPrice != nil {
+		if *req.StopPrice <= 0 || !order.IsConditional() {
+			return nil, ErrInvalidPrice
+		}
+		stopPrice = s.roundPrice(*req.StopPrice, symbolInfo)
+	}
+	if price == order.Price && quantity == order.Quantity && stopPrice == order.StopPrice {
+		return nil, ErrOrderNotModified
+	}
+
+	// Opening limit orders must still be affordable at the new price and size
+	if order.Type == models.OrderTypeLimit && !order.ReduceOnly {
+		positionValue := price * quantity
+		requiredMargin := positionValue / float64(s.getLeverage(account, order.Symbol))
+		if account.BalanceUSDT < requiredMargin+positionValue*account.TakerFeeRate {
+			return nil, ErrInsufficientBalance
+		}
+	}
+
+	// Limit orders re-priced across the quote meet their time in force as at placement:
+	// post-only orders are rejected rather than amended, others take the best quote as taker
+	var crosses bool
+	var takerPrice float64
+	if order.Type == models.OrderTypeLimit && price != order.Price {
+		crosses, takerPrice = s.crossesQuote(string(account.ExchangeType), order.Symbol, order.Side, price)
+		if err := timeInForceError(order.TimeInForce, crosses); err != nil {
+			return nil, err
+		}
+	}
+
+	// The amend is lost to a fill or cancel that changed the order since it was read;
+	// an amend that crosses claims the fill in the same write
+	status := order.Status
+	readPrice, readQuantity := order.Price, order.Quantity
+	order.Price = price
+	order.Quantity = quantity
+	order.StopPrice = stopPrice
+	if crosses {
+		order.Status = models.OrderStatusFilled
+	}
+	amended, err := s.orderRepo.UpdateIfUnchanged(order, readPrice, readQuantity)
+	if err != nil {
+		return nil, err
+	}
+	if !amended {
+		return nil, ErrOrderNotOpen
+	}
+
+	if crosses {
+		amendment := *order
+		amendment.Status = status
+		s.events.PublishOrderAmendment(&amendment)
+		if err := s.takeAmendedOrder(order, account, symbolInfo, takerPrice); err != nil {
+			return nil, err
+		}
+		return order, nil
+	}
+
+	if order.Type == models.OrderTypeLimit && s.matchingEngine != nil {
+		s.matchingEngine.Track(order, account.ExchangeType)
+	}
+	s.events.PublishOrderAmendment(order)
+
+	return order, nil
+}
+
+// takeAmendedOrder fills a limit order amended across the quote at the best quote as taker
+func (s *TradingService) takeAmendedOrder(order *models.Order, account *models.Account, symbolInfo *exchange.SymbolInfo, takerPrice float64) error {
+	takerPrice = s.roundPrice(takerPrice, symbolInfo)
+	if order.ReduceOnly {
+		_, _, err := s.fillLimitClose(order, account, takerPrice, false)
+		return err
+	}
+	fee := takerPrice * order.Quantity * account.TakerFeeRate
+	_, _, err := s.executeOpenOrder(order, account, symbolInfo, takerPrice, s.getLeverage(account, order.Symbol), fee, nil, nil, false)
+	return err
+}
+
+// validateTimeInForce normalizes the time in force of a limit order; market orders ignore it and report GTC
+// The good till date is only kept for GTD orders and must be in the future
+func validateTimeInForce(orderType models.OrderType, timeInForce string, goodTillDate time.Time) (string, *time.Time, error) {
+	switch {
+	case orderType != models.OrderTypeLimit, timeInForce == "":
+		return models.TimeInForceGTC, nil, nil
+	case timeInForce == models.TimeInForceGTC, timeInForce == models.TimeInForceIOC,
+		timeInForce == models.TimeInForceFOK, timeInForce == models.TimeInForceGTX:
+		return timeInForce, nil, nil
+	case timeInForce == models.TimeInForceGTD:
+		if !goodTillDate.After(time.Now()) {
+			return "", nil, ErrInvalidGoodTillDate
+		}
+		return timeInForce, &goodTillDate, nil
+	default:
+		return "", nil, ErrInvalidTimeInForce
+	}
+}
+
+// timeInForceError returns why a limit order must be expired at placement, or nil if it can be accepted
+func timeInForceError(timeInForce string, crosses bool) error {
+	switch {
+	case timeInForce == models.TimeInForceIOC && !crosses:
+		return ErrIOCNotFilled
+	case timeInForce == models.TimeInForceFOK && !crosses:
+		return ErrFOKNotFilled
+	case timeInForce == models.TimeInForceGTX && crosses:
+		return ErrPostOnlyWouldTake
+	}
+	return nil
+}
+
+// IsTimeInForceExpiry reports whether an order error means the order was stored as EXPIRED
+// because its time in force could not be met; the order itself is returned alongside the error
+func IsTimeInForceExpiry(err error) bool {
+	return err == ErrIOCNotFilled || err == ErrFOKNotFilled || err == ErrPostOnlyWouldTake
+}
+
+// crossesQuote reports whether a limit order would take liquidity against the best quote,
+// and the quote it would take; the last price stands in when the feed has no book data
+func (s *TradingService) crossesQuote(exchangeName, symbol string, side models.OrderSide, price float64) (bool, float64) {
+	quote, err := s.priceService.GetQuote(exchangeName, symbol)
+	if err != nil || quote.Price <= 0 {
+		return false, price
+	}
+	taker := quotePrice(*quote, side)
+	if side == models.OrderSideBuy {
+		return taker <= price, taker
+	}
+	return taker >= price, taker
+}
+
 // GetClosedPnL returns closed PnL records
 func (s *TradingService) GetClosedPnL(accountID uint, page, pageSize int) ([]models.ClosedPnLRecord, int64, error) {
 	return s.closedPnLRepo.GetByAccountIDPaginated(accountID, page, pageSize)
@@ -596,16 +1317,59 @@ func (s *TradingService) GetClosedPnL(accountID uint, page, pageSize int) ([]mod
 
 // Helper functions
 
-func (s *TradingService) getLeverage(accountID uint, symbol string, defaultLeverage int) int {
-	s.cacheMux.RLock()
-	defer s.cacheMux.RUnlock()
+func (s *TradingService) getLeverage(account *models.Account, symbol string) int {
+	return s.symbolSetting(account, symbol).Leverage
+}
 
-	if leverages, ok := s.leverageCache[accountID]; ok {
-		if lev, ok := leverages[symbol]; ok {
-			return lev
-		}
+// symbolSetting returns the stored setting for a symbol, or one built from the account defaults
+func (s *TradingService) symbolSetting(account *models.Account, symbol string) *models.SymbolSetting {
+	if setting, err := s.symbolSettingRepo.Get(account.ID, symbol); err == nil && setting != nil {
+		return setting
+	}
+
+	marginMode := account.MarginMode
+	if marginMode == "" {
+		marginMode = models.MarginModeCross
+	}
+	return &models.SymbolSetting{
+		AccountID:  account.ID,
+		Symbol:     symbol,
+		Leverage:   account.DefaultLeverage,
+		MarginMode: marginMode,
+		HedgeMode:  account.HedgeMode,
+	}
+}
+
+// hasOpenExposure checks whether a symbol has an open position or pending order
+func (s *TradingService) hasOpenExposure(accountID uint, symbol string) bool {
+	if positions, err := s.positionRepo.GetByAccountIDAndSymbol(accountID, symbol); err == nil && len(positions) > 0 {
+		return true
+	}
+	orders, err := s.orderRepo.GetOpenOrdersBySymbol(accountID, symbol)
+	return err == nil && len(orders) > 0
+}
+
+// checkClientOrderID rejects a client-supplied order ID that an open order of the account already uses
+func (s *TradingService) checkClientOrderID(accountID uint, clientOrderID string) error {
+	if clientOrderID == "" {
+		return nil
+	}
+	exists, err := s.orderRepo.HasOpenClientOrderID(accountID, clientOrderID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrDuplicateClientID
+	}
+	return nil
+}
+
+// clientOrderID returns the client-supplied order ID, generating one when empty
+func clientOrderID(id string) string {
+	if id == "" {
+		return uuid.New().String()
 	}
-	return defaultLeverage
+	return id
 }
 
 func (s *TradingService) getSide(positionSide models.PositionSide, isOpen bool) models.OrderSide {
@@ -622,6 +1386,17 @@ func (s *TradingService) getSide(positionSide models.PositionSide, isOpen bool)
 	return models.OrderSideBuy
 }
 
+// refreshLiquidationPrice recalculates a position's stored liquidation price
+// Isolated positions are liquidated on their own margin; cross positions store the leverage-based estimate
+func (s *TradingService) refreshLiquidationPrice(position *models.Position, exchangeType models.ExchangeType) {
+	if position.IsIsolated() {
+		mmr := s.priceService.GetMaintenanceMarginRate(string(exchangeType), position.EntryPrice*position.Quantity)
+		position.LiquidationPrice = position.CalculateIsolatedLiquidationPrice(mmr)
+		return
+	}
+	position.LiquidationPrice = s.calculateLiquidationPrice(position.EntryPrice, position.Leverage, position.Side)
+}
+
 func (s *TradingService) calculateLiquidationPrice(entryPrice float64, leverage int, side models.PositionSide) float64 {
 	maintenanceMarginRate := 0.004 // Default MMR
 
@@ -662,17 +1437,17 @@ func (s *TradingService) GetOpenAlgoOrders(accountID uint, symbol string) ([]mod
 
 // CancelAllAlgoOrders cancels all open algo orders
 func (s *TradingService) CancelAllAlgoOrders(accountID uint, symbol string) (int64, error) {
-	orderTypes := []models.OrderType{
-		models.OrderTypeStopMarket,
-		models.OrderTypeTakeProfit,
-		models.OrderTypeTrailingStop,
+	orders, err := s.GetOpenAlgoOrders(accountID, symbol)
+	if err != nil {
+		return 0, err
 	}
-	return s.orderRepo.CancelOpenOrdersByTypes(accountID, symbol, orderTypes)
+	return s.cancelOrders(orders), nil
 }
 
 // ExecuteTriggeredOrder executes a triggered SL/TP order
 // This is called by the SL/TP monitoring worker when price triggers the order
-func (s *TradingService) ExecuteTriggeredOrder(order *models.Order, exchangeType models.ExchangeType) (*models.ClosedPnLRecord, error) {
+// The fill is priced on the order's own account venue
+func (s *TradingService) ExecuteTriggeredOrder(order *models.Order) (*models.ClosedPnLRecord, error) {
 	// Get account
 	account, err := s.accountRepo.GetByID(order.AccountID)
 	if err != nil {
@@ -684,7 +1459,9 @@ func (s *TradingService) ExecuteTriggeredOrder(order *models.Order, exchangeType
 	if err != nil {
 		// No position to close, cancel the order
 		order.Status = models.OrderStatusCanceled
-		s.orderRepo.Update(order)
+		if canceled, _ := s.orderRepo.UpdateIfOpen(order); canceled {
+			s.events.PublishOrder(order, nil)
+		}
 		return nil, ErrNoOpenPosition
 	}
 
@@ -697,14 +1474,38 @@ func (s *TradingService) ExecuteTriggeredOrder(order *models.Order, exchangeType
 		closeQty = position.Quantity
 	}
 
-	// Get execution price (use stop price as trigger price, then apply slippage)
-	executionPrice := order.StopPrice
-	if position.Side == models.PositionSideLong {
-		executionPrice = executionPrice * (1 - defaultSlippage)
-	} else {
-		executionPrice = executionPrice * (1 + defaultSlippage)
+	// Stop-limit and take-profit-limit orders rest a limit order at their price instead of closing at market
+	if order.Price > 0 {
+		if err := s.claimOrder(order, models.OrderStatusTriggered); err != nil {
+			return nil, err
+		}
+		return s.placeTriggeredLimit(order, account, position, closeQty)
 	}
+	if err := s.claimOrder(order, models.OrderStatusFilled); err != nil {
+		return nil, err
+	}
+
+	// Triggered orders close at market against the live quote; the stop price stands in without one
+	quote, err := s.priceService.GetQuote(string(account.ExchangeType), order.Symbol)
+	if err != nil {
+		quote = &exchange.PriceUpdate{Price: order.StopPrice}
+	}
+	executionPrice := fillPrice(account, *quote, s.getSide(position.Side, false), closeQty)
+
+	return s.executeCloseOrder(order, account, position, closeQty, executionPrice, account.TakerFeeRate, false, triggerCloseReason(order.Type))
+}
 
+// executeCloseOrder fills a reduce-only order against a position and books the trade, PnL and fee
+func (s *TradingService) executeCloseOrder(
+	order *models.Order,
+	account *models.Account,
+	position *models.Position,
+	closeQty float64,
+	executionPrice float64,
+	feeRate float64,
+	isMaker bool,
+	closeReason string,
+) (*models.ClosedPnLRecord, error) {
 	// Calculate PnL
 	var realizedPnL float64
 	if position.Side == models.PositionSideLong {
@@ -715,7 +1516,7 @@ func (s *TradingService) ExecuteTriggeredOrder(order *models.Order, exchangeType
 
 	// Calculate fee
 	positionValue := executionPrice * closeQty
-	fee := positionValue * account.TakerFeeRate
+	fee := positionValue * feeRate
 
 	// Update order status
 	order.Status = models.OrderStatusFilled
@@ -736,7 +1537,7 @@ func (s *TradingService) ExecuteTriggeredOrder(order *models.Order, exchangeType
 		Fee:         fee,
 		FeeCurrency: "USDT",
 		RealizedPnL: realizedPnL,
-		IsMaker:     false,
+		IsMaker:     isMaker,
 		ExecutedAt:  time.Now(),
 	}
 	if err := s.tradeRepo.Create(trade); err != nil {
@@ -749,12 +1550,7 @@ func (s *TradingService) ExecuteTriggeredOrder(order *models.Order, exchangeType
 	// Update or delete position
 	var closedPnL *models.ClosedPnLRecord
 	if closeQty >= position.Quantity {
-		// Full close - determine close reason
-		closeReason := "stop_loss"
-		if order.Type == models.OrderTypeTakeProfit {
-			closeReason = "take_profit"
-		}
-
+		// Full close
 		closedPnL = &models.ClosedPnLRecord{
 			AccountID:    order.AccountID,
 			Symbol:       order.Symbol,
@@ -776,9 +1572,10 @@ func (s *TradingService) ExecuteTriggeredOrder(order *models.Order, exchangeType
 		if err := s.positionRepo.Delete(position.ID); err != nil {
 			return nil, fmt.Errorf("failed to delete position: %w", err)
 		}
+		position.Quantity = 0
 
-		// Cancel all remaining SL/TP orders for this symbol to prevent affecting next trade
-		s.CancelAllAlgoOrders(order.AccountID, order.Symbol)
+		// Cancel the side's remaining SL/TP and reduce-only orders to prevent affecting next trade
+		s.cancelPositionOrders(position)
 	} else {
 		// Partial close
 		position.Quantity -= closeQty
@@ -789,14 +1586,231 @@ func (s *TradingService) ExecuteTriggeredOrder(order *models.Order, exchangeType
 	}
 
 	// Update account balance (Binance-style: only add realized PnL minus fee)
-	account.BalanceUSDT += realizedPnL - fee
-	if err := s.accountRepo.Update(account); err != nil {
+	if err := s.accountRepo.AddBalance(account, realizedPnL-fee); err != nil {
 		return nil, fmt.Errorf("failed to update account: %w", err)
 	}
 
+	s.events.PublishOrder(order, trade)
+	s.events.PublishAccount(account, UserEventReasonOrder, *position)
+
 	return closedPnL, nil
 }
 
+// triggerCloseReason returns the closed PnL reason of a position closed by a conditional order
+func triggerCloseReason(orderType models.OrderType) string {
+	if orderType == models.OrderTypeTakeProfit {
+		return "take_profit"
+	}
+	return "stop_loss"
+}
+
+// placeTriggeredLimit places the reduce-only limit order of a triggered stop-limit or take-profit-limit order
+// The conditional order is marked TRIGGERED and linked to the limit order, which fills at once as taker
+// when its price is already marketable and otherwise rests on the matching engine
+func (s *TradingService) placeTriggeredLimit(order *models.Order, account *models.Account, position *models.Position, closeQty float64) (*models.ClosedPnLRecord, error) {
+	child := &models.Order{
+		AccountID:     order.AccountID,
+		ClientOrderID: clientOrderID(""),
+		Symbol:        order.Symbol,
+		Side:          order.Side,
+		PositionSide:  order.PositionSide,
+		Type:          models.OrderTypeLimit,
+		Quantity:      closeQty,
+		Price:         order.Price,
+		Status:        models.OrderStatusNew,
+		ReduceOnly:    true,
+		ClosePosition: order.ClosePosition,
+		TimeInForce:   models.TimeInForceGTC,
+		ParentOrderID: &order.ID,
+	}
+	if err := s.orderRepo.Create(child); err != nil {
+		return nil, fmt.Errorf("failed to create triggered order: %w", err)
+	}
+
+	order.Status = models.OrderStatusTriggered
+	order.TriggeredOrderID = &child.ID
+	if err := s.orderRepo.Update(order); err != nil {
+		return nil, fmt.Errorf("failed to update order: %w", err)
+	}
+	s.events.PublishOrder(order, nil)
+	s.events.PublishOrder(child, nil)
+
+	if crosses, takerPrice := s.crossesQuote(string(account.ExchangeType), order.Symbol, child.Side, child.Price); crosses {
+		return s.executeCloseOrder(child, account, position, closeQty, takerPrice, account.TakerFeeRate, false, triggerCloseReason(order.Type))
+	}
+	if s.matchingEngine != nil {
+		s.matchingEngine.Track(child, account.ExchangeType)
+	}
+	return nil, nil
+}
+
+// LiquidatePosition force-closes a position whose mark price breached maintenance margin
+// This is called by the liquidation worker; the forced order is recorded as a LIQUIDATION order
+func (s *TradingService) LiquidatePosition(position *models.Position, markPrice float64) (*models.Order, *models.ClosedPnLRecord, error) {
+	account, err := s.accountRepo.GetByID(position.AccountID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	closeQty := position.Quantity
+	executionPrice := markPrice
+
+	// Calculate PnL
+	var realizedPnL float64
+	if position.Side == models.PositionSideLong {
+		realizedPnL = (executionPrice - position.EntryPrice) * closeQty
+	} else {
+		realizedPnL = (position.EntryPrice - executionPrice) * closeQty
+	}
+
+	// Calculate fee
+	positionValue := executionPrice * closeQty
+	fee := positionValue * account.TakerFeeRate
+
+	// Create forced close order (Binance-style "autoclose-" client order ID)
+	order := &models.Order{
+		AccountID:     position.AccountID,
+		ClientOrderID: fmt.Sprintf("autoclose-%d", time.Now().UnixNano()),
+		Symbol:        position.Symbol,
+		Side:          s.getSide(position.Side, false),
+		PositionSide:  position.Side,
+		Type:          models.OrderTypeLiquidation,
+		Quantity:      closeQty,
+		Price:         executionPrice,
+		Status:        models.OrderStatusFilled,
+		FilledQty:     closeQty,
+		AvgPrice:      executionPrice,
+		ReduceOnly:    true,
+		ClosePosition: true,
+		TimeInForce:   "IOC",
+	}
+	if err := s.orderRepo.Create(order); err != nil {
+		return nil, nil, fmt.Errorf("failed to create liquidation order: %w", err)
+	}
+
+	// Create trade record
+	trade := &models.Trade{
+		AccountID:   order.AccountID,
+		OrderID:     order.ID,
+		Symbol:      order.Symbol,
+		Side:        order.Side,
+		Quantity:    closeQty,
+		Price:       executionPrice,
+		Fee:         fee,
+		FeeCurrency: "USDT",
+		RealizedPnL: realizedPnL,
+		IsMaker:     false,
+		ExecutedAt:  time.Now(),
+	}
+	if err := s.tradeRepo.Create(trade); err != nil {
+		return nil, nil, fmt.Errorf("failed to create trade: %w", err)
+	}
+
+	closedPnL := &models.ClosedPnLRecord{
+		AccountID:    position.AccountID,
+		Symbol:       position.Symbol,
+		Side:         position.Side,
+		Quantity:     closeQty,
+		EntryPrice:   position.EntryPrice,
+		ExitPrice:    executionPrice,
+		RealizedPnL:  realizedPnL,
+		TotalFee:     fee,
+		Leverage:     position.Leverage,
+		ClosedReason: "liquidation",
+		OpenedAt:     position.CreatedAt,
+		ClosedAt:     time.Now(),
+	}
+	if err := s.closedPnLRepo.Create(closedPnL); err != nil {
+		return nil, nil, fmt.Errorf("failed to create closed pnl record: %w", err)
+	}
+
+	if err := s.positionRepo.Delete(position.ID); err != nil {
+		return nil, nil, fmt.Errorf("failed to delete position: %w", err)
+	}
+
+	// A liquidated position takes its reduce-only and SL/TP orders with it
+	s.cancelPositionOrders(position)
+
+	// An isolated position can lose at most its own margin, losses beyond the
+	// wallet balance are absorbed by the (simulated) insurance fund
+	balanceChange := realizedPnL - fee
+	if position.IsIsolated() && balanceChange < -position.Margin {
+		balanceChange = -position.Margin
+	}
+	if err := s.accountRepo.AddBalanceFloored(account, balanceChange); err != nil {
+		return nil, nil, fmt.Errorf("failed to update account: %w", err)
+	}
+
+	closedPosition := *position
+	closedPosition.Quantity = 0
+	s.events.PublishOrder(order, trade)
+	s.events.PublishAccount(account, UserEventReasonOrder, closedPosition)
+
+	return order, closedPnL, nil
+}
+
+// cancelPositionOrders cancels the pending reduce-only and conditional orders of a closed position side
+func (s *TradingService) cancelPositionOrders(position *models.Position) {
+	orders, err := s.orderRepo.GetOpenOrdersBySymbol(position.AccountID, position.Symbol)
+	if err != nil {
+		log.Printf("[TradingService] Failed to load %s orders of account %d: %v", position.Symbol, position.AccountID, err)
+		return
+	}
+	var closing []models.Order
+	for _, order := range orders {
+		if order.PositionSide == position.Side && (order.ReduceOnly || order.ClosePosition || order.IsConditional()) {
+			closing = append(closing, order)
+		}
+	}
+	s.cancelOrders(closing)
+}
+
+// cancelOrders cancels pending orders one by one so each cancel reaches the user data streams
+// Returns the number of orders canceled
+func (s *TradingService) cancelOrders(orders []models.Order) int64 {
+	var canceled int64
+	for i := range orders {
+		order := &orders[i]
+		order.Status = models.OrderStatusCanceled
+		updated, err := s.orderRepo.UpdateIfOpen(order)
+		if err != nil {
+			log.Printf("[TradingService] Failed to cancel order %d: %v", order.ID, err)
+			continue
+		}
+		if !updated {
+			continue
+		}
+		s.events.PublishOrder(order, nil)
+		canceled++
+	}
+	return canceled
+}
+
+// GetForceOrders returns liquidation orders for an account, newest first
+func (s *TradingService) GetForceOrders(accountID uint, symbol string, limit int) ([]models.Order, error) {
+	return s.orderRepo.GetByTypes(accountID, symbol, []models.OrderType{models.OrderTypeLiquidation}, limit)
+}
+
+// GetOrderHistory returns orders of any status for an account, newest first
+func (s *TradingService) GetOrderHistory(accountID uint, symbol string, limit int) ([]models.Order, error) {
+	return s.orderRepo.GetHistory(accountID, symbol, limit)
+}
+
+// GetTradeHistory returns an account's fills with their orders
+func (s *TradingService) GetTradeHistory(accountID uint, q repository.HistoryQuery) ([]models.Trade, error) {
+	return s.tradeRepo.FindHistory(accountID, q)
+}
+
+// GetOrderHistoryPage returns an account's orders of any status matching a history query
+func (s *TradingService) GetOrderHistoryPage(accountID uint, q repository.HistoryQuery) ([]models.Order, error) {
+	return s.orderRepo.FindHistory(accountID, q)
+}
+
+// GetClosedPnLHistory returns an account's closed PnL records matching a history query
+func (s *TradingService) GetClosedPnLHistory(accountID uint, q repository.HistoryQuery) ([]models.ClosedPnLRecord, error) {
+	return s.closedPnLRepo.FindHistory(accountID, q)
+}
+
 // GetPriceService returns the price service (for worker access)
 func (s *TradingService) GetPriceService() *PriceService {
 	return s.priceService
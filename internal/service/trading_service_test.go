@@ -0,0 +1,289 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ccxt-simulator/internal/exchange"
+	"github.com/ccxt-simulator/internal/exchange/hyperliquid"
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidateTimeInForce tests time in force defaults and GTD validation
+func TestValidateTimeInForce(t *testing.T) {
+	tif, gtd, err := validateTimeInForce(models.OrderTypeLimit, "", time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.TimeInForceGTC, tif)
+	assert.Nil(t, gtd)
+
+	tif, _, err = validateTimeInForce(models.OrderTypeMarket, models.TimeInForceFOK, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.TimeInForceGTC, tif, "Market orders should ignore the time in force")
+
+	_, _, err = validateTimeInForce(models.OrderTypeLimit, "DAY", time.Time{})
+	assert.Equal(t, ErrInvalidTimeInForce, err)
+
+	_, _, err = validateTimeInForce(models.OrderTypeLimit, models.TimeInForceGTD, time.Now().Add(-time.Minute))
+	assert.Equal(t, ErrInvalidGoodTillDate, err)
+
+	expiry := time.Now().Add(time.Hour)
+	tif, gtd, err = validateTimeInForce(models.OrderTypeLimit, models.TimeInForceGTD, expiry)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TimeInForceGTD, tif)
+	assert.Equal(t, expiry, *gtd)
+}
+
+// TestTimeInForceError tests which limit orders are expired at placement
+func TestTimeInForceError(t *testing.T) {
+	assert.Equal(t, ErrIOCNotFilled, timeInForceError(models.TimeInForceIOC, false))
+	assert.NoError(t, timeInForceError(models.TimeInForceIOC, true))
+	assert.Equal(t, ErrFOKNotFilled, timeInForceError(models.TimeInForceFOK, false))
+	assert.NoError(t, timeInForceError(models.TimeInForceFOK, true))
+	assert.Equal(t, ErrPostOnlyWouldTake, timeInForceError(models.TimeInForceGTX, true))
+	assert.NoError(t, timeInForceError(models.TimeInForceGTX, false))
+	assert.NoError(t, timeInForceError(models.TimeInForceGTC, true))
+	assert.NoError(t, timeInForceError(models.TimeInForceGTD, false))
+}
+
+// TestTriggerCloseReason tests the closed PnL reason of positions closed by conditional orders
+func TestTriggerCloseReason(t *testing.T) {
+	assert.Equal(t, "take_profit", triggerCloseReason(models.OrderTypeTakeProfit))
+	assert.Equal(t, "stop_loss", triggerCloseReason(models.OrderTypeStopMarket))
+	assert.Equal(t, "stop_loss", triggerCloseReason(models.OrderTypeTrailingStop))
+}
+
+// TestOpenPositionHyperliquidSymbol tests that Hyperliquid symbols, which publish no maximum size, accept orders
+func TestOpenPositionHyperliquidSymbol(t *testing.T) {
+	h := newTestHarness(t)
+	h.addSymbol("hyperliquid", "BTCUSDT", hyperliquid.NewSymbolInfo("BTC", 5))
+	h.setQuote("hyperliquid", "BTCUSDT", 99.9, 100.1)
+	account := h.newAccount(t, models.ExchangeHyperliquid, 10000)
+
+	order, position, err := h.trading.OpenPosition(&OpenPositionRequest{
+		AccountID: account.ID,
+		Symbol:    "BTCUSDT",
+		Side:      models.PositionSideLong,
+		Quantity:  0.5,
+	}, models.ExchangeHyperliquid)
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderStatusFilled, order.Status)
+	assert.Equal(t, 0.5, position.Quantity)
+
+	// Sizes below one szDecimals step are still rejected
+	_, _, err = h.trading.OpenPosition(&OpenPositionRequest{
+		AccountID: account.ID,
+		Symbol:    "BTCUSDT",
+		Side:      models.PositionSideLong,
+		Quantity:  0.000001,
+	}, models.ExchangeHyperliquid)
+	assert.Equal(t, ErrInvalidQuantity, err)
+}
+
+// TestReplaceOrderKeepsClientOrderID tests that a replacement may reuse the original's client order ID,
+// and that a rejected replacement reopens the original
+func TestReplaceOrderKeepsClientOrderID(t *testing.T) {
+	h := newTestHarness(t)
+	h.addSymbol("hyperliquid", "BTCUSDT", hyperliquid.NewSymbolInfo("BTC", 5))
+	h.setQuote("hyperliquid", "BTCUSDT", 99.9, 100.1)
+	account := h.newAccount(t, models.ExchangeHyperliquid, 10000)
+
+	place := func(price float64) (*models.Order, error) {
+		order, _, err := h.trading.OpenPosition(&OpenPositionRequest{
+			AccountID:     account.ID,
+			Symbol:        "BTCUSDT",
+			Side:          models.PositionSideLong,
+			Quantity:      0.1,
+			OrderType:     models.OrderTypeLimit,
+			Price:         price,
+			ClientOrderID: "0x1234",
+		}, models.ExchangeHyperliquid)
+		return order, err
+	}
+	original, err := place(90)
+	require.NoError(t, err)
+
+	var replacement *models.Order
+	require.NoError(t, h.trading.ReplaceOrder(account.ID, original.ID, func() error {
+		replacement, err = place(95)
+		return err
+	}))
+	assert.Equal(t, models.OrderStatusNew, replacement.Status)
+	found, err := h.trading.GetOrderByClientID(account.ID, "0x1234")
+	require.NoError(t, err)
+	assert.Equal(t, replacement.ID, found.ID)
+	previous, _ := h.orders.GetByID(original.ID)
+	assert.Equal(t, models.OrderStatusCanceled, previous.Status)
+
+	// A rejected replacement leaves the order it was meant to replace open
+	rejected := errors.New("rejected")
+	err = h.trading.ReplaceOrder(account.ID, replacement.ID, func() error { return rejected })
+	assert.Equal(t, rejected, err)
+	reopened, _ := h.orders.GetByID(replacement.ID)
+	assert.Equal(t, models.OrderStatusNew, reopened.Status)
+
+	// Orders that are no longer open cannot be replaced
+	err = h.trading.ReplaceOrder(account.ID, original.ID, func() error { return nil })
+	assert.Equal(t, ErrOrderNotOpen, err)
+}
+
+// TestFillsRaceCancelsAndAmends tests that a fill of an order read before a cancel or amend is rejected,
+// that a cancel of an order read before its fill is lost, and that saving a stale account keeps the fill's fee
+func TestFillsRaceCancelsAndAmends(t *testing.T) {
+	h := newTestHarness(t)
+	h.addSymbol("hyperliquid", "BTCUSDT", hyperliquid.NewSymbolInfo("BTC", 5))
+	h.setQuote("hyperliquid", "BTCUSDT", 99.9, 100.1)
+	account := h.newAccount(t, models.ExchangeHyperliquid, 10000)
+
+	place := func() *models.Order {
+		order, _, err := h.trading.OpenPosition(&OpenPositionRequest{
+			AccountID: account.ID,
+			Symbol:    "BTCUSDT",
+			Side:      models.PositionSideLong,
+			Quantity:  0.1,
+			OrderType: models.OrderTypeLimit,
+			Price:     90,
+		}, models.ExchangeHyperliquid)
+		require.NoError(t, err)
+		return order
+	}
+
+	order := place()
+	stale, err := h.orders.GetByID(order.ID)
+	require.NoError(t, err)
+	price := 92.0
+	_, err = h.trading.AmendOrder(account.ID, order.ID, &AmendOrderRequest{Price: &price})
+	require.NoError(t, err)
+	assert.Equal(t, ErrOrderNotOpen, h.trading.claimOrder(stale, models.OrderStatusFilled))
+
+	stale, err = h.orders.GetByID(order.ID)
+	require.NoError(t, err)
+	_, err = h.trading.CancelOrder(account.ID, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ErrOrderNotOpen, h.trading.claimOrder(stale, models.OrderStatusFilled))
+
+	order = place()
+	stale, err = h.orders.GetByID(order.ID)
+	require.NoError(t, err)
+	staleAccount, err := h.accounts.GetByID(account.ID)
+	require.NoError(t, err)
+	_, _, err = h.trading.FillLimitOrder(order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), h.trading.cancelOrders([]models.Order{*stale}))
+	filled, _ := h.orders.GetByID(order.ID)
+	assert.Equal(t, models.OrderStatusFilled, filled.Status)
+
+	require.NoError(t, h.accounts.Update(staleAccount))
+	stored, err := h.accounts.GetByID(account.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 10000-90*0.1*0.0002, stored.BalanceUSDT, 1e-9)
+}
+
+// TestAmendAcrossQuote tests that limit orders amended across the quote fill at once as taker,
+// while post-only orders are rejected and left unchanged
+func TestAmendAcrossQuote(t *testing.T) {
+	h := newTestHarness(t)
+	h.addSymbol("hyperliquid", "BTCUSDT", hyperliquid.NewSymbolInfo("BTC", 5))
+	h.setQuote("hyperliquid", "BTCUSDT", 99.9, 100.1)
+	account := h.newAccount(t, models.ExchangeHyperliquid, 10000)
+
+	open := func(timeInForce string) *models.Order {
+		order, _, err := h.trading.OpenPosition(&OpenPositionRequest{
+			AccountID:   account.ID,
+			Symbol:      "BTCUSDT",
+			Side:        models.PositionSideLong,
+			Quantity:    0.1,
+			OrderType:   models.OrderTypeLimit,
+			Price:       90,
+			TimeInForce: timeInForce,
+		}, models.ExchangeHyperliquid)
+		require.NoError(t, err)
+		return order
+	}
+	price := 101.0
+
+	postOnly := open(models.TimeInForceGTX)
+	_, err := h.trading.AmendOrder(account.ID, postOnly.ID, &AmendOrderRequest{Price: &price})
+	assert.Equal(t, ErrPostOnlyWouldTake, err)
+	stored, _ := h.orders.GetByID(postOnly.ID)
+	assert.Equal(t, models.OrderStatusNew, stored.Status)
+	assert.Equal(t, 90.0, stored.Price)
+
+	order, err := h.trading.AmendOrder(account.ID, open(models.TimeInForceGTC).ID, &AmendOrderRequest{Price: &price})
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderStatusFilled, order.Status)
+	assert.Equal(t, 100.1, order.AvgPrice)
+	position, err := h.position.GetByAccountIDSymbolAndSide(account.ID, "BTCUSDT", models.PositionSideLong)
+	require.NoError(t, err)
+	assert.Equal(t, 0.1, position.Quantity)
+
+	// Reduce-only limits amended across the quote close the position at the bid
+	closing, _, err := h.trading.ClosePosition(&ClosePositionRequest{
+		AccountID: account.ID,
+		Symbol:    "BTCUSDT",
+		Side:      models.PositionSideLong,
+		OrderType: models.OrderTypeLimit,
+		Price:     110,
+	}, models.ExchangeHyperliquid)
+	require.NoError(t, err)
+	price = 99.0
+	closing, err = h.trading.AmendOrder(account.ID, closing.ID, &AmendOrderRequest{Price: &price})
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderStatusFilled, closing.Status)
+	assert.Equal(t, 99.9, closing.AvgPrice)
+	_, err = h.position.GetByAccountIDSymbolAndSide(account.ID, "BTCUSDT", models.PositionSideLong)
+	assert.Error(t, err)
+}
+
+// TestCancelsReachUserStreams tests that bulk cancels and close cascades publish an order update per canceled order,
+// and that closing one hedge-mode side leaves the other side's SL/TP orders open
+func TestCancelsReachUserStreams(t *testing.T) {
+	h := newTestHarness(t)
+	h.addSymbol("binance", "BTCUSDT", &exchange.SymbolInfo{Symbol: "BTCUSDT", MinQty: 0.001, MaxQty: 1000, QuantityPrecision: 3, PricePrecision: 2})
+	h.setQuote("binance", "BTCUSDT", 99.9, 100.1)
+	account := h.newAccount(t, models.ExchangeBinance, 100000)
+	events := h.trading.Events().Subscribe(account.ID)
+
+	for _, side := range []models.PositionSide{models.PositionSideLong, models.PositionSideShort} {
+		_, _, err := h.trading.OpenPosition(&OpenPositionRequest{AccountID: account.ID, Symbol: "BTCUSDT", Side: side, Quantity: 1}, models.ExchangeBinance)
+		require.NoError(t, err)
+	}
+	stops := make(map[models.PositionSide]*models.Order)
+	for side, stopPrice := range map[models.PositionSide]float64{models.PositionSideLong: 90, models.PositionSideShort: 110} {
+		stop, err := h.trading.CreateConditionalOrder(&ConditionalOrderRequest{
+			AccountID:     account.ID,
+			Symbol:        "BTCUSDT",
+			Side:          side,
+			OrderType:     models.OrderTypeStopMarket,
+			StopPrice:     stopPrice,
+			ClosePosition: true,
+		}, models.ExchangeBinance)
+		require.NoError(t, err)
+		stops[side] = stop
+	}
+	drainOrderEvents(events)
+
+	// Closing the long cancels the long stop only
+	_, _, err := h.trading.ClosePosition(&ClosePositionRequest{AccountID: account.ID, Symbol: "BTCUSDT", Side: models.PositionSideLong}, models.ExchangeBinance)
+	require.NoError(t, err)
+	var canceled []uint
+	for _, order := range drainOrderEvents(events) {
+		if order.Status == models.OrderStatusCanceled {
+			canceled = append(canceled, order.ID)
+		}
+	}
+	assert.Equal(t, []uint{stops[models.PositionSideLong].ID}, canceled)
+	shortStop, _ := h.orders.GetByID(stops[models.PositionSideShort].ID)
+	assert.Equal(t, models.OrderStatusNew, shortStop.Status)
+
+	// Cancel all publishes each canceled order
+	count, err := h.trading.CancelAllOrders(account.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	updates := drainOrderEvents(events)
+	require.Len(t, updates, 1)
+	assert.Equal(t, shortStop.ID, updates[0].ID)
+	assert.Equal(t, models.OrderStatusCanceled, updates[0].Status)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"github.com/ccxt-simulator/internal/models"
+)
+
+// validateTrailingStop checks that a trailing stop has exactly one callback: a rate in (0, 1) or a positive spread
+func validateTrailingStop(req *ConditionalOrderRequest) error {
+	hasRate, hasSpread := req.CallbackRate != 0, req.CallbackSpread != 0
+	if hasRate == hasSpread || req.CallbackRate < 0 || req.CallbackRate >= 1 || req.CallbackSpread < 0 || req.ActivationPrice < 0 {
+		return ErrInvalidCallback
+	}
+	return nil
+}
+
+// advanceTrailingStop moves a trailing stop's watermark to the best price since activation
+// and its stop price to the callback behind the watermark; it reports whether the order changed
+// Stops closing a long track the high and trigger below it, stops closing a short track the low and trigger above it
+func advanceTrailingStop(order *models.Order, price float64) bool {
+	if order.Type != models.OrderTypeTrailingStop || price <= 0 {
+		return false
+	}
+
+	closesLong := order.PositionSide == models.PositionSideLong
+	if order.Watermark == 0 {
+		// Inactive until price reaches the activation price
+		if order.ActivationPrice > 0 && ((closesLong && price < order.ActivationPrice) || (!closesLong && price > order.ActivationPrice)) {
+			return false
+		}
+	} else if (closesLong && price <= order.Watermark) || (!closesLong && price >= order.Watermark) {
+		return false
+	}
+
+	distance := order.CallbackSpread
+	if order.CallbackRate > 0 {
+		distance = price * order.CallbackRate
+	}
+
+	order.Watermark = price
+	if closesLong {
+		order.StopPrice = price - distance
+	} else {
+		order.StopPrice = price + distance
+	}
+	return true
+}
+
+// TrackTrailingStop advances a pending trailing stop with the latest price and saves its new watermark
+func (s *TradingService) TrackTrailingStop(order *models.Order, price float64) error {
+	if !advanceTrailingStop(order, price) {
+		return nil
+	}
+	updated, err := s.orderRepo.UpdateIfOpen(order)
+	if err == nil && !updated {
+		return ErrOrderNotOpen
+	}
+	return err
+}
+
+// SetTrailingStop replaces the trailing stop that closes a whole position
+// distance is the callback as a price distance; zero removes the trailing stop
+func (s *TradingService) SetTrailingStop(accountID uint, symbol string, side models.PositionSide, distance, activationPrice float64, exchangeType models.ExchangeType) (*models.Order, error) {
+	if _, err := s.positionRepo.GetByAccountIDSymbolAndSide(accountID, symbol, side); err != nil {
+		return nil, ErrPositionNotFound
+	}
+
+	orders, err := s.orderRepo.GetOpenOrdersBySymbolAndTypes(accountID, symbol, []models.OrderType{models.OrderTypeTrailingStop})
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		if order.PositionSide == side && order.ClosePosition {
+			if _, err := s.CancelOrder(accountID, order.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if distance == 0 {
+		return nil, nil
+	}
+	return s.CreateConditionalOrder(&ConditionalOrderRequest{
+		AccountID:       accountID,
+		Symbol:          symbol,
+		Side:            side,
+		OrderType:       models.OrderTypeTrailingStop,
+		ClosePosition:   true,
+		ReduceOnly:      true,
+		CallbackSpread:  distance,
+		ActivationPrice: activationPrice,
+	}, exchangeType)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAdvanceTrailingStop tests activation and watermark tracking for stops closing longs and shorts
+func TestAdvanceTrailingStop(t *testing.T) {
+	long := &models.Order{Type: models.OrderTypeTrailingStop, PositionSide: models.PositionSideLong, CallbackRate: 0.01, ActivationPrice: 105}
+
+	assert.False(t, advanceTrailingStop(long, 104), "Should stay inactive below the activation price")
+	assert.Zero(t, long.StopPrice)
+
+	assert.True(t, advanceTrailingStop(long, 110))
+	assert.Equal(t, 110.0, long.Watermark)
+	assert.InDelta(t, 108.9, long.StopPrice, 1e-9)
+
+	assert.False(t, advanceTrailingStop(long, 109), "A retrace should not move the watermark")
+	assert.True(t, advanceTrailingStop(long, 120))
+	assert.InDelta(t, 118.8, long.StopPrice, 1e-9)
+
+	short := &models.Order{Type: models.OrderTypeTrailingStop, PositionSide: models.PositionSideShort, CallbackSpread: 5}
+
+	assert.True(t, advanceTrailingStop(short, 100), "Should activate at once without an activation price")
+	assert.Equal(t, 105.0, short.StopPrice)
+	assert.False(t, advanceTrailingStop(short, 101))
+	assert.True(t, advanceTrailingStop(short, 90))
+	assert.Equal(t, 90.0, short.Watermark)
+	assert.Equal(t, 95.0, short.StopPrice)
+}
+
+// TestValidateTrailingStop tests that exactly one valid callback is required
+func TestValidateTrailingStop(t *testing.T) {
+	assert.NoError(t, validateTrailingStop(&ConditionalOrderRequest{CallbackRate: 0.01}))
+	assert.NoError(t, validateTrailingStop(&ConditionalOrderRequest{CallbackSpread: 50, ActivationPrice: 100}))
+	assert.Equal(t, ErrInvalidCallback, validateTrailingStop(&ConditionalOrderRequest{}))
+	assert.Equal(t, ErrInvalidCallback, validateTrailingStop(&ConditionalOrderRequest{CallbackRate: 0.01, CallbackSpread: 50}))
+	assert.Equal(t, ErrInvalidCallback, validateTrailingStop(&ConditionalOrderRequest{CallbackRate: 1.5}))
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"github.com/ccxt-simulator/internal/exchange"
+	"github.com/ccxt-simulator/internal/models"
+)
+
+// validateTriggerPriceType checks a conditional order's trigger price type; empty means the last price
+func validateTriggerPriceType(priceType models.TriggerPriceType) (models.TriggerPriceType, error) {
+	switch priceType {
+	case "":
+		return models.TriggerPriceLast, nil
+	case models.TriggerPriceLast, models.TriggerPriceMark, models.TriggerPriceIndex:
+		return priceType, nil
+	default:
+		return "", ErrInvalidTrigger
+	}
+}
+
+// TriggerSourcePrice returns the price in a quote that a conditional order's trigger is checked against
+// Feeds without a separate stream for the chosen price fall back to their main price
+func TriggerSourcePrice(quote exchange.PriceUpdate, priceType models.TriggerPriceType) float64 {
+	switch priceType {
+	case models.TriggerPriceMark:
+		return quote.Mark()
+	case models.TriggerPriceIndex:
+		return quote.Index()
+	default:
+		return quote.Last()
+	}
+}
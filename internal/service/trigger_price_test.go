@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/ccxt-simulator/internal/exchange"
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTriggerSourcePrice tests that each trigger price type reads its own price stream
+func TestTriggerSourcePrice(t *testing.T) {
+	quote := exchange.PriceUpdate{Price: 100, LastPrice: 98, MarkPrice: 100, IndexPrice: 101}
+	assert.Equal(t, 98.0, TriggerSourcePrice(quote, models.TriggerPriceLast))
+	assert.Equal(t, 100.0, TriggerSourcePrice(quote, models.TriggerPriceMark))
+	assert.Equal(t, 101.0, TriggerSourcePrice(quote, models.TriggerPriceIndex))
+	assert.Equal(t, 98.0, TriggerSourcePrice(quote, ""), "Orders without a type should use the last price")
+
+	// Feeds with a single price stream serve every trigger type from it
+	single := exchange.PriceUpdate{Price: 100}
+	assert.Equal(t, 100.0, TriggerSourcePrice(single, models.TriggerPriceLast))
+	assert.Equal(t, 100.0, TriggerSourcePrice(single, models.TriggerPriceIndex))
+}
+
+// TestValidateTriggerPriceType tests the default and rejection of unknown trigger price types
+func TestValidateTriggerPriceType(t *testing.T) {
+	priceType, err := validateTriggerPriceType("")
+	assert.NoError(t, err)
+	assert.Equal(t, models.TriggerPriceLast, priceType)
+
+	priceType, err = validateTriggerPriceType(models.TriggerPriceMark)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TriggerPriceMark, priceType)
+
+	_, err = validateTriggerPriceType("BID")
+	assert.Equal(t, ErrInvalidTrigger, err)
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ccxt-simulator/internal/models"
+)
+
+// UserEventType identifies the kind of private account event
+type UserEventType string
+
+const (
+	UserEventOrderUpdate   UserEventType = "order_update"
+	UserEventAccountUpdate UserEventType = "account_update"
+)
+
+// Account update reasons
+const (
+	UserEventReasonOrder          = "ORDER"
+	UserEventReasonFundingFee     = "FUNDING_FEE"
+	UserEventReasonMarginTransfer = "MARGIN_TRANSFER"
+)
+
+// userEventBufferSize is the per-subscriber event buffer; slow subscribers drop events
+const userEventBufferSize = 256
+
+// UserEvent is a private account event emitted when orders, balances or positions change
+type UserEvent struct {
+	Type      UserEventType
+	AccountID uint
+	Time      time.Time
+
+	// Order updates
+	Order       *models.Order
+	Trade       *models.Trade // Set when the update is a fill
+	Liquidation bool          // Set when the fill was forced by the liquidation engine
+	Amendment   bool          // Set when a resting order was modified in place
+
+	// Account updates
+	Account   *models.Account
+	Positions []models.Position // Positions touched by the update; Quantity 0 means closed
+	Reason    string
+}
+
+// UserEventHub fans out user events to per-account subscribers (e.g. private WebSocket streams)
+type UserEventHub struct {
+	subscribers map[uint]map[chan UserEvent]struct{}
+	mu          sync.RWMutex
+}
+
+// NewUserEventHub creates a new UserEventHub
+func NewUserEventHub() *UserEventHub {
+	return &UserEventHub{
+		subscribers: make(map[uint]map[chan UserEvent]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber for an account's events
+func (h *UserEventHub) Subscribe(accountID uint) chan UserEvent {
+	ch := make(chan UserEvent, userEventBufferSize)
+
+	h.mu.Lock()
+	if h.subscribers[accountID] == nil {
+		h.subscribers[accountID] = make(map[chan UserEvent]struct{})
+	}
+	h.subscribers[accountID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch
+}
+
+// Unsubscribe removes a subscriber
+func (h *UserEventHub) Unsubscribe(accountID uint, ch chan UserEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subscribers[accountID], ch)
+	if len(h.subscribers[accountID]) == 0 {
+		delete(h.subscribers, accountID)
+	}
+}
+
+// Publish delivers an event to all subscribers of its account without blocking
+func (h *UserEventHub) Publish(event UserEvent) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers[event.AccountID] {
+		select {
+		case ch <- event:
+		default:
+			// Subscriber is not keeping up, drop the event
+		}
+	}
+}
+
+// PublishOrder emits an order update; trade is nil unless the order was filled
+// Snapshots are copied since subscribers consume events asynchronously
+func (h *UserEventHub) PublishOrder(order *models.Order, trade *models.Trade) {
+	orderCopy := *order
+	event := UserEvent{
+		Type:        UserEventOrderUpdate,
+		AccountID:   order.AccountID,
+		Order:       &orderCopy,
+		Liquidation: order.IsLiquidation(),
+	}
+	if trade != nil {
+		tradeCopy := *trade
+		event.Trade = &tradeCopy
+	}
+	h.Publish(event)
+}
+
+// PublishOrderAmendment emits an order update for an order amended in place
+func (h *UserEventHub) PublishOrderAmendment(order *models.Order) {
+	orderCopy := *order
+	h.Publish(UserEvent{
+		Type:      UserEventOrderUpdate,
+		AccountID: order.AccountID,
+		Order:     &orderCopy,
+		Amendment: true,
+	})
+}
+
+// PublishAccount emits a balance/position update
+func (h *UserEventHub) PublishAccount(account *models.Account, reason string, positions ...models.Position) {
+	accountCopy := *account
+	h.Publish(UserEvent{
+		Type:      UserEventAccountUpdate,
+		AccountID: account.ID,
+		Account:   &accountCopy,
+		Positions: positions,
+		Reason:    reason,
+	})
+}
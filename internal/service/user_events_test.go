@@ -0,0 +1,32 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUserEventHubRoutesByAccount tests that events only reach the owning account's subscribers
+func TestUserEventHubRoutesByAccount(t *testing.T) {
+	hub := NewUserEventHub()
+	mine := hub.Subscribe(1)
+	other := hub.Subscribe(2)
+
+	order := &models.Order{ID: 10, AccountID: 1, Status: models.OrderStatusNew}
+	hub.PublishOrder(order, nil)
+
+	assert.Len(t, mine, 1)
+	assert.Len(t, other, 0)
+
+	// Events carry a snapshot, later mutations must not leak into queued events
+	order.Status = models.OrderStatusFilled
+	event := <-mine
+	assert.Equal(t, UserEventOrderUpdate, event.Type)
+	assert.Equal(t, models.OrderStatusNew, event.Order.Status)
+	assert.False(t, event.Time.IsZero())
+
+	hub.Unsubscribe(1, mine)
+	hub.PublishOrder(order, nil)
+	assert.Len(t, mine, 0)
+}
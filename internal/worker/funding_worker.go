@@ -0,0 +1,92 @@
+package worker
+
+import (
+	"log"
+	"time"
+
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/ccxt-simulator/internal/service"
+)
+
+// fundingExchanges lists the exchanges whose positions are charged funding
+var fundingExchanges = []models.ExchangeType{
+	models.ExchangeBinance,
+	models.ExchangeOKX,
+	models.ExchangeBybit,
+	models.ExchangeBitget,
+	models.ExchangeHyperliquid,
+}
+
+// FundingWorker settles funding on each exchange's schedule
+// (every 8h at 00:00/08:00/16:00 UTC, hourly for Hyperliquid)
+type FundingWorker struct {
+	fundingService *service.FundingService
+	interval       time.Duration
+	lastSettled    map[models.ExchangeType]time.Time
+	stopChan       chan struct{}
+}
+
+// NewFundingWorker creates a new funding settlement worker
+func NewFundingWorker(fundingService *service.FundingService, interval time.Duration) *FundingWorker {
+	if interval <= 0 {
+		interval = 1 * time.Minute // Default 1 minute check interval
+	}
+	return &FundingWorker{
+		fundingService: fundingService,
+		interval:       interval,
+		lastSettled:    make(map[models.ExchangeType]time.Time),
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Start begins the settlement loop
+func (w *FundingWorker) Start() {
+	log.Printf("Funding Worker started with interval: %v", w.interval)
+
+	// Only settle boundaries crossed while running; positions opened before
+	// startup are not charged for an interval that has already passed
+	now := time.Now().UTC()
+	for _, exchangeType := range fundingExchanges {
+		w.lastSettled[exchangeType] = now.Truncate(models.FundingInterval(exchangeType))
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.checkAndSettle()
+		case <-w.stopChan:
+			log.Println("Funding Worker stopped")
+			return
+		}
+	}
+}
+
+// Stop stops the settlement loop
+func (w *FundingWorker) Stop() {
+	close(w.stopChan)
+}
+
+// checkAndSettle settles funding for every exchange whose funding time has passed
+func (w *FundingWorker) checkAndSettle() {
+	now := time.Now().UTC()
+	for _, exchangeType := range fundingExchanges {
+		fundingTime := now.Truncate(models.FundingInterval(exchangeType))
+		if !fundingTime.After(w.lastSettled[exchangeType]) {
+			continue
+		}
+
+		settled, err := w.fundingService.SettleFunding(exchangeType, fundingTime)
+		if err != nil {
+			log.Printf("Funding Worker: failed to settle %s funding: %v", exchangeType, err)
+			continue
+		}
+		w.lastSettled[exchangeType] = fundingTime
+
+		if settled > 0 {
+			log.Printf("Funding Worker: settled %d %s positions at %s", settled, exchangeType, fundingTime.Format(time.RFC3339))
+		}
+	}
+}
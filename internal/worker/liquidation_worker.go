@@ -0,0 +1,175 @@
+package worker
+
+import (
+	"log"
+	"time"
+
+	"github.com/ccxt-simulator/internal/models"
+	"github.com/ccxt-simulator/internal/repository"
+	"github.com/ccxt-simulator/internal/service"
+)
+
+// LiquidationWorker monitors open positions and force-closes those
+// whose mark price breaches the liquidation price or maintenance margin
+type LiquidationWorker struct {
+	tradingService *service.TradingService
+	positionRepo   *repository.PositionRepository
+	accountRepo    *repository.AccountRepository
+	interval       time.Duration
+	stopChan       chan struct{}
+}
+
+// NewLiquidationWorker creates a new liquidation monitoring worker
+func NewLiquidationWorker(
+	tradingService *service.TradingService,
+	positionRepo *repository.PositionRepository,
+	accountRepo *repository.AccountRepository,
+	interval time.Duration,
+) *LiquidationWorker {
+	if interval <= 0 {
+		interval = 1 * time.Second // Default 1 second check interval
+	}
+	return &LiquidationWorker{
+		tradingService: tradingService,
+		positionRepo:   positionRepo,
+		accountRepo:    accountRepo,
+		interval:       interval,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Start begins the monitoring loop
+func (w *LiquidationWorker) Start() {
+	log.Printf("Liquidation Worker started with interval: %v", w.interval)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.checkAndLiquidate()
+		case <-w.stopChan:
+			log.Println("Liquidation Worker stopped")
+			return
+		}
+	}
+}
+
+// Stop stops the monitoring loop
+func (w *LiquidationWorker) Stop() {
+	close(w.stopChan)
+}
+
+// checkAndLiquidate checks all open positions against their account's exchange mark price
+// Isolated positions are checked against their own margin; cross positions are liquidated
+// once the account's cross equity no longer covers their maintenance margin
+// Accounts with a cross position that has no price are skipped, as their cross equity is unknown
+func (w *LiquidationWorker) checkAndLiquidate() {
+	positions, err := w.positionRepo.GetAll()
+	if err != nil {
+		log.Printf("Liquidation Worker: failed to get positions: %v", err)
+		return
+	}
+
+	if len(positions) == 0 {
+		return
+	}
+
+	priceService := w.tradingService.GetPriceService()
+	accounts := make(map[uint]*models.Account)
+	books := make(map[uint][]models.Position) // accountID -> positions with current mark prices
+	unpriced := make(map[uint]bool)           // accountIDs with a cross position that has no price
+
+	for _, position := range positions {
+		account, ok := accounts[position.AccountID]
+		if !ok {
+			account, err = w.accountRepo.GetByID(position.AccountID)
+			if err != nil {
+				continue
+			}
+			accounts[position.AccountID] = account
+		}
+
+		// Positions are valued at the mark price, or the last price when the feed has no mark stream
+		var markPrice float64
+		if quote, err := priceService.GetQuote(string(account.ExchangeType), position.Symbol); err == nil {
+			markPrice = quote.MarkPrice
+			if markPrice <= 0 {
+				markPrice = quote.Last()
+			}
+		}
+		if markPrice <= 0 {
+			// Skip if no price available
+			if !position.IsIsolated() {
+				unpriced[account.ID] = true
+			}
+			continue
+		}
+
+		position.MarkPrice = markPrice
+		position.UnrealizedPnL = position.CalculateUnrealizedPnL(markPrice)
+		books[account.ID] = append(books[account.ID], position)
+	}
+
+	for accountID, book := range books {
+		if unpriced[accountID] {
+			continue
+		}
+		w.liquidateAccount(accounts[accountID], book)
+	}
+}
+
+// liquidateAccount liquidates an account's breached positions
+// The cross state is recomputed after every liquidation, which realizes its loss and releases its margin,
+// so cross positions are only liquidated while the remaining book is still underwater
+func (w *LiquidationWorker) liquidateAccount(account *models.Account, book []models.Position) {
+	priceService := w.tradingService.GetPriceService()
+	remaining := append([]models.Position(nil), book...)
+
+	for i := range book {
+		position := &book[i]
+		crossState := w.tradingService.CrossMarginState(account, remaining, account.ExchangeType)
+		if position.IsIsolated() {
+			mmr := priceService.GetMaintenanceMarginRate(string(account.ExchangeType), position.MarkPrice*position.Quantity)
+			if !position.IsLiquidatable(position.MarkPrice, mmr) {
+				continue
+			}
+		} else if !crossState.IsLiquidatable() {
+			continue
+		}
+
+		if !w.liquidate(position, crossState) {
+			continue
+		}
+		for j := range remaining {
+			if remaining[j].ID == position.ID {
+				remaining = append(remaining[:j], remaining[j+1:]...)
+				break
+			}
+		}
+		if reloaded, err := w.accountRepo.GetByID(account.ID); err == nil {
+			account = reloaded
+		}
+	}
+}
+
+// liquidate force-closes a position at its mark price, reporting whether it was liquidated
+func (w *LiquidationWorker) liquidate(position *models.Position, crossState models.CrossMarginState) bool {
+	if position.IsIsolated() {
+		log.Printf("Liquidation Worker: liquidating isolated position %d (account=%d, symbol=%s, side=%s, margin=%.8f, liqPrice=%.8f, markPrice=%.8f)",
+			position.ID, position.AccountID, position.Symbol, position.Side, position.Margin, position.LiquidationPrice, position.MarkPrice)
+	} else {
+		log.Printf("Liquidation Worker: liquidating cross position %d (account=%d, symbol=%s, side=%s, crossEquity=%.8f, maintMargin=%.8f, markPrice=%.8f)",
+			position.ID, position.AccountID, position.Symbol, position.Side, crossState.Equity(), crossState.MaintenanceMargin, position.MarkPrice)
+	}
+
+	order, closedPnL, err := w.tradingService.LiquidatePosition(position, position.MarkPrice)
+	if err != nil {
+		log.Printf("Liquidation Worker: failed to liquidate position %d: %v", position.ID, err)
+		return false
+	}
+
+	log.Printf("Liquidation Worker: position %d liquidated by order %d, PnL=%.8f",
+		position.ID, order.ID, closedPnL.RealizedPnL)
+	return true
+}
@@ -14,6 +14,7 @@ import (
 type SLTPWorker struct {
 	tradingService *service.TradingService
 	orderRepo      *repository.OrderRepository
+	accountRepo    *repository.AccountRepository
 	interval       time.Duration
 	stopChan       chan struct{}
 }
@@ -22,6 +23,7 @@ type SLTPWorker struct {
 func NewSLTPWorker(
 	tradingService *service.TradingService,
 	orderRepo *repository.OrderRepository,
+	accountRepo *repository.AccountRepository,
 	interval time.Duration,
 ) *SLTPWorker {
 	if interval <= 0 {
@@ -30,6 +32,7 @@ func NewSLTPWorker(
 	return &SLTPWorker{
 		tradingService: tradingService,
 		orderRepo:      orderRepo,
+		accountRepo:    accountRepo,
 		interval:       interval,
 		stopChan:       make(chan struct{}),
 	}
@@ -71,36 +74,42 @@ func (w *SLTPWorker) checkAndTriggerOrders() {
 	}
 
 	priceService := w.tradingService.GetPriceService()
+	accounts := make(map[uint]*models.Account)
 
 	for _, order := range orders {
-		// Get current price for the symbol
-		// Try each exchange until we get a price
-		var currentPrice float64
-		var priceErr error
-
-		exchanges := []string{"binance", "okx", "bybit", "bitget"}
-		for _, exchange := range exchanges {
-			currentPrice, priceErr = priceService.GetPrice(exchange, order.Symbol)
-			if priceErr == nil && currentPrice > 0 {
-				break
+		account, ok := accounts[order.AccountID]
+		if !ok {
+			account, err = w.accountRepo.GetByID(order.AccountID)
+			if err != nil {
+				continue
 			}
+			accounts[order.AccountID] = account
 		}
 
-		if priceErr != nil || currentPrice <= 0 {
+		// Check the trigger against the order's price stream (last, mark or index) on its account's exchange
+		var currentPrice float64
+		if quote, err := priceService.GetQuote(string(account.ExchangeType), order.Symbol); err == nil {
+			currentPrice = service.TriggerSourcePrice(*quote, order.TriggerPriceType)
+		}
+
+		if currentPrice <= 0 {
 			// Skip if no price available
 			continue
 		}
 
+		// Trailing stops follow the price before their trigger is checked
+		if err := w.tradingService.TrackTrailingStop(&order, currentPrice); err != nil {
+			log.Printf("SL/TP Worker: failed to track trailing stop %d: %v", order.ID, err)
+			continue
+		}
+
 		// Check if order should be triggered
 		if w.shouldTrigger(&order, currentPrice) {
 			log.Printf("SL/TP Worker: triggering order %d (type=%s, symbol=%s, stopPrice=%.8f, currentPrice=%.8f)",
 				order.ID, order.Type, order.Symbol, order.StopPrice, currentPrice)
 
-			// Determine exchange type from account (for now default to binance)
-			exchangeType := models.ExchangeBinance
-
 			// Execute the triggered order
-			closedPnL, err := w.tradingService.ExecuteTriggeredOrder(&order, exchangeType)
+			closedPnL, err := w.tradingService.ExecuteTriggeredOrder(&order)
 			if err != nil {
 				log.Printf("SL/TP Worker: failed to execute order %d: %v", order.ID, err)
 				continue
@@ -116,19 +125,22 @@ func (w *SLTPWorker) checkAndTriggerOrders() {
 
 // shouldTrigger checks if the order should be triggered based on current price
 // Trigger logic:
-// | Order Type    | Position Side | Trigger Condition       |
-// |---------------|---------------|-------------------------|
-// | STOP_MARKET   | LONG          | markPrice <= stopPrice  |
-// | STOP_MARKET   | SHORT         | markPrice >= stopPrice  |
-// | TAKE_PROFIT   | LONG          | markPrice >= stopPrice  |
-// | TAKE_PROFIT   | SHORT         | markPrice <= stopPrice  |
+// | Order Type    | Position Side | Trigger Condition          |
+// |---------------|---------------|----------------------------|
+// | STOP_MARKET   | LONG          | triggerPrice <= stopPrice  |
+// | STOP_MARKET   | SHORT         | triggerPrice >= stopPrice  |
+// | TAKE_PROFIT   | LONG          | triggerPrice >= stopPrice  |
+// | TAKE_PROFIT   | SHORT         | triggerPrice <= stopPrice  |
+// triggerPrice is the order's trigger price stream: last, mark or index
+// Trailing stops trigger like stop losses once active; their stop price trails the watermark
 func (w *SLTPWorker) shouldTrigger(order *models.Order, currentPrice float64) bool {
 	stopPrice := order.StopPrice
 	if stopPrice <= 0 {
 		return false
 	}
 
-	isStopLoss := order.Type == models.OrderTypeStopMarket || order.Type == models.OrderTypeStopLoss
+	isStopLoss := order.Type == models.OrderTypeStopMarket || order.Type == models.OrderTypeStopLoss ||
+		order.Type == models.OrderTypeTrailingStop
 	isTakeProfit := order.Type == models.OrderTypeTakeProfit
 
 	switch {
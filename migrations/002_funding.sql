@@ -0,0 +1,34 @@
+-- CCXT Simulator Database Schema
+-- Version: 1.1 - Funding fee settlement
+
+-- Create funding_rates table
+CREATE TABLE IF NOT EXISTS funding_rates (
+    id BIGSERIAL PRIMARY KEY,
+    exchange VARCHAR(20) NOT NULL,
+    symbol VARCHAR(20) NOT NULL,
+    rate DECIMAL(20, 10) NOT NULL,
+    mark_price DECIMAL(20, 8),
+    funding_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_funding_rates_exchange_symbol_time ON funding_rates(exchange, symbol, funding_time);
+
+-- Create funding_fees table (funding ledger)
+CREATE TABLE IF NOT EXISTS funding_fees (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    exchange VARCHAR(20) NOT NULL,
+    symbol VARCHAR(20) NOT NULL,
+    side VARCHAR(10) NOT NULL,
+    quantity DECIMAL(20, 8) NOT NULL,
+    mark_price DECIMAL(20, 8) NOT NULL,
+    funding_rate DECIMAL(20, 10) NOT NULL,
+    amount DECIMAL(20, 8) NOT NULL,
+    balance_after DECIMAL(20, 8),
+    funding_time TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_funding_fees_account_id ON funding_fees(account_id);
+CREATE INDEX idx_funding_fees_symbol ON funding_fees(symbol);
+CREATE INDEX idx_funding_fees_funding_time ON funding_fees(funding_time);
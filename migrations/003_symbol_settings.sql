@@ -0,0 +1,16 @@
+-- CCXT Simulator Database Schema
+-- Version: 1.2 - Persisted per-symbol leverage, margin mode and position mode
+
+-- Create symbol_settings table
+CREATE TABLE IF NOT EXISTS symbol_settings (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    symbol VARCHAR(20) NOT NULL,
+    leverage INTEGER NOT NULL,
+    margin_mode VARCHAR(20) NOT NULL DEFAULT 'cross',
+    hedge_mode BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_symbol_settings_account_symbol ON symbol_settings(account_id, symbol);
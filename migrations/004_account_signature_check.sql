@@ -0,0 +1,4 @@
+-- CCXT Simulator Database Schema
+-- Version: 1.3 - Account-level toggle to relax Binance request signing
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS skip_signature_check BOOLEAN DEFAULT FALSE;
@@ -0,0 +1,15 @@
+-- CCXT Simulator Database Schema
+-- Version: 1.4 - Hyperliquid agent (API) wallets
+
+-- Create agent_wallets table
+CREATE TABLE IF NOT EXISTS agent_wallets (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL DEFAULT '',
+    address VARCHAR(42) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_agent_wallets_account_name ON agent_wallets(account_id, name);
+CREATE UNIQUE INDEX idx_agent_wallets_address ON agent_wallets(address);
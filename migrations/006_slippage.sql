@@ -0,0 +1,6 @@
+-- CCXT Simulator Database Schema
+-- Version: 1.5 - Per-account taker slippage model
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS slippage_model VARCHAR(20) DEFAULT 'fixed';
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS slippage_bps DECIMAL(10, 4) DEFAULT 1;
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS slippage_depth DECIMAL(20, 2) DEFAULT 1000000;
@@ -0,0 +1,7 @@
+-- CCXT Simulator Database Schema
+-- Version: 1.6 - Trailing stop orders
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS callback_rate DECIMAL(10, 6);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS callback_spread DECIMAL(20, 8);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS activation_price DECIMAL(20, 8);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS watermark DECIMAL(20, 8);
@@ -0,0 +1,5 @@
+-- CCXT Simulator Database Schema
+-- Version: 1.7 - Link stop-limit and take-profit-limit orders to the limit order they place
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS triggered_order_id BIGINT;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS parent_order_id BIGINT;
@@ -0,0 +1,4 @@
+-- CCXT Simulator Database Schema
+-- Version: 1.8 - Conditional order trigger price stream
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS trigger_price_type VARCHAR(10);
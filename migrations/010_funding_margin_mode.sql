@@ -0,0 +1,4 @@
+-- CCXT Simulator Database Schema
+-- Version: 1.9 - Margin mode of the position a funding fee was settled against
+
+ALTER TABLE funding_fees ADD COLUMN IF NOT EXISTS margin_mode VARCHAR(20) DEFAULT 'cross';
@@ -0,0 +1,4 @@
+-- CCXT Simulator Database Schema
+-- Version: 1.10 - Good till date of GTD limit orders
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS good_till_date TIMESTAMP WITH TIME ZONE;
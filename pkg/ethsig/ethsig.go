@@ -0,0 +1,156 @@
+// Package ethsig implements the minimal Ethereum signing primitives needed to
+// authenticate wallet-signed requests: keccak256, secp256k1 address recovery and EIP-712 typed data hashing.
+package ethsig
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Keccak256 returns the legacy Keccak-256 hash used by Ethereum
+func Keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// publicKeyToAddress returns the last 20 bytes of keccak(X||Y) as a lowercase hex address
+func publicKeyToAddress(p point) string {
+	return "0x" + hex.EncodeToString(Keccak256(pointBytes(p))[12:])
+}
+
+// AddressBytes decodes a 0x-prefixed 20-byte address
+func AddressBytes(address string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(address), "0x"))
+	if err != nil || len(b) != 20 {
+		return nil, fmt.Errorf("invalid address: %s", address)
+	}
+	return b, nil
+}
+
+// Field is one member of an EIP-712 struct type
+type Field struct {
+	Name string
+	Type string
+}
+
+// Domain is an EIP-712 domain (name, version, chainId, verifyingContract)
+type Domain struct {
+	Name              string
+	Version           string
+	ChainID           int64
+	VerifyingContract string
+}
+
+var domainFields = []Field{
+	{Name: "name", Type: "string"},
+	{Name: "version", Type: "string"},
+	{Name: "chainId", Type: "uint256"},
+	{Name: "verifyingContract", Type: "address"},
+}
+
+// Separator returns the EIP-712 domain separator
+func (d Domain) Separator() ([]byte, error) {
+	return HashStruct("EIP712Domain", domainFields, []interface{}{d.Name, d.Version, d.ChainID, d.VerifyingContract})
+}
+
+// HashStruct returns hashStruct(primaryType) for a flat struct of atomic fields
+// Supported types: string, address, bytes32, bool and uint*/int* (as int64, uint64 or *big.Int)
+func HashStruct(primaryType string, fields []Field, values []interface{}) ([]byte, error) {
+	if len(fields) != len(values) {
+		return nil, fmt.Errorf("%s: expected %d values, got %d", primaryType, len(fields), len(values))
+	}
+
+	members := make([]string, len(fields))
+	for i, f := range fields {
+		members[i] = f.Type + " " + f.Name
+	}
+	typeHash := Keccak256([]byte(primaryType + "(" + strings.Join(members, ",") + ")"))
+
+	encoded := make([][]byte, 0, len(fields)+1)
+	encoded = append(encoded, typeHash)
+	for i, f := range fields {
+		word, err := encodeValue(f.Type, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", primaryType, f.Name, err)
+		}
+		encoded = append(encoded, word)
+	}
+
+	return Keccak256(encoded...), nil
+}
+
+// TypedDataHash returns keccak256("\x19\x01" || domainSeparator || structHash), the digest that gets signed
+func TypedDataHash(domain Domain, structHash []byte) ([]byte, error) {
+	separator, err := domain.Separator()
+	if err != nil {
+		return nil, err
+	}
+	return Keccak256([]byte{0x19, 0x01}, separator, structHash), nil
+}
+
+// encodeValue encodes an atomic EIP-712 value as a 32-byte word
+func encodeValue(typ string, value interface{}) ([]byte, error) {
+	word := make([]byte, 32)
+
+	switch {
+	case typ == "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string")
+		}
+		return Keccak256([]byte(s)), nil
+	case typ == "address":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected address string")
+		}
+		b, err := AddressBytes(s)
+		if err != nil {
+			return nil, err
+		}
+		copy(word[12:], b)
+		return word, nil
+	case typ == "bytes32":
+		b, ok := value.([]byte)
+		if !ok || len(b) != 32 {
+			return nil, fmt.Errorf("expected 32 bytes")
+		}
+		return b, nil
+	case typ == "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected bool")
+		}
+		if b {
+			word[31] = 1
+		}
+		return word, nil
+	case strings.HasPrefix(typ, "uint") || strings.HasPrefix(typ, "int"):
+		var n *big.Int
+		switch v := value.(type) {
+		case int64:
+			n = big.NewInt(v)
+		case uint64:
+			n = new(big.Int).SetUint64(v)
+		case *big.Int:
+			n = v
+		default:
+			return nil, fmt.Errorf("expected integer")
+		}
+		if n.Sign() < 0 {
+			// Two's complement for negative ints
+			n = new(big.Int).Add(n, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		n.FillBytes(word)
+		return word, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", typ)
+	}
+}
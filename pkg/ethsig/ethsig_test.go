@@ -0,0 +1,43 @@
+package ethsig
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivateKeyToAddress(t *testing.T) {
+	cases := map[string]string{
+		"0000000000000000000000000000000000000000000000000000000000000001": "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf",
+		"4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318": "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23",
+	}
+	for key, want := range cases {
+		keyBytes, _ := hex.DecodeString(key)
+		address, err := PrivateKeyToAddress(keyBytes)
+		require.NoError(t, err)
+		assert.Equal(t, want, address)
+	}
+}
+
+func TestSignAndRecover(t *testing.T) {
+	key, err := GeneratePrivateKey()
+	require.NoError(t, err)
+	address, err := PrivateKeyToAddress(key)
+	require.NoError(t, err)
+
+	digest := Keccak256([]byte("hello"))
+	r, s, v, err := Sign(digest, key)
+	require.NoError(t, err)
+
+	recovered, err := RecoverAddress(digest, r, s, v)
+	require.NoError(t, err)
+	assert.Equal(t, address, recovered)
+
+	// A different digest recovers a different address
+	other, err := RecoverAddress(Keccak256([]byte("world")), r, s, v)
+	if err == nil {
+		assert.NotEqual(t, address, other)
+	}
+}
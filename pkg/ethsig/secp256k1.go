@@ -0,0 +1,196 @@
+package ethsig
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+// secp256k1 curve parameters (y² = x³ + 7 over F_p)
+var (
+	curveP, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	curveN, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	curveGx, _ = new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+	curveGy, _ = new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+	curveB     = big.NewInt(7)
+	halfN      = new(big.Int).Rsh(curveN, 1)
+	sqrtExp    = new(big.Int).Rsh(new(big.Int).Add(curveP, big.NewInt(1)), 2)
+)
+
+var (
+	ErrInvalidPrivateKey = errors.New("invalid private key")
+	ErrInvalidSignature  = errors.New("invalid signature")
+)
+
+// point is an affine curve point; a nil X marks the point at infinity
+type point struct {
+	X, Y *big.Int
+}
+
+func (p point) isInfinity() bool {
+	return p.X == nil
+}
+
+func modP(x *big.Int) *big.Int {
+	return x.Mod(x, curveP)
+}
+
+// add returns p + q
+func add(p, q point) point {
+	if p.isInfinity() {
+		return q
+	}
+	if q.isInfinity() {
+		return p
+	}
+
+	var lambda *big.Int
+	if p.X.Cmp(q.X) == 0 {
+		if p.Y.Cmp(q.Y) != 0 || p.Y.Sign() == 0 {
+			return point{}
+		}
+		// Tangent: (3x²) / (2y)
+		num := new(big.Int).Mul(p.X, p.X)
+		num.Mul(num, big.NewInt(3))
+		den := new(big.Int).Lsh(p.Y, 1)
+		lambda = modP(num.Mul(num, den.ModInverse(modP(den), curveP)))
+	} else {
+		// Chord: (y2 - y1) / (x2 - x1)
+		num := new(big.Int).Sub(q.Y, p.Y)
+		den := modP(new(big.Int).Sub(q.X, p.X))
+		lambda = modP(num.Mul(num, den.ModInverse(den, curveP)))
+	}
+
+	x := new(big.Int).Mul(lambda, lambda)
+	x = modP(x.Sub(x.Sub(x, p.X), q.X))
+	y := new(big.Int).Sub(p.X, x)
+	y = modP(y.Sub(y.Mul(y, lambda), p.Y))
+	return point{X: x, Y: y}
+}
+
+// scalarMult returns k * p using double-and-add
+func scalarMult(p point, k *big.Int) point {
+	result := point{}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = add(result, result)
+		if k.Bit(i) == 1 {
+			result = add(result, p)
+		}
+	}
+	return result
+}
+
+func baseMult(k *big.Int) point {
+	return scalarMult(point{X: curveGx, Y: curveGy}, k)
+}
+
+// pointBytes serializes a point as the 64-byte uncompressed X||Y (without the 0x04 prefix)
+func pointBytes(p point) []byte {
+	out := make([]byte, 64)
+	p.X.FillBytes(out[:32])
+	p.Y.FillBytes(out[32:])
+	return out
+}
+
+// GeneratePrivateKey returns a random 32-byte secp256k1 private key
+func GeneratePrivateKey() ([]byte, error) {
+	for {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		d := new(big.Int).SetBytes(key)
+		if d.Sign() > 0 && d.Cmp(curveN) < 0 {
+			return key, nil
+		}
+	}
+}
+
+// PrivateKeyToAddress derives the lowercase 0x-prefixed Ethereum address of a private key
+func PrivateKeyToAddress(privateKey []byte) (string, error) {
+	d := new(big.Int).SetBytes(privateKey)
+	if len(privateKey) != 32 || d.Sign() == 0 || d.Cmp(curveN) >= 0 {
+		return "", ErrInvalidPrivateKey
+	}
+	return publicKeyToAddress(baseMult(d)), nil
+}
+
+// Sign produces an Ethereum-style (r, s, v) signature of a 32-byte digest, with v in {27, 28} and low s
+func Sign(digest, privateKey []byte) (r, s *big.Int, v int, err error) {
+	d := new(big.Int).SetBytes(privateKey)
+	if len(privateKey) != 32 || d.Sign() == 0 || d.Cmp(curveN) >= 0 {
+		return nil, nil, 0, ErrInvalidPrivateKey
+	}
+	e := new(big.Int).SetBytes(digest)
+
+	for {
+		kBytes, err := GeneratePrivateKey()
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		k := new(big.Int).SetBytes(kBytes)
+
+		R := baseMult(k)
+		r = new(big.Int).Mod(R.X, curveN)
+		if r.Sign() == 0 || R.X.Cmp(curveN) >= 0 {
+			continue
+		}
+
+		s = new(big.Int).Mul(r, d)
+		s.Add(s, e)
+		s.Mul(s, new(big.Int).ModInverse(k, curveN))
+		s.Mod(s, curveN)
+		if s.Sign() == 0 {
+			continue
+		}
+
+		recID := int(R.Y.Bit(0))
+		if s.Cmp(halfN) > 0 {
+			s.Sub(curveN, s)
+			recID ^= 1
+		}
+		return r, s, 27 + recID, nil
+	}
+}
+
+// RecoverAddress recovers the lowercase 0x-prefixed address that signed a 32-byte digest
+// v may be given as 27/28 or as the raw recovery id 0/1
+func RecoverAddress(digest []byte, r, s *big.Int, v int) (string, error) {
+	if v >= 27 {
+		v -= 27
+	}
+	if v != 0 && v != 1 {
+		return "", ErrInvalidSignature
+	}
+	if r.Sign() <= 0 || r.Cmp(curveN) >= 0 || s.Sign() <= 0 || s.Cmp(curveN) >= 0 {
+		return "", ErrInvalidSignature
+	}
+
+	// Lift x = r onto the curve and pick the y with the parity given by v
+	x := new(big.Int).Set(r)
+	y2 := new(big.Int).Mul(x, x)
+	y2.Mul(y2, x)
+	y2 = modP(y2.Add(y2, curveB))
+	y := new(big.Int).Exp(y2, sqrtExp, curveP)
+	if modP(new(big.Int).Mul(y, y)).Cmp(y2) != 0 {
+		return "", ErrInvalidSignature
+	}
+	if int(y.Bit(0)) != v {
+		y.Sub(curveP, y)
+	}
+	R := point{X: x, Y: y}
+
+	// Q = r⁻¹ (sR - eG)
+	rInv := new(big.Int).ModInverse(r, curveN)
+	e := new(big.Int).SetBytes(digest)
+	u1 := new(big.Int).Neg(e)
+	u1.Mul(u1, rInv).Mod(u1, curveN)
+	u2 := new(big.Int).Mul(s, rInv)
+	u2.Mod(u2, curveN)
+
+	Q := add(baseMult(u1), scalarMult(R, u2))
+	if Q.isInfinity() {
+		return "", ErrInvalidSignature
+	}
+	return publicKeyToAddress(Q), nil
+}
@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"strings"
 
+	"github.com/ccxt-simulator/pkg/ethsig"
 	"github.com/google/uuid"
 )
 
@@ -129,26 +130,22 @@ func generateBitgetKeys() (*APIKeySet, error) {
 }
 
 // generateHyperliquidKeys generates Hyperliquid-style API keys
-// API Key: Ethereum address format (42 characters, 0x prefix)
-// API Secret: 64 characters hex (private key format)
+// API Key: Ethereum wallet address derived from the private key (42 characters, 0x prefix)
+// API Secret: 64 characters hex secp256k1 private key, usable directly by Hyperliquid SDKs
 func generateHyperliquidKeys() (*APIKeySet, error) {
-	// Generate random 20 bytes for address
-	addrBytes := make([]byte, 20)
-	if _, err := rand.Read(addrBytes); err != nil {
+	privateKey, err := ethsig.GeneratePrivateKey()
+	if err != nil {
 		return nil, err
 	}
-	apiKey := "0x" + hex.EncodeToString(addrBytes)
 
-	// Generate random 32 bytes for private key
-	secretBytes := make([]byte, 32)
-	if _, err := rand.Read(secretBytes); err != nil {
+	address, err := ethsig.PrivateKeyToAddress(privateKey)
+	if err != nil {
 		return nil, err
 	}
-	apiSecret := hex.EncodeToString(secretBytes)
 
 	return &APIKeySet{
-		APIKey:    apiKey,
-		APISecret: apiSecret,
+		APIKey:    address,
+		APISecret: hex.EncodeToString(privateKey),
 	}, nil
 }
 
@@ -167,3 +164,9 @@ func randomString(length int, charset string) (string, error) {
 
 	return string(result), nil
 }
+
+// GenerateListenKey generates a user data stream listen key
+// Listen Key: 64 characters alphanumeric
+func GenerateListenKey() (string, error) {
+	return randomString(64, alphaNumeric)
+}
@@ -0,0 +1,210 @@
+// Package msgpack encodes JSON documents as MessagePack, preserving object key order.
+// The output matches Python's msgpack.packb for the same data, which is what
+// wallet-signed exchange actions are hashed over.
+package msgpack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FromJSON re-encodes a JSON document as MessagePack
+// Object keys keep their document order; integer literals use the smallest int format and
+// other numbers are encoded as float64
+func FromJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var buf bytes.Buffer
+	if err := encodeNext(dec, &buf); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("msgpack: trailing data after JSON value")
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeNext(dec *json.Decoder, buf *bytes.Buffer) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			return encodeObject(dec, buf)
+		case '[':
+			return encodeArray(dec, buf)
+		default:
+			return fmt.Errorf("msgpack: unexpected delimiter %q", v)
+		}
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if v {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case string:
+		writeString(buf, v)
+	case json.Number:
+		return writeNumber(buf, v)
+	default:
+		return fmt.Errorf("msgpack: unexpected token %v", tok)
+	}
+	return nil
+}
+
+func encodeObject(dec *json.Decoder, buf *bytes.Buffer) error {
+	var body bytes.Buffer
+	n := 0
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("msgpack: object key is not a string")
+		}
+		writeString(&body, key)
+		if err := encodeNext(dec, &body); err != nil {
+			return err
+		}
+		n++
+	}
+	if _, err := dec.Token(); err != nil { // closing '}'
+		return err
+	}
+
+	switch {
+	case n < 16:
+		buf.WriteByte(0x80 | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xde)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(0xdf)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+	buf.Write(body.Bytes())
+	return nil
+}
+
+func encodeArray(dec *json.Decoder, buf *bytes.Buffer) error {
+	var body bytes.Buffer
+	n := 0
+	for dec.More() {
+		if err := encodeNext(dec, &body); err != nil {
+			return err
+		}
+		n++
+	}
+	if _, err := dec.Token(); err != nil { // closing ']'
+		return err
+	}
+
+	switch {
+	case n < 16:
+		buf.WriteByte(0x90 | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xdc)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(0xdd)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+	buf.Write(body.Bytes())
+	return nil
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	n := len(s)
+	switch {
+	case n < 32:
+		buf.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(0xd9)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xda)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(0xdb)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+	buf.WriteString(s)
+}
+
+func writeNumber(buf *bytes.Buffer, num json.Number) error {
+	s := num.String()
+	if !strings.ContainsAny(s, ".eE") {
+		if strings.HasPrefix(s, "-") {
+			if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+				writeInt(buf, i)
+				return nil
+			}
+		} else if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			writeUint(buf, u)
+			return nil
+		}
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("msgpack: invalid number %s", s)
+	}
+	buf.WriteByte(0xcb)
+	binary.Write(buf, binary.BigEndian, math.Float64bits(f))
+	return nil
+}
+
+func writeUint(buf *bytes.Buffer, u uint64) {
+	switch {
+	case u <= 0x7f:
+		buf.WriteByte(byte(u))
+	case u <= math.MaxUint8:
+		buf.WriteByte(0xcc)
+		buf.WriteByte(byte(u))
+	case u <= math.MaxUint16:
+		buf.WriteByte(0xcd)
+		binary.Write(buf, binary.BigEndian, uint16(u))
+	case u <= math.MaxUint32:
+		buf.WriteByte(0xce)
+		binary.Write(buf, binary.BigEndian, uint32(u))
+	default:
+		buf.WriteByte(0xcf)
+		binary.Write(buf, binary.BigEndian, u)
+	}
+}
+
+func writeInt(buf *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0:
+		writeUint(buf, uint64(i))
+	case i >= -32:
+		buf.WriteByte(byte(i))
+	case i >= math.MinInt8:
+		buf.WriteByte(0xd0)
+		buf.WriteByte(byte(i))
+	case i >= math.MinInt16:
+		buf.WriteByte(0xd1)
+		binary.Write(buf, binary.BigEndian, int16(i))
+	case i >= math.MinInt32:
+		buf.WriteByte(0xd2)
+		binary.Write(buf, binary.BigEndian, int32(i))
+	default:
+		buf.WriteByte(0xd3)
+		binary.Write(buf, binary.BigEndian, i)
+	}
+}